@@ -0,0 +1,51 @@
+package async
+
+import (
+	"context"
+	"fmt"
+)
+
+// Cancellable wraps fn so the operation ends as soon as its context is
+// cancelled or reaches MaxLifetime, returning ctx.Err(), even if fn never
+// checks ctx.Done(). fn keeps running in the background until it returns
+// and its result is discarded, so this bounds how long the operation is
+// reported as running, not the work fn does; prefer RunWithHeartbeat for
+// work that can be split into steps.
+func Cancellable(fn OperationFunc) OperationFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		type outcome struct {
+			result interface{}
+			err    error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			result, err := fn(ctx)
+			done <- outcome{result, err}
+		}()
+		
+		select {
+		case o := <-done:
+			return o.result, o.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// RunWithHeartbeat runs step for i = 0..n-1, checking ctx between steps so a
+// cancelled operation stops before its next step, and reporting progress
+// i/n via ReportProgress after each one. It returns ctx.Err() if cancelled
+// before a step, or the first error a step returns; a run whose every step
+// succeeded returns nil even if ctx ends afterwards.
+func RunWithHeartbeat(ctx context.Context, n int, step func(ctx context.Context, i int) error) error {
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := step(ctx, i); err != nil {
+			return fmt.Errorf("step %d: %w", i, err)
+		}
+		ReportProgress(ctx, float64(i+1), float64(n), "")
+	}
+	return nil
+}
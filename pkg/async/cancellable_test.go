@@ -0,0 +1,117 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test a wrapped operation that ignores its context still stops on cancel
+func TestCancellable_StopsPromptly(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	release := make(chan struct{})
+	defer close(release)
+	stubborn := func(ctx context.Context) (interface{}, error) {
+		<-release // never looks at ctx
+		return "too late", nil
+	}
+	
+	result, err := executor.Execute(context.Background(), Cancellable(stubborn), ExecuteOptions{Type: "stubborn", Timeout: 10 * time.Millisecond})
+	if err != nil || result.Status != StatusRunning {
+		t.Fatalf("execute: result %+v, err %v", result, err)
+	}
+	op, err := executor.registry.Get(result.OperationID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	
+	if err := executor.Cancel(result.OperationID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	select {
+	case <-op.CompleteCh:
+	case <-time.After(time.Second):
+		t.Fatal("operation still running after cancel")
+	}
+}
+
+// Test RunWithHeartbeat stops between steps once cancelled
+func TestRunWithHeartbeat_StopsBetweenSteps(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	
+	var steps int32
+	err := RunWithHeartbeat(ctx, 100, func(ctx context.Context, i int) error {
+		if atomic.AddInt32(&steps, 1) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n := atomic.LoadInt32(&steps); n != 3 {
+		t.Errorf("ran %d steps, want 3", n)
+	}
+}
+
+// Test a run whose last step cancels ctx still counts as complete
+func TestRunWithHeartbeat_CancelAfterLastStep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	
+	err := RunWithHeartbeat(ctx, 3, func(ctx context.Context, i int) error {
+		if i == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("expected nil after every step succeeded, got %v", err)
+	}
+}
+
+// Test RunWithHeartbeat reports progress and surfaces step errors
+func TestRunWithHeartbeat_ProgressAndErrors(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	op := func(ctx context.Context) (interface{}, error) {
+		return nil, RunWithHeartbeat(ctx, 3, func(ctx context.Context, i int) error {
+			if i == 2 {
+				return errors.New("disk full")
+			}
+			return nil
+		})
+	}
+	result, err := executor.Execute(context.Background(), op, ExecuteOptions{Type: "steps", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if result.Status != StatusFailed || result.Error != "step 2: disk full" {
+		t.Errorf("result = %+v, want failure from step 2", result)
+	}
+	
+	progress := 0
+	for {
+		select {
+		case ev := <-executor.Events():
+			if ev.Type == EventProgress {
+				progress++
+				if ev.Total != 3 {
+					t.Errorf("progress total = %v, want 3", ev.Total)
+				}
+			}
+			continue
+		default:
+		}
+		break
+	}
+	if progress != 2 {
+		t.Errorf("got %d progress events, want 2 for the steps that succeeded", progress)
+	}
+}
@@ -0,0 +1,83 @@
+package async
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// EventType identifies an operation lifecycle transition
+type EventType string
+
+const (
+	EventCreated   EventType = "created"
+	EventProgress  EventType = "progress"
+	EventCompleted EventType = "completed"
+	EventFailed    EventType = "failed"
+	EventCancelled EventType = "cancelled"
+	EventReaped    EventType = "reaped"
+)
+
+// defaultEventBuffer is the Events() channel capacity when
+// ExecutorConfig.EventBufferSize is zero or negative
+const defaultEventBuffer = 256
+
+// OperationEvent describes one lifecycle transition of an operation.
+// Result is set for completed events, Error for failed ones, Progress,
+// Total and Message for progress events, and Message for cancelled events
+// that have a reason (such as SupersededReason).
+type OperationEvent struct {
+	Type          EventType
+	OperationID   string
+	OperationType string
+	Time          time.Time
+	Result        interface{}
+	Error         string
+	Progress      float64
+	Total         float64
+	Message       string
+}
+
+// Events returns the executor's lifecycle event stream. Sends never block:
+// when the buffer is full the event is dropped, so a slow consumer cannot
+// stall operations. The channel is never closed.
+func (e *OperationExecutor) Events() <-chan OperationEvent {
+	return e.events
+}
+
+// emit publishes an event for op without blocking
+func (e *OperationExecutor) emit(eventType EventType, op *Operation, fill func(*OperationEvent)) {
+	ev := OperationEvent{
+		Type:          eventType,
+		OperationID:   op.ID,
+		OperationType: op.Type,
+		Time:          timeNow().Now(),
+	}
+	if fill != nil {
+		fill(&ev)
+	}
+	
+	select {
+	case e.events <- ev:
+	default:
+		log.Printf("[ASYNC] Event buffer full, dropping %s event for operation %s", eventType, op.ID)
+	}
+}
+
+// emitEnd publishes the event for op's transition to the terminal state and
+// tells op's owner about that state
+func (e *OperationExecutor) emitEnd(eventType EventType, op *Operation, state opState, fill func(*OperationEvent)) {
+	e.emit(eventType, op, fill)
+	e.notifyOwner(op, state)
+}
+
+type progressReporterKey struct{}
+
+// ReportProgress publishes a progress event for the operation running under
+// ctx. total is 0 when the amount of work is unknown. Outside an operation
+// started by an executor it does nothing.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	if report, ok := ctx.Value(progressReporterKey{}).(func(float64, float64, string)); ok {
+		report(progress, total, message)
+	}
+}
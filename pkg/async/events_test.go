@@ -0,0 +1,147 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitForEvent reads events until one of the wanted type arrives for opID
+func waitForEvent(t *testing.T, executor *OperationExecutor, opID string, want EventType) OperationEvent {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case ev := <-executor.Events():
+			if ev.OperationID == opID && ev.Type == want {
+				return ev
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for %s event on operation %s", want, opID)
+		}
+	}
+}
+
+// Test created, progress and completed events for a successful operation
+func TestEvents_Completed(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	release := make(chan struct{})
+	result, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		ReportProgress(ctx, 1, 2, "halfway")
+		<-release
+		return "done", nil
+	}, ExecuteOptions{Type: "evented", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	opID := result.OperationID
+	
+	created := waitForEvent(t, executor, opID, EventCreated)
+	if created.OperationType != "evented" {
+		t.Errorf("created.OperationType = %q, want evented", created.OperationType)
+	}
+	
+	progress := waitForEvent(t, executor, opID, EventProgress)
+	if progress.Progress != 1 || progress.Total != 2 || progress.Message != "halfway" {
+		t.Errorf("unexpected progress event %+v", progress)
+	}
+	
+	close(release)
+	completed := waitForEvent(t, executor, opID, EventCompleted)
+	if completed.Result != "done" {
+		t.Errorf("completed.Result = %v, want done", completed.Result)
+	}
+}
+
+// Test a failed event carries the error
+func TestEvents_Failed(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("upstream down")
+	}, ExecuteOptions{Type: "evented"})
+	
+	// The result of a fast failure carries no ID; take it from the created event
+	created := <-executor.Events()
+	if created.Type != EventCreated {
+		t.Fatalf("first event = %s, want created", created.Type)
+	}
+	failed := waitForEvent(t, executor, created.OperationID, EventFailed)
+	if failed.Error != "upstream down" {
+		t.Errorf("failed.Error = %q, want upstream down", failed.Error)
+	}
+}
+
+// Test cancelling emits cancelled and no failed event, then reaping emits reaped
+func TestEvents_CancelledAndReaped(t *testing.T) {
+	executor := NewExecutor(ExecutorConfig{
+		DefaultTimeout:  10 * time.Millisecond,
+		RetentionPeriod: 10 * time.Millisecond,
+		CleanupInterval: time.Hour,
+	})
+	defer executor.Stop()
+	
+	result, _ := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, ExecuteOptions{Type: "evented"})
+	opID := result.OperationID
+	
+	if err := executor.Cancel(opID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	waitForEvent(t, executor, opID, EventCancelled)
+	
+	time.Sleep(30 * time.Millisecond)
+	executor.Cleanup()
+	waitForEvent(t, executor, opID, EventReaped)
+	
+	// Drain what is left: a cancelled operation must not also report failure
+	for {
+		select {
+		case ev := <-executor.Events():
+			if ev.OperationID == opID && ev.Type == EventFailed {
+				t.Errorf("cancelled operation also emitted a failed event")
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Test a full buffer drops events instead of blocking operations
+func TestEvents_NonBlocking(t *testing.T) {
+	executor := NewExecutor(ExecutorConfig{DefaultTimeout: time.Second, EventBufferSize: 1})
+	defer executor.Stop()
+	
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+				ReportProgress(ctx, 1, 1, "")
+				return nil, nil
+			}, ExecuteOptions{Type: "noisy"})
+		}
+	}()
+	
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("operations stalled on an unread event channel")
+	}
+}
+
+// Test a negative buffer size falls back to the default
+func TestEvents_NegativeBufferUsesDefault(t *testing.T) {
+	executor := NewExecutor(ExecutorConfig{EventBufferSize: -1})
+	defer executor.Stop()
+	
+	if got := cap(executor.Events()); got != defaultEventBuffer {
+		t.Errorf("event buffer = %d, want %d", got, defaultEventBuffer)
+	}
+}
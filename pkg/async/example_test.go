@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gomcpgo/mcp/pkg/async"
+	"github.com/gomcpgo/mcp/pkg/handler"
 	"github.com/gomcpgo/mcp/pkg/protocol"
 )
 
@@ -21,131 +22,43 @@ func Example() {
 	executor := async.NewExecutor(config)
 	defer executor.Stop()
 
-	// Example: Image generation handler
-	handleGenerateImage := func(ctx context.Context, args map[string]interface{}) (*protocol.CallToolResponse, error) {
-		// Extract parameters
-		prompt := args["prompt"].(string)
-
-		// Execute the long-running operation
-		result, err := executor.Execute(ctx,
-			func(opCtx context.Context) (interface{}, error) {
-				// Simulate long-running image generation
-				select {
-				case <-time.After(20 * time.Second):
-					return map[string]string{
-						"image_path": "/tmp/generated.png",
-						"prompt":     prompt,
-					}, nil
-				case <-opCtx.Done():
-					return nil, opCtx.Err()
-				}
-			},
-			async.ExecuteOptions{
-				Type:    "generate_image",
-				Timeout: 15 * time.Second, // Return "processing" after 15 seconds
-			},
-		)
-
-		if err != nil {
-			return nil, err
-		}
-
-		// Check if operation is still running
-		if result.Status == async.StatusRunning {
-			// Return processing response
-			return &protocol.CallToolResponse{
-				Content: []protocol.ToolContent{{
-					Type: "text",
-					Text: fmt.Sprintf(`{
-						"status": "processing",
-						"operation_id": "%s",
-						"message": "%s"
-					}`, result.OperationID, result.Message),
-				}},
-			}, nil
-		}
-
-		// Operation completed immediately
-		if result.Status == async.StatusFailed {
-			return &protocol.CallToolResponse{
-				Content: []protocol.ToolContent{{
-					Type: "text",
-					Text: fmt.Sprintf(`{"error": "%s"}`, result.Error),
-				}},
+	// Example: Image generation tool. WrapTool runs it on the executor and
+	// answers with a "processing" status and operation ID if it is still
+	// running after 15 seconds.
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{
+		Name:        "generate_image",
+		Description: "Generate an image from a text prompt",
+	}, async.WrapTool(executor, "generate_image", func(ctx context.Context) (interface{}, error) {
+		req, _ := async.ToolRequestFromContext(ctx)
+		prompt := req.GetString("prompt", "")
+
+		// Simulate long-running image generation
+		select {
+		case <-time.After(20 * time.Second):
+			return map[string]string{
+				"image_path": "/tmp/generated.png",
+				"prompt":     prompt,
 			}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
+	}, 15*time.Second))
 
-		// Format success response
-		imageData := result.Result.(map[string]string)
-		return &protocol.CallToolResponse{
-			Content: []protocol.ToolContent{{
-				Type: "text",
-				Text: fmt.Sprintf(`{
-					"status": "completed",
-					"image_path": "%s"
-				}`, imageData["image_path"]),
-			}},
-		}, nil
-	}
+	// The built-in continue_operation and cancel_operation tools let the
+	// client collect the result later, even from a new connection
+	executor.RegisterTools(router)
 
-	// Example: Continue operation handler
-	handleContinueOperation := func(ctx context.Context, args map[string]interface{}) (*protocol.CallToolResponse, error) {
-		opID := args["operation_id"].(string)
-		waitTime := 30 * time.Second
+	// Register the router with your MCP server
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(router)
 
-		// Check/wait for operation status
-		result, err := executor.Continue(ctx, opID, waitTime)
-		if err != nil {
-			return &protocol.CallToolResponse{
-				Content: []protocol.ToolContent{{
-					Type: "text",
-					Text: fmt.Sprintf(`{"error": "%s"}`, err.Error()),
-				}},
-			}, nil
-		}
-
-		// Check status
-		switch result.Status {
-		case async.StatusRunning:
-			return &protocol.CallToolResponse{
-				Content: []protocol.ToolContent{{
-					Type: "text",
-					Text: fmt.Sprintf(`{
-						"status": "processing",
-						"operation_id": "%s",
-						"message": "%s"
-					}`, result.OperationID, result.Message),
-				}},
-			}, nil
-
-		case async.StatusFailed:
-			return &protocol.CallToolResponse{
-				Content: []protocol.ToolContent{{
-					Type: "text",
-					Text: fmt.Sprintf(`{"error": "%s"}`, result.Error),
-				}},
-			}, nil
-
-		case async.StatusCompleted:
-			imageData := result.Result.(map[string]string)
-			return &protocol.CallToolResponse{
-				Content: []protocol.ToolContent{{
-					Type: "text",
-					Text: fmt.Sprintf(`{
-						"status": "completed",
-						"image_path": "%s"
-					}`, imageData["image_path"]),
-				}},
-			}, nil
-		}
-
-		return nil, fmt.Errorf("unexpected status: %s", result.Status)
+	list, _ := router.ListTools(context.Background())
+	for _, tool := range list.Tools {
+		fmt.Println(tool.Name)
 	}
-
-	// Register these handlers with your MCP server
-	_ = handleGenerateImage
-	_ = handleContinueOperation
-	
-	fmt.Println("Executor created and handlers defined")
-	// Output: Executor created and handlers defined
-}
\ No newline at end of file
+	// Output:
+	// generate_image
+	// continue_operation
+	// cancel_operation
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 )
 
@@ -11,6 +12,11 @@ import (
 type OperationExecutor struct {
 	registry *OperationRegistry
 	config   ExecutorConfig
+	opWG     sync.WaitGroup // Tracks running operation goroutines
+	events   chan OperationEvent
+	
+	notifyMu sync.RWMutex
+	notifier Notifier
 }
 
 // NewExecutor creates a new operation executor
@@ -29,14 +35,45 @@ func NewExecutor(config ExecutorConfig) *OperationExecutor {
 		config.CleanupInterval = 1 * time.Minute
 	}
 	
-	return &OperationExecutor{
-		registry: NewRegistry(config),
-		config:   config,
+	if config.EventBufferSize <= 0 {
+		config.EventBufferSize = defaultEventBuffer
+	}
+	
+	e := &OperationExecutor{
+		config: config,
+		events: make(chan OperationEvent, config.EventBufferSize),
 	}
+	e.registry = newRegistry(config, func(eventType EventType, op *Operation, ended *opState, fill func(*OperationEvent)) {
+		if ended != nil {
+			e.emitEnd(eventType, op, *ended, fill)
+			return
+		}
+		e.emit(eventType, op, fill)
+	})
+	return e
 }
 
 // Execute runs an operation with timeout management
 func (e *OperationExecutor) Execute(ctx context.Context, operation OperationFunc, opts ExecuteOptions) (*ExecuteResult, error) {
+	return e.execute(ctx, operation, opts, false)
+}
+
+// Supersede runs operation like Execute under the shared key, first
+// cancelling any running operation of the same owner with that key, e.g. a
+// search made obsolete by a newer query. The old operations end with reason
+// SupersededReason. Swapping is atomic: concurrent calls leave exactly one
+// operation running for the key. The key doubles as the DedupKey, so a later
+// Execute with it attaches to the new operation.
+func (e *OperationExecutor) Supersede(ctx context.Context, key string, operation OperationFunc, opts ExecuteOptions) (*ExecuteResult, error) {
+	if key == "" {
+		return nil, fmt.Errorf("supersede: empty key")
+	}
+	opts.DedupKey = key
+	return e.execute(ctx, operation, opts, true)
+}
+
+// execute implements Execute and, when supersede is set, Supersede
+func (e *OperationExecutor) execute(ctx context.Context, operation OperationFunc, opts ExecuteOptions, supersede bool) (*ExecuteResult, error) {
 	// Generate operation ID
 	opID := generateID()
 	log.Printf("[ASYNC] Execute called for operation type: %s, generated ID: %s", opts.Type, opID)
@@ -47,80 +84,159 @@ func (e *OperationExecutor) Execute(ctx context.Context, operation OperationFunc
 		timeout = e.config.DefaultTimeout
 	}
 	
-	// Create operation context with max lifetime
-	opCtx, opCancel := context.WithTimeout(context.Background(), e.config.MaxLifetime)
+	// Create operation context with max lifetime, carrying over any
+	// requested values from the caller's context
+	baseCtx := context.Background()
+	for _, key := range opts.PropagateValues {
+		if value := ctx.Value(key); value != nil {
+			baseCtx = context.WithValue(baseCtx, key, value)
+		}
+	}
+	opCtx, opCancel := context.WithTimeout(baseCtx, e.config.MaxLifetime)
+	
+	owner := opts.Owner
+	if owner == "" {
+		owner = OwnerFromContext(ctx)
+	}
 	
 	// Create operation record
 	op := &Operation{
 		ID:         opID,
 		Type:       opts.Type,
+		DedupKey:   opts.DedupKey,
+		Tags:       opts.Tags,
+		Owner:      owner,
 		Status:     StatusRunning,
 		StartTime:  timeNow().Now(),
 		CompleteCh: make(chan struct{}),
 		cancelFunc: opCancel,
 	}
 	
-	// Register the operation
-	e.registry.Add(op)
+	// Register the operation, attaching to an already-running duplicate if
+	// one exists for the same DedupKey or, when superseding, cancelling it
+	existing := op
+	if supersede {
+		superseded, err := e.registry.supersede(op, SupersededReason)
+		if err != nil {
+			opCancel()
+			return nil, err
+		}
+		for _, old := range superseded {
+			log.Printf("[ASYNC] Operation %s superseded by %s (key: %s)", old.op.ID, opID, opts.DedupKey)
+			e.emitEnd(EventCancelled, old.op, old.state, func(ev *OperationEvent) { ev.Message = SupersededReason })
+		}
+		if len(superseded) > 0 {
+			e.registry.evictExcess()
+		}
+	} else {
+		var err error
+		if existing, err = e.registry.addOrAttach(op); err != nil {
+			opCancel()
+			return nil, err
+		}
+	}
+	if existing != op {
+		opCancel()
+		log.Printf("[ASYNC] Operation type: %s attached to running operation %s (dedup key: %s)", opts.Type, existing.ID, opts.DedupKey)
+		return e.await(ctx, existing, timeout), nil
+	}
 	log.Printf("[ASYNC] Operation registered with ID: %s, type: %s", opID, opts.Type)
+	e.emit(EventCreated, op, nil)
+	
+	// Let the operation publish progress events via ReportProgress
+	opCtx = context.WithValue(opCtx, progressReporterKey{}, func(progress, total float64, message string) {
+		e.emit(EventProgress, op, func(ev *OperationEvent) {
+			ev.Progress = progress
+			ev.Total = total
+			ev.Message = message
+		})
+	})
+	
+	// Let the operation publish incremental results via AppendResult
+	opCtx = context.WithValue(opCtx, partialResultKey{}, func(v interface{}) {
+		e.registry.appendPartial(op, v)
+	})
 	
 	// Start operation in goroutine
+	e.opWG.Add(1)
 	go func() {
+		defer e.opWG.Done()
 		defer close(op.CompleteCh)
 		defer opCancel()
 		
 		// Run the operation
 		result, err := operation(opCtx)
 		
-		// Update operation status
-		op.EndTime = timeNow().Now()
+		// Update operation status; a cancelled, superseded or reaped
+		// operation already reported its end and keeps that outcome
+		var state opState
+		var ended bool
 		if err != nil {
-			op.Status = StatusFailed
-			op.Error = err
+			state, ended = e.registry.finish(op, StatusFailed, nil, err)
 		} else {
-			op.Status = StatusCompleted
-			op.Result = result
+			state, ended = e.registry.finish(op, StatusCompleted, result, nil)
+		}
+		if !ended {
+			return
+		}
+		e.registry.evictExcess()
+		
+		if err != nil {
+			e.emitEnd(EventFailed, op, state, func(ev *OperationEvent) { ev.Error = err.Error() })
+		} else {
+			e.emitEnd(EventCompleted, op, state, func(ev *OperationEvent) { ev.Result = result })
 		}
 	}()
 	
-	// Wait for completion or timeout
+	return e.await(ctx, op, timeout), nil
+}
+
+// await waits for op to complete, the timeout to elapse or ctx to be done,
+// and builds the corresponding ExecuteResult
+func (e *OperationExecutor) await(ctx context.Context, op *Operation, timeout time.Duration) *ExecuteResult {
 	select {
 	case <-op.CompleteCh:
 		// Operation completed
-		if op.Error != nil {
+		state := e.registry.state(op)
+		if state.Error != nil {
 			return &ExecuteResult{
-				Status: StatusFailed,
-				Error:  op.Error.Error(),
-			}, nil
+				Status: state.Status,
+				Error:  state.Error.Error(),
+			}
 		}
 		return &ExecuteResult{
 			Status: StatusCompleted,
-			Result: op.Result,
-		}, nil
+			Result: state.Result,
+		}
 		
 	case <-timeNow().After(timeout):
 		// Timeout - return processing status
-		log.Printf("[ASYNC] Operation %s timed out after %v, returning processing status", opID, timeout)
+		log.Printf("[ASYNC] Operation %s timed out after %v, returning processing status", op.ID, timeout)
 		return &ExecuteResult{
 			Status:        StatusRunning,
-			OperationID:   opID,
-			OperationType: opts.Type,
-			Message:       fmt.Sprintf("Operation in progress. Use continue_operation with operation_id='%s' to check status.", opID),
-		}, nil
+			OperationID:   op.ID,
+			OperationType: op.Type,
+			Message:       fmt.Sprintf("Operation in progress. Use continue_operation with operation_id='%s' to check status.", op.ID),
+		}
 		
 	case <-ctx.Done():
 		// MCP context cancelled - but don't cancel the operation
 		// The operation continues in the background
 		return &ExecuteResult{
 			Status:        StatusRunning,
-			OperationID:   opID,
-			OperationType: opts.Type,
+			OperationID:   op.ID,
+			OperationType: op.Type,
 			Message:       "Request cancelled, but operation continues. Use continue_operation to check status.",
-		}, nil
+		}
 	}
 }
 
-// Continue checks or waits for operation completion
+// Continue checks or waits for operation completion. It waits for at most
+// the shorter of waitTime and the time left until ctx's deadline. Either way
+// the wait ends with a pollable StatusRunning result rather than an error, so
+// a client with a tight deadline can simply call Continue again. Only an
+// explicit cancellation of ctx is reported as an error. An operation owned
+// by someone other than ctx's owner is refused with ErrNotOwner.
 func (e *OperationExecutor) Continue(ctx context.Context, operationID string, waitTime time.Duration) (*ContinueResult, error) {
 	log.Printf("[ASYNC] Continue called for operation ID: %s, waitTime: %v", operationID, waitTime)
 	
@@ -130,63 +246,79 @@ func (e *OperationExecutor) Continue(ctx context.Context, operationID string, wa
 		log.Printf("[ASYNC] Operation %s not found in registry: %v", operationID, err)
 		return nil, err
 	}
+	if err := checkOwner(ctx, op); err != nil {
+		return nil, err
+	}
 	
-	log.Printf("[ASYNC] Found operation %s with status: %s, type: %s", operationID, op.Status, op.Type)
+	state := e.registry.state(op)
+	log.Printf("[ASYNC] Found operation %s with status: %s, type: %s", operationID, state.Status, op.Type)
 	
 	// Check current status
-	if op.Status != StatusRunning {
+	if state.Status.IsTerminal() {
 		// Operation already completed
-		if op.Error != nil {
-			return &ContinueResult{
-				Status:        StatusFailed,
-				OperationID:   operationID,
-				OperationType: op.Type,
-				Error:         op.Error.Error(),
-			}, nil
-		}
-		return &ContinueResult{
-			Status:        StatusCompleted,
-			OperationID:   operationID,
-			OperationType: op.Type,
-			Result:        op.Result,
-		}, nil
+		return e.finished(op, state), nil
+	}
+	
+	// Never wait past the caller's deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := deadline.Sub(timeNow().Now()); remaining < waitTime {
+			waitTime = remaining
+		}
+	}
+	if waitTime < 0 {
+		waitTime = 0
 	}
 	
 	// Wait for completion or timeout
 	select {
 	case <-op.CompleteCh:
 		// Operation completed
-		if op.Error != nil {
-			return &ContinueResult{
-				Status:        StatusFailed,
-				OperationID:   operationID,
-				OperationType: op.Type,
-				Error:         op.Error.Error(),
-			}, nil
-		}
-		return &ContinueResult{
-			Status:        StatusCompleted,
-			OperationID:   operationID,
-			OperationType: op.Type,
-			Result:        op.Result,
-		}, nil
+		return e.finished(op, e.registry.state(op)), nil
 		
 	case <-timeNow().After(waitTime):
 		// Still running
-		elapsed := timeNow().Now().Sub(op.StartTime)
-		return &ContinueResult{
-			Status:        StatusRunning,
-			OperationID:   operationID,
-			OperationType: op.Type,
-			Message:       fmt.Sprintf("Operation still in progress (elapsed: %v). Continue checking.", elapsed.Round(time.Second)),
-		}, nil
+		return e.stillRunning(op), nil
 		
 	case <-ctx.Done():
+		// Deadline reached: the client can still poll
+		if ctx.Err() == context.DeadlineExceeded {
+			return e.stillRunning(op), nil
+		}
 		// Context cancelled
 		return nil, ctx.Err()
 	}
 }
 
+// finished builds the Continue result for an operation that ended in state
+func (e *OperationExecutor) finished(op *Operation, state opState) *ContinueResult {
+	result := &ContinueResult{
+		Status:         StatusCompleted,
+		OperationID:    op.ID,
+		OperationType:  op.Type,
+		PartialResults: e.registry.partialResults(op),
+	}
+	if state.Error != nil {
+		result.Status = state.Status
+		result.Error = state.Error.Error()
+	} else {
+		result.Result = state.Result
+	}
+	return result
+}
+
+// stillRunning builds the Continue result for an operation that has not
+// finished yet.
+func (e *OperationExecutor) stillRunning(op *Operation) *ContinueResult {
+	elapsed := timeNow().Now().Sub(op.StartTime)
+	return &ContinueResult{
+		Status:         StatusRunning,
+		OperationID:    op.ID,
+		OperationType:  op.Type,
+		Message:        fmt.Sprintf("Operation still in progress (elapsed: %v). Continue checking.", elapsed.Round(time.Second)),
+		PartialResults: e.registry.partialResults(op),
+	}
+}
+
 // Cancel cancels a running operation
 func (e *OperationExecutor) Cancel(operationID string) error {
 	op, err := e.registry.Get(operationID)
@@ -194,32 +326,117 @@ func (e *OperationExecutor) Cancel(operationID string) error {
 		return err
 	}
 	
-	if op.Status != StatusRunning {
-		return fmt.Errorf("operation %s is not running (status: %s)", operationID, op.Status)
+	state, ok := e.registry.finish(op, StatusCancelled, nil, fmt.Errorf("operation cancelled"))
+	if !ok {
+		return fmt.Errorf("operation %s is not running (status: %s)", operationID, e.registry.state(op).Status)
 	}
 	
 	// Cancel the operation
 	if op.cancelFunc != nil {
 		op.cancelFunc()
-		op.Status = StatusFailed
-		op.Error = fmt.Errorf("operation cancelled")
-		op.EndTime = timeNow().Now()
 	}
+	e.emitEnd(EventCancelled, op, state, nil)
+	e.registry.evictExcess()
 	
 	return nil
 }
 
+// CancelFor cancels a running operation on behalf of the caller in ctx,
+// refusing with ErrNotOwner an operation owned by someone else. Cancel
+// itself is unrestricted.
+func (e *OperationExecutor) CancelFor(ctx context.Context, operationID string) error {
+	op, err := e.registry.Get(operationID)
+	if err != nil {
+		return err
+	}
+	if err := checkOwner(ctx, op); err != nil {
+		return err
+	}
+	return e.Cancel(operationID)
+}
+
+// CancelByType cancels every running operation of the given type and returns
+// how many were cancelled
+func (e *OperationExecutor) CancelByType(opType string) int {
+	return e.cancelMatching(func(op *Operation) bool {
+		return op.Type == opType
+	})
+}
+
+// CancelByTag cancels every running operation tagged key=value and returns
+// how many were cancelled
+func (e *OperationExecutor) CancelByTag(key, value string) int {
+	return e.cancelMatching(func(op *Operation) bool {
+		v, ok := op.Tags[key]
+		return ok && v == value
+	})
+}
+
+// CancelByOwner cancels every running operation owned by owner, e.g. when
+// the session it belongs to closes, and returns how many were cancelled
+func (e *OperationExecutor) CancelByOwner(owner string) int {
+	return e.cancelMatching(func(op *Operation) bool {
+		return op.Owner == owner
+	})
+}
+
+// cancelMatching cancels the running operations selected by match
+func (e *OperationExecutor) cancelMatching(match func(*Operation) bool) int {
+	cancelled := 0
+	for _, op := range e.registry.filter(match) {
+		if e.Cancel(op.ID) == nil {
+			cancelled++
+		}
+	}
+	return cancelled
+}
+
 // Cleanup manually triggers cleanup of expired operations
 func (e *OperationExecutor) Cleanup() {
 	e.registry.cleanupExpired()
 }
 
-// Stop stops the executor and cleans up resources
+// Reset cancels all running operations and clears the registry, leaving the
+// executor ready for reuse, e.g. between test cases. Unlike Stop, the
+// cleanup goroutine keeps running. Operations that were running are not
+// waited for and emit no further events.
+func (e *OperationExecutor) Reset() {
+	e.registry.reset()
+}
+
+// Stop stops the executor and cleans up resources. Later calls to Execute
+// fail with ErrExecutorStopped; Continue and Cancel keep working on the
+// operations already registered. Stop may be called more than once.
 func (e *OperationExecutor) Stop() {
 	e.registry.Stop()
 }
 
+// StopAndWait stops the executor, cancels all running operations and waits
+// for their goroutines to exit. Returns ctx.Err() if ctx is done before every
+// goroutine has finished.
+func (e *OperationExecutor) StopAndWait(ctx context.Context) error {
+	e.registry.Stop()
+	
+	done := make(chan struct{})
+	go func() {
+		e.opWG.Wait()
+		close(done)
+	}()
+	
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // ListOperations returns all operation IDs (mainly for debugging/testing)
 func (e *OperationExecutor) ListOperations() []string {
 	return e.registry.List()
-}
\ No newline at end of file
+}
+// ListOperationDetails returns a snapshot of every tracked operation, oldest
+// first
+func (e *OperationExecutor) ListOperationDetails() []OperationInfo {
+	return e.registry.details(func(*Operation) bool { return true })
+}
@@ -2,7 +2,9 @@ package async
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -237,6 +239,96 @@ func TestContinue_StillRunning(t *testing.T) {
 	}
 }
 
+// startBlockingOperation starts an operation that runs until cancelled and
+// returns its ID.
+func startBlockingOperation(t *testing.T, executor *OperationExecutor) string {
+	t.Helper()
+	operation := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	
+	result, err := executor.Execute(context.Background(), operation, ExecuteOptions{
+		Type:    "blocking_op",
+		Timeout: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result.OperationID
+}
+
+// Test Continue stops at the ctx deadline when it is shorter than waitTime
+func TestContinue_CtxDeadlineFirst(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	opID := startBlockingOperation(t, executor)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	
+	start := time.Now()
+	result, err := executor.Continue(ctx, opID, 10*time.Second)
+	elapsed := time.Since(start)
+	
+	if err != nil {
+		t.Fatalf("ctx deadline should yield a pollable result, got error: %v", err)
+	}
+	if result.Status != StatusRunning {
+		t.Errorf("expected status %s, got %s", StatusRunning, result.Status)
+	}
+	if result.OperationID != opID {
+		t.Errorf("expected operation ID %s, got %s", opID, result.OperationID)
+	}
+	if elapsed > time.Second {
+		t.Errorf("Continue waited %v, should have stopped at the ctx deadline", elapsed)
+	}
+}
+
+// Test Continue stops at waitTime when it is shorter than the ctx deadline
+func TestContinue_WaitTimeFirst(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	opID := startBlockingOperation(t, executor)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	
+	start := time.Now()
+	result, err := executor.Continue(ctx, opID, 50*time.Millisecond)
+	elapsed := time.Since(start)
+	
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusRunning {
+		t.Errorf("expected status %s, got %s", StatusRunning, result.Status)
+	}
+	if elapsed > time.Second {
+		t.Errorf("Continue waited %v, should have stopped after waitTime", elapsed)
+	}
+}
+
+// Test Continue still reports an explicit cancellation as an error
+func TestContinue_CtxCancelled(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	opID := startBlockingOperation(t, executor)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	
+	if _, err := executor.Continue(ctx, opID, 10*time.Second); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
 // Test Continue with non-existent operation
 func TestContinue_NotFound(t *testing.T) {
 	executor := createTestExecutor()
@@ -287,8 +379,8 @@ func TestCancel(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	
-	if continueResult.Status != StatusFailed {
-		t.Errorf("expected failed status after cancel, got %s", continueResult.Status)
+	if continueResult.Status != StatusCancelled {
+		t.Errorf("expected cancelled status after cancel, got %s", continueResult.Status)
 	}
 	
 	if continueResult.Error == "" {
@@ -457,4 +549,646 @@ func TestExecute_ContextCancellation(t *testing.T) {
 	if continueResult.Status != StatusCompleted {
 		t.Errorf("expected operation to complete despite context cancellation, got %s", continueResult.Status)
 	}
-}
\ No newline at end of file
+}
+// Test StopAndWait waits for operation goroutines to exit
+func TestStopAndWait(t *testing.T) {
+	executor := createTestExecutor()
+	
+	var exited bool
+	var mu sync.Mutex
+	operation := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		exited = true
+		mu.Unlock()
+		return nil, ctx.Err()
+	}
+	
+	result, err := executor.Execute(context.Background(), operation, ExecuteOptions{
+		Type:    "stop_wait_op",
+		Timeout: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusRunning {
+		t.Fatalf("expected running status, got %s", result.Status)
+	}
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	if err := executor.StopAndWait(ctx); err != nil {
+		t.Fatalf("StopAndWait returned error: %v", err)
+	}
+	
+	mu.Lock()
+	defer mu.Unlock()
+	if !exited {
+		t.Error("StopAndWait returned before the operation goroutine exited")
+	}
+}
+
+// Test StopAndWait honours its context when an operation ignores cancellation
+func TestStopAndWait_Timeout(t *testing.T) {
+	executor := createTestExecutor()
+	
+	release := make(chan struct{})
+	defer close(release)
+	operation := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return nil, nil
+	}
+	
+	executor.Execute(context.Background(), operation, ExecuteOptions{
+		Type:    "stubborn_op",
+		Timeout: 20 * time.Millisecond,
+	})
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := executor.StopAndWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+type traceKey struct{}
+
+// Test PropagateValues copies caller context values into the operation
+func TestExecute_PropagateValues(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace-123"))
+	
+	seen := make(chan interface{}, 1)
+	operation := func(opCtx context.Context) (interface{}, error) {
+		seen <- opCtx.Value(traceKey{})
+		return "ok", nil
+	}
+	
+	result, err := executor.Execute(ctx, operation, ExecuteOptions{
+		Type:            "traced_op",
+		Timeout:         1 * time.Second,
+		PropagateValues: []interface{}{traceKey{}},
+	})
+	cancel()
+	
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusCompleted {
+		t.Fatalf("expected status %s, got %s", StatusCompleted, result.Status)
+	}
+	if got := <-seen; got != "trace-123" {
+		t.Errorf("expected propagated value 'trace-123', got %v", got)
+	}
+	
+	// Without the key listed, the value is not carried over
+	operation = func(opCtx context.Context) (interface{}, error) {
+		seen <- opCtx.Value(traceKey{})
+		return "ok", nil
+	}
+	executor.Execute(context.WithValue(context.Background(), traceKey{}, "trace-456"), operation, ExecuteOptions{
+		Type:    "untraced_op",
+		Timeout: 1 * time.Second,
+	})
+	if got := <-seen; got != nil {
+		t.Errorf("expected no value without PropagateValues, got %v", got)
+	}
+}
+
+// Test a cancelled operation whose goroutine is still winding down is not
+// attached to by a new Execute with the same DedupKey
+func TestExecute_DedupKeySkipsCancelled(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	release := make(chan struct{})
+	defer close(release)
+	stubborn := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "stale", nil
+	}
+	first, err := executor.Execute(context.Background(), stubborn, ExecuteOptions{Type: "embed", Timeout: 10 * time.Millisecond, DedupKey: "doc-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := executor.Cancel(first.OperationID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	
+	second, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return "fresh", nil
+	}, ExecuteOptions{Type: "embed", Timeout: time.Second, DedupKey: "doc-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Status != StatusCompleted || second.Result != "fresh" {
+		t.Errorf("expected a fresh run, got %+v", second)
+	}
+}
+
+// Test concurrent Executes with the same DedupKey share one operation
+func TestExecute_DedupKey(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	var mu sync.Mutex
+	calls := 0
+	release := make(chan struct{})
+	operation := func(ctx context.Context) (interface{}, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		select {
+		case <-release:
+			return "embedded", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	
+	var wg sync.WaitGroup
+	results := make([]*ExecuteResult, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			results[idx], _ = executor.Execute(context.Background(), operation, ExecuteOptions{
+				Type:     "embed",
+				Timeout:  50 * time.Millisecond,
+				DedupKey: "doc-1",
+			})
+		}(i)
+	}
+	wg.Wait()
+	
+	for i, result := range results {
+		if result == nil || result.Status != StatusRunning {
+			t.Fatalf("result %d: expected running status, got %+v", i, result)
+		}
+	}
+	if results[0].OperationID != results[1].OperationID {
+		t.Errorf("expected shared operation ID, got %s and %s", results[0].OperationID, results[1].OperationID)
+	}
+	if ops := executor.ListOperations(); len(ops) != 1 {
+		t.Errorf("expected 1 registered operation, got %d", len(ops))
+	}
+	
+	close(release)
+	continueResult, err := executor.Continue(context.Background(), results[0].OperationID, 1*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if continueResult.Result != "embedded" {
+		t.Errorf("expected result 'embedded', got %v", continueResult.Result)
+	}
+	
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Errorf("expected operation to run once, ran %d times", calls)
+	}
+	
+	// Once the first operation has finished, the key is free again
+	result, _ := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return "fresh", nil
+	}, ExecuteOptions{Type: "embed", Timeout: 1 * time.Second, DedupKey: "doc-1"})
+	if result.Result != "fresh" {
+		t.Errorf("expected a new operation after completion, got %+v", result)
+	}
+}
+
+// Test Continue distinguishes unknown IDs from expired operations
+func TestContinue_ExpiredVsUnknown(t *testing.T) {
+	config := ExecutorConfig{
+		DefaultTimeout:  50 * time.Millisecond,
+		MaxLifetime:     1 * time.Second,
+		RetentionPeriod: 10 * time.Millisecond,
+		CleanupInterval: 1 * time.Hour, // cleanup is triggered manually
+	}
+	executor := NewExecutor(config)
+	defer executor.Stop()
+	
+	operation := func(ctx context.Context) (interface{}, error) {
+		time.Sleep(100 * time.Millisecond)
+		return "done", nil
+	}
+	result, err := executor.Execute(context.Background(), operation, ExecuteOptions{Type: "expiring_op"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	
+	// Let the operation finish and outlive its retention period
+	time.Sleep(200 * time.Millisecond)
+	executor.Cleanup()
+	
+	_, err = executor.Continue(context.Background(), result.OperationID, 10*time.Millisecond)
+	if !errors.Is(err, ErrOperationExpired) {
+		t.Errorf("expected ErrOperationExpired for reaped operation, got %v", err)
+	}
+	
+	_, err = executor.Continue(context.Background(), "deadbeef", 10*time.Millisecond)
+	if !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("expected ErrOperationNotFound for unknown operation, got %v", err)
+	}
+}
+
+// startTaggedOperation starts an operation that runs until cancelled
+func startTaggedOperation(t *testing.T, executor *OperationExecutor, opType string, tags map[string]string) string {
+	t.Helper()
+	operation := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	
+	result, err := executor.Execute(context.Background(), operation, ExecuteOptions{
+		Type:    opType,
+		Timeout: 10 * time.Millisecond,
+		Tags:    tags,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result.OperationID
+}
+
+// opStatus returns the current status of an operation
+func opStatus(t *testing.T, executor *OperationExecutor, id string) OperationStatus {
+	t.Helper()
+	op, err := executor.registry.Get(id)
+	if err != nil {
+		t.Fatalf("operation %s: %v", id, err)
+	}
+	return op.Status
+}
+
+// Test CancelByType only cancels operations of the matching type
+func TestCancelByType(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	img1 := startTaggedOperation(t, executor, "generate_image", nil)
+	img2 := startTaggedOperation(t, executor, "generate_image", nil)
+	txt := startTaggedOperation(t, executor, "generate_text", nil)
+	
+	if n := executor.CancelByType("generate_image"); n != 2 {
+		t.Errorf("expected 2 cancelled, got %d", n)
+	}
+	
+	for _, id := range []string{img1, img2} {
+		if status := opStatus(t, executor, id); status == StatusRunning {
+			t.Errorf("operation %s should have been cancelled", id)
+		}
+	}
+	if status := opStatus(t, executor, txt); status != StatusRunning {
+		t.Errorf("operation %s should still be running, got %s", txt, status)
+	}
+	
+	// Already-cancelled operations are not counted again
+	if n := executor.CancelByType("generate_image"); n != 0 {
+		t.Errorf("expected 0 cancelled on second call, got %d", n)
+	}
+}
+
+// Test CancelByTag only cancels operations carrying the matching tag
+func TestCancelByTag(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	openai := startTaggedOperation(t, executor, "generate_image", map[string]string{"provider": "openai"})
+	other := startTaggedOperation(t, executor, "generate_image", map[string]string{"provider": "replicate"})
+	untagged := startTaggedOperation(t, executor, "generate_image", nil)
+	
+	if n := executor.CancelByTag("provider", "openai"); n != 1 {
+		t.Errorf("expected 1 cancelled, got %d", n)
+	}
+	
+	if status := opStatus(t, executor, openai); status == StatusRunning {
+		t.Errorf("operation %s should have been cancelled", openai)
+	}
+	for _, id := range []string{other, untagged} {
+		if status := opStatus(t, executor, id); status != StatusRunning {
+			t.Errorf("operation %s should still be running, got %s", id, status)
+		}
+	}
+}
+
+// Test CancelByOwner only cancels the operations of the given owner
+func TestCancelByOwner(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	block := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	start := func(owner string) string {
+		result, err := executor.Execute(WithOwner(context.Background(), owner), block, ExecuteOptions{Type: "render", Timeout: 10 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result.OperationID
+	}
+	mine := start("session-a")
+	theirs := start("session-b")
+	
+	if n := executor.CancelByOwner("session-a"); n != 1 {
+		t.Errorf("expected 1 cancelled, got %d", n)
+	}
+	if status := opStatus(t, executor, mine); status != StatusCancelled {
+		t.Errorf("operation %s status = %s, want %s", mine, status, StatusCancelled)
+	}
+	if status := opStatus(t, executor, theirs); status != StatusRunning {
+		t.Errorf("operation %s should still be running, got %s", theirs, status)
+	}
+}
+
+// Test Reset clears all operations and leaves the executor usable
+func TestReset(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	cancelled := make(chan struct{}, 1)
+	slow := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		cancelled <- struct{}{}
+		return nil, ctx.Err()
+	}
+	running, err := executor.Execute(context.Background(), slow, ExecuteOptions{Type: "slow", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quick := func(ctx context.Context) (interface{}, error) { return "done", nil }
+	if _, err := executor.Execute(context.Background(), quick, ExecuteOptions{Type: "quick", Timeout: time.Second}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(executor.ListOperations()); n != 2 {
+		t.Fatalf("expected 2 operations before reset, got %d", n)
+	}
+	
+	executor.Reset()
+	
+	if ops := executor.ListOperations(); len(ops) != 0 {
+		t.Errorf("expected no operations after reset, got %v", ops)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("running operation was not cancelled by reset")
+	}
+	if _, err := executor.Continue(context.Background(), running.OperationID, 0); !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("expected ErrOperationNotFound after reset, got %v", err)
+	}
+	
+	// The executor keeps working after a reset
+	result, err := executor.Execute(context.Background(), quick, ExecuteOptions{Type: "quick", Timeout: time.Second})
+	if err != nil || result.Status != StatusCompleted {
+		t.Errorf("execute after reset: result %+v, err %v", result, err)
+	}
+}
+
+// Test an operation that finishes after a reset keeps the reset outcome and
+// reports nothing
+func TestReset_LateFinishReportsNothing(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	running, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		defer close(finished)
+		<-release
+		return "late", nil
+	}, ExecuteOptions{Type: "stubborn", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForEvent(t, executor, running.OperationID, EventCreated)
+	
+	executor.Reset()
+	close(release)
+	<-finished
+	
+	deadline := time.After(100 * time.Millisecond)
+	for {
+		select {
+		case ev := <-executor.Events():
+			if ev.OperationID == running.OperationID {
+				t.Fatalf("expected no events after reset, got %+v", ev)
+			}
+		case <-deadline:
+			return
+		}
+	}
+}
+
+// Test finished operations beyond MaxRetained are evicted oldest first
+func TestExecute_MaxRetained(t *testing.T) {
+	config := ExecutorConfig{
+		DefaultTimeout:  time.Second,
+		RetentionPeriod: time.Hour,
+		CleanupInterval: time.Hour,
+		MaxRetained:     2,
+	}
+	executor := NewExecutor(config)
+	defer executor.Stop()
+	
+	var ids []string
+	for i := 0; i < 4; i++ {
+		i := i
+		result, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+			return i, nil
+		}, ExecuteOptions{Type: "bulk", Timeout: time.Second})
+		if err != nil || result.Status != StatusCompleted {
+			t.Fatalf("execute %d: result %+v, err %v", i, result, err)
+		}
+		// Execute's result carries no ID for completed operations
+		ids = append(ids, newestOperation(t, executor, ids))
+		time.Sleep(time.Millisecond)
+	}
+	
+	if n := len(executor.ListOperations()); n != 2 {
+		t.Fatalf("expected 2 retained operations, got %d", n)
+	}
+	for _, id := range ids[:2] {
+		if _, err := executor.Continue(context.Background(), id, 0); !errors.Is(err, ErrOperationExpired) {
+			t.Errorf("oldest operation %s: expected ErrOperationExpired, got %v", id, err)
+		}
+	}
+	for _, id := range ids[2:] {
+		if _, err := executor.Continue(context.Background(), id, 0); err != nil {
+			t.Errorf("newest operation %s should be retained: %v", id, err)
+		}
+	}
+}
+
+// newestOperation returns the one listed operation ID not in seen
+func newestOperation(t *testing.T, executor *OperationExecutor, seen []string) string {
+	t.Helper()
+	known := make(map[string]bool, len(seen))
+	for _, id := range seen {
+		known[id] = true
+	}
+	for _, id := range executor.ListOperations() {
+		if !known[id] {
+			return id
+		}
+	}
+	t.Fatal("no new operation registered")
+	return ""
+}
+
+// Test Stop is idempotent and Execute afterwards fails cleanly
+func TestStop_Idempotent(t *testing.T) {
+	executor := createTestExecutor()
+	
+	quick := func(ctx context.Context) (interface{}, error) { return "done", nil }
+	if _, err := executor.Execute(context.Background(), quick, ExecuteOptions{Type: "quick", Timeout: time.Second}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			executor.Stop()
+		}()
+	}
+	wg.Wait()
+	executor.Stop()
+	
+	ran := false
+	result, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		ran = true
+		return nil, nil
+	}, ExecuteOptions{Type: "late", Timeout: time.Second})
+	if !errors.Is(err, ErrExecutorStopped) {
+		t.Fatalf("expected ErrExecutorStopped, got result %+v, err %v", result, err)
+	}
+	if ran {
+		t.Error("operation ran after Stop")
+	}
+	if n := len(executor.ListOperations()); n != 1 {
+		t.Errorf("expected only the operation from before Stop, got %d", n)
+	}
+}
+
+// Test Supersede cancels the running operation with the same key and starts
+// the new one
+func TestSupersede(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	oldCtx := make(chan context.Context, 1)
+	first, err := executor.Supersede(context.Background(), "search", func(ctx context.Context) (interface{}, error) {
+		oldCtx <- ctx
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, ExecuteOptions{Type: "search", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Status != StatusRunning {
+		t.Fatalf("expected first search to be running, got %+v", first)
+	}
+	<-oldCtx
+	
+	second, err := executor.Supersede(context.Background(), "search", func(ctx context.Context) (interface{}, error) {
+		return "new results", nil
+	}, ExecuteOptions{Type: "search", Timeout: 1 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Status != StatusCompleted || second.Result != "new results" {
+		t.Errorf("expected the new search to run, got %+v", second)
+	}
+	
+	old, err := executor.Continue(context.Background(), first.OperationID, 1*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if old.Status != StatusCancelled || !strings.Contains(old.Error, SupersededReason) {
+		t.Errorf("expected old search cancelled as superseded, got %+v", old)
+	}
+	
+	var cancelled *OperationEvent
+	for len(executor.Events()) > 0 {
+		ev := <-executor.Events()
+		if ev.Type == EventCancelled {
+			cancelled = &ev
+		}
+	}
+	if cancelled == nil || cancelled.OperationID != first.OperationID || cancelled.Message != SupersededReason {
+		t.Errorf("expected a cancelled event with reason %q for %s, got %+v", SupersededReason, first.OperationID, cancelled)
+	}
+}
+
+// Test concurrent Supersede calls leave exactly one operation running
+func TestSupersede_Concurrent(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	block := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			executor.Supersede(context.Background(), "search", block, ExecuteOptions{Type: "search", Timeout: 10 * time.Millisecond})
+		}()
+	}
+	wg.Wait()
+	
+	running := executor.registry.filter(func(op *Operation) bool {
+		return op.DedupKey == "search" && !op.Status.IsTerminal()
+	})
+	if len(running) != 1 {
+		t.Errorf("expected 1 running operation, got %d", len(running))
+	}
+}
+
+// Test ListOperationDetails reports each operation's state, oldest first
+func TestListOperationDetails(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	_, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	}, ExecuteOptions{Type: "quick", Tags: map[string]string{"provider": "a"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	running, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, ExecuteOptions{Type: "slow", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	
+	infos := executor.ListOperationDetails()
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 operations, got %+v", infos)
+	}
+	first, second := infos[0], infos[1]
+	if first.Type != "quick" || first.Status != StatusCompleted || first.EndTime == nil || first.Tags["provider"] != "a" {
+		t.Errorf("unexpected details for the completed operation: %+v", first)
+	}
+	if second.ID != running.OperationID || second.Status != StatusRunning || second.EndTime != nil || second.Type != "slow" {
+		t.Errorf("unexpected details for the running operation: %+v", second)
+	}
+	
+	executor.Cancel(running.OperationID)
+	for _, info := range executor.ListOperationDetails() {
+		if info.ID == running.OperationID && (info.Status != StatusCancelled || info.Error == "") {
+			t.Errorf("expected the cancelled operation to be cancelled with an error, got %+v", info)
+		}
+	}
+}
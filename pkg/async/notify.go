@@ -0,0 +1,57 @@
+package async
+
+import (
+	"log"
+	
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// NotificationOperationFinished is a framework extension (not in the MCP
+// spec) pushed to an operation's owner when it completes, fails or is
+// cancelled. Its params are the ContinueResult the owner would get from
+// continue_operation, so the client need not poll.
+const NotificationOperationFinished = "notifications/operations/finished"
+
+// Notifier delivers notification to the session identified by owner. It
+// should return an error, or may even panic, when the session has gone; the
+// executor drops such notifications silently.
+type Notifier func(owner string, notification *protocol.Notification) error
+
+// SetNotifier makes the executor push NotificationOperationFinished through
+// notify whenever an operation with an Owner finishes. Operations without an
+// owner have nobody to tell and are skipped. Pass nil to stop notifying.
+func (e *OperationExecutor) SetNotifier(notify Notifier) {
+	e.notifyMu.Lock()
+	defer e.notifyMu.Unlock()
+	e.notifier = notify
+}
+
+// notifyOwner tells op's owner that op has finished in state, the snapshot
+// taken when it ended. Delivery runs on its own goroutine: emitEnd may be
+// called with the registry locked, and a slow or broken session must not
+// hold up the executor.
+func (e *OperationExecutor) notifyOwner(op *Operation, state opState) {
+	e.notifyMu.RLock()
+	notify := e.notifier
+	e.notifyMu.RUnlock()
+	if notify == nil || op.Owner == "" {
+		return
+	}
+	
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[ASYNC] Notifier panicked for operation %s: %v", op.ID, r)
+			}
+		}()
+		
+		notification, err := protocol.NewNotification(NotificationOperationFinished, e.finished(op, state))
+		if err != nil {
+			log.Printf("[ASYNC] Failed to build notification for operation %s: %v", op.ID, err)
+			return
+		}
+		if err := notify(op.Owner, notification); err != nil {
+			log.Printf("[ASYNC] Dropped notification for operation %s: %v", op.ID, err)
+		}
+	}()
+}
@@ -0,0 +1,104 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+type delivered struct {
+	owner        string
+	notification *protocol.Notification
+}
+
+// Test the owner is notified when its operation completes
+func TestNotifier_DeliversOnCompletion(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	got := make(chan delivered, 4)
+	executor.SetNotifier(func(owner string, n *protocol.Notification) error {
+		got <- delivered{owner, n}
+		return nil
+	})
+	
+	release := make(chan struct{})
+	op := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "rendered", nil
+	}
+	ctx := WithOwner(context.Background(), "alice")
+	result, err := executor.Execute(ctx, op, ExecuteOptions{Type: "render", Timeout: 10 * time.Millisecond})
+	if err != nil || result.Status != StatusRunning {
+		t.Fatalf("execute: result %+v, err %v", result, err)
+	}
+	close(release)
+	
+	select {
+	case d := <-got:
+		if d.owner != "alice" || d.notification.Method != NotificationOperationFinished {
+			t.Errorf("delivered %q to %q, want %q to alice", d.notification.Method, d.owner, NotificationOperationFinished)
+		}
+		var params ContinueResult
+		if err := json.Unmarshal(d.notification.Params, &params); err != nil {
+			t.Fatalf("unmarshal params: %v", err)
+		}
+		if params.OperationID != result.OperationID || params.Status != StatusCompleted || params.Result != "rendered" {
+			t.Errorf("params = %+v, want completed %s with its result", params, result.OperationID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification after completion")
+	}
+	
+	// Unowned operations have nobody to notify
+	quick := func(ctx context.Context) (interface{}, error) { return "done", nil }
+	if _, err := executor.Execute(context.Background(), quick, ExecuteOptions{Type: "quick", Timeout: time.Second}); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	select {
+	case d := <-got:
+		t.Errorf("unexpected notification to %q", d.owner)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Test a disconnected session's notifier failing or panicking is harmless
+func TestNotifier_DisconnectedSession(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	calls := make(chan string, 4)
+	executor.SetNotifier(func(owner string, n *protocol.Notification) error {
+		calls <- owner
+		if owner == "gone" {
+			panic("session closed")
+		}
+		return errors.New("session disconnected")
+	})
+	
+	quick := func(ctx context.Context) (interface{}, error) { return "done", nil }
+	for _, owner := range []string{"gone", "stale"} {
+		result, err := executor.Execute(WithOwner(context.Background(), owner), quick, ExecuteOptions{Type: "quick", Timeout: time.Second})
+		if err != nil || result.Status != StatusCompleted {
+			t.Fatalf("execute for %s: result %+v, err %v", owner, result, err)
+		}
+	}
+	
+	for i := 0; i < 2; i++ {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			t.Fatal("notifier was not called")
+		}
+	}
+	
+	// The executor keeps working afterwards
+	result, err := executor.Execute(context.Background(), quick, ExecuteOptions{Type: "quick", Timeout: time.Second})
+	if err != nil || result.Status != StatusCompleted {
+		t.Errorf("execute after failed delivery: result %+v, err %v", result, err)
+	}
+}
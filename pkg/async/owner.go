@@ -0,0 +1,42 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// ErrNotOwner is returned when a caller tries to continue or cancel an
+// operation that belongs to another owner
+var ErrNotOwner = errors.New("operation belongs to another session")
+
+// ownerKey carries the owner set by WithOwner
+type ownerKey struct{}
+
+// WithOwner returns ctx identifying its caller as owner, for Execute to
+// record and for Continue and CancelFor to check
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+// OwnerFromContext returns the owner set with WithOwner or, failing that,
+// the transport session the request arrived on, so operations started over
+// a session-aware transport are scoped to that session automatically.
+// Returns "" when ctx carries neither.
+func OwnerFromContext(ctx context.Context) string {
+	if owner, ok := ctx.Value(ownerKey{}).(string); ok {
+		return owner
+	}
+	id, _ := transport.SessionIDFromContext(ctx)
+	return id
+}
+
+// checkOwner returns ErrNotOwner unless op is unowned or owned by ctx's owner
+func checkOwner(ctx context.Context, op *Operation) error {
+	if op.Owner == "" || op.Owner == OwnerFromContext(ctx) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrNotOwner, op.ID)
+}
@@ -0,0 +1,80 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// Test operations are only reachable from the session that owns them
+func TestOwner_CrossSessionDenied(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	alice := WithOwner(context.Background(), "alice")
+	bob := WithOwner(context.Background(), "bob")
+	
+	slow := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	result, err := executor.Execute(alice, slow, ExecuteOptions{Type: "slow", Timeout: 10 * time.Millisecond})
+	if err != nil || result.OperationID == "" {
+		t.Fatalf("execute: result %+v, err %v", result, err)
+	}
+	id := result.OperationID
+	
+	if _, err := executor.Continue(bob, id, 0); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("bob Continue: expected ErrNotOwner, got %v", err)
+	}
+	if _, err := executor.Continue(context.Background(), id, 0); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("anonymous Continue: expected ErrNotOwner, got %v", err)
+	}
+	if err := executor.CancelFor(bob, id); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("bob CancelFor: expected ErrNotOwner, got %v", err)
+	}
+	
+	cont, err := executor.Continue(alice, id, 0)
+	if err != nil || cont.Status != StatusRunning {
+		t.Errorf("alice Continue: result %+v, err %v", cont, err)
+	}
+	if err := executor.CancelFor(alice, id); err != nil {
+		t.Errorf("alice CancelFor: %v", err)
+	}
+}
+
+// Test the transport session is the default owner and unowned operations
+// stay open to everyone
+func TestOwner_DefaultsToSession(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	quick := func(ctx context.Context) (interface{}, error) { return "done", nil }
+	session := transport.WithSessionID(context.Background(), "session-1")
+	if _, err := executor.Execute(session, quick, ExecuteOptions{Type: "quick", Timeout: time.Second}); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if _, err := executor.Execute(context.Background(), quick, ExecuteOptions{Type: "open", Timeout: time.Second}); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	
+	for _, id := range executor.ListOperations() {
+		op, _ := executor.registry.Get(id)
+		switch op.Type {
+		case "quick":
+			if op.Owner != "session-1" {
+				t.Errorf("owner = %q, want session-1", op.Owner)
+			}
+			if _, err := executor.Continue(transport.WithSessionID(context.Background(), "session-2"), id, 0); !errors.Is(err, ErrNotOwner) {
+				t.Errorf("other session: expected ErrNotOwner, got %v", err)
+			}
+		case "open":
+			if _, err := executor.Continue(transport.WithSessionID(context.Background(), "session-2"), id, 0); err != nil {
+				t.Errorf("unowned operation: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package async
+
+import "context"
+
+type partialResultKey struct{}
+
+// AppendResult publishes an incremental result for the operation running
+// under ctx, e.g. each page a crawler finds. Continue returns everything
+// appended so far in PartialResults, so a polling client sees progress
+// before the final Result. Outside an operation started by an executor it
+// does nothing.
+func AppendResult(ctx context.Context, v interface{}) {
+	if appendResult, ok := ctx.Value(partialResultKey{}).(func(interface{})); ok {
+		appendResult(v)
+	}
+}
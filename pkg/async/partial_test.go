@@ -0,0 +1,73 @@
+package async
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Test Continue reports incremental results as they accumulate
+func TestContinue_PartialResults(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	step := make(chan struct{})
+	appended := make(chan struct{})
+	result, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		for _, page := range []string{"/a", "/b", "/c"} {
+			<-step
+			AppendResult(ctx, page)
+			appended <- struct{}{}
+		}
+		<-step
+		return "crawl done", nil
+	}, ExecuteOptions{Type: "crawl", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	opID := result.OperationID
+	
+	cont, err := executor.Continue(context.Background(), opID, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cont.Status != StatusRunning || cont.PartialResults != nil {
+		t.Fatalf("expected running with no partial results, got %+v", cont)
+	}
+	
+	var want []interface{}
+	for _, page := range []string{"/a", "/b", "/c"} {
+		step <- struct{}{}
+		<-appended
+		want = append(want, page)
+		
+		cont, err := executor.Continue(context.Background(), opID, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cont.Status != StatusRunning {
+			t.Errorf("expected running, got %s", cont.Status)
+		}
+		if !reflect.DeepEqual(cont.PartialResults, want) {
+			t.Errorf("partial results = %v, want %v", cont.PartialResults, want)
+		}
+	}
+	
+	step <- struct{}{}
+	cont, err = executor.Continue(context.Background(), opID, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cont.Status != StatusCompleted || cont.Result != "crawl done" {
+		t.Errorf("expected completed with final result, got %+v", cont)
+	}
+	if !reflect.DeepEqual(cont.PartialResults, want) {
+		t.Errorf("final partial results = %v, want %v", cont.PartialResults, want)
+	}
+}
+
+// Test AppendResult outside an operation is a no-op
+func TestAppendResult_NoOperation(t *testing.T) {
+	AppendResult(context.Background(), "ignored")
+}
@@ -1,27 +1,63 @@
 package async
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
 )
 
+// ErrOperationNotFound is returned for an operation ID the registry has
+// never seen (or forgot long ago)
+var ErrOperationNotFound = errors.New("operation not found")
+
+// ErrOperationExpired is returned for an operation that existed but was
+// removed after its retention period, so its result is no longer available
+var ErrOperationExpired = errors.New("operation expired, results no longer available")
+
+// ErrExecutorStopped is returned by Execute once the executor has been
+// stopped
+var ErrExecutorStopped = errors.New("executor stopped")
+
+// maxExpiredIDs bounds how many reaped operation IDs are remembered to tell
+// expired operations apart from unknown ones
+const maxExpiredIDs = 1024
+
 // OperationRegistry manages tracked operations
 type OperationRegistry struct {
 	operations map[string]*Operation
 	mu         sync.RWMutex
 	config     ExecutorConfig
 	stopCh     chan struct{}
+	stopOnce   sync.Once
+	stopped    bool
 	wg         sync.WaitGroup
+
+	// expired remembers recently reaped IDs; expiredOrder holds the same IDs
+	// oldest first so the set can be trimmed to maxExpiredIDs
+	expired      map[string]struct{}
+	expiredOrder []string
+	
+	// notify, when set, is told about operations the cleanup loop fails or
+	// reaps, with the state a failed operation ended in
+	notify func(EventType, *Operation, *opState, func(*OperationEvent))
 }
 
 // NewRegistry creates a new operation registry
 func NewRegistry(config ExecutorConfig) *OperationRegistry {
+	return newRegistry(config, nil)
+}
+
+// newRegistry creates a registry that reports cleanup transitions to notify
+func newRegistry(config ExecutorConfig, notify func(EventType, *Operation, *opState, func(*OperationEvent))) *OperationRegistry {
 	r := &OperationRegistry{
 		operations: make(map[string]*Operation),
+		expired:    make(map[string]struct{}),
 		config:     config,
 		stopCh:     make(chan struct{}),
+		notify:     notify,
 	}
 	
 	// Start cleanup goroutine
@@ -38,6 +74,116 @@ func (r *OperationRegistry) Add(op *Operation) {
 	log.Printf("[REGISTRY] Added operation %s (type: %s, status: %s)", op.ID, op.Type, op.Status)
 }
 
+// AddOrAttach registers op unless it has a DedupKey matching an operation
+// that is still running, in which case that operation is returned instead and
+// op is not registered. Returns op when it was added. After Stop nothing is
+// registered and op is returned.
+func (r *OperationRegistry) AddOrAttach(op *Operation) *Operation {
+	existing, _ := r.addOrAttach(op)
+	if existing == nil {
+		return op
+	}
+	return existing
+}
+
+// addOrAttach is AddOrAttach, except that once the registry is stopped it
+// registers nothing and returns ErrExecutorStopped
+func (r *OperationRegistry) addOrAttach(op *Operation) (*Operation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if r.stopped {
+		return nil, ErrExecutorStopped
+	}
+	
+	if op.DedupKey != "" {
+		for _, existing := range r.operations {
+			if existing.DedupKey == op.DedupKey && existing.Owner == op.Owner && !existing.Status.IsTerminal() {
+				return existing, nil
+			}
+		}
+	}
+	
+	r.operations[op.ID] = op
+	log.Printf("[REGISTRY] Added operation %s (type: %s, status: %s)", op.ID, op.Type, op.Status)
+	return op, nil
+}
+
+// supersede registers op after cancelling every running operation with the
+// same DedupKey and Owner, all under one lock so concurrent calls cannot both
+// survive. The cancelled operations end cancelled with reason and are returned,
+// with the state they ended in, for the caller to report.
+func (r *OperationRegistry) supersede(op *Operation, reason string) ([]endedOp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if r.stopped {
+		return nil, ErrExecutorStopped
+	}
+	
+	var superseded []endedOp
+	for _, existing := range r.operations {
+		if existing.DedupKey != op.DedupKey || existing.Owner != op.Owner {
+			continue
+		}
+		state, ok := r.finishLocked(existing, StatusCancelled, nil, fmt.Errorf("operation cancelled: %s", reason))
+		if !ok {
+			continue
+		}
+		if existing.cancelFunc != nil {
+			existing.cancelFunc()
+		}
+		superseded = append(superseded, endedOp{op: existing, state: state})
+	}
+	
+	r.operations[op.ID] = op
+	log.Printf("[REGISTRY] Added operation %s (type: %s, status: %s)", op.ID, op.Type, op.Status)
+	return superseded, nil
+}
+
+// opState is a snapshot of an operation's outcome, taken under r.mu
+type opState struct {
+	Status  OperationStatus
+	Result  interface{}
+	Error   error
+	EndTime time.Time
+}
+
+// endedOp is an operation together with the state it ended in
+type endedOp struct {
+	op    *Operation
+	state opState
+}
+
+// state returns a snapshot of op's outcome
+func (r *OperationRegistry) state(op *Operation) opState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return opState{Status: op.Status, Result: op.Result, Error: op.Error, EndTime: op.EndTime}
+}
+
+// finish moves op to the terminal status with result or err and returns the
+// resulting state. An operation that has already ended is left untouched and
+// ok is false, so whichever of completion, cancellation, supersession, reset
+// or reaping comes first decides the outcome.
+func (r *OperationRegistry) finish(op *Operation, status OperationStatus, result interface{}, err error) (state opState, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.finishLocked(op, status, result, err)
+}
+
+// finishLocked is finish for a caller already holding r.mu
+func (r *OperationRegistry) finishLocked(op *Operation, status OperationStatus, result interface{}, err error) (opState, bool) {
+	if op.Status.IsTerminal() {
+		return opState{}, false
+	}
+	op.Status = status
+	op.Result = result
+	op.Error = err
+	op.EndTime = timeNow().Now()
+	return opState{Status: op.Status, Result: op.Result, Error: op.Error, EndTime: op.EndTime}, true
+}
+
 // Get retrieves an operation by ID
 func (r *OperationRegistry) Get(id string) (*Operation, error) {
 	r.mu.RLock()
@@ -45,14 +191,38 @@ func (r *OperationRegistry) Get(id string) (*Operation, error) {
 	
 	op, exists := r.operations[id]
 	if !exists {
+		if _, wasExpired := r.expired[id]; wasExpired {
+			log.Printf("[REGISTRY] Operation %s has expired", id)
+			return nil, fmt.Errorf("%w: %s", ErrOperationExpired, id)
+		}
 		log.Printf("[REGISTRY] Operation %s not found. Current operations: %v", id, r.getOperationIDs())
-		return nil, fmt.Errorf("operation not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrOperationNotFound, id)
 	}
 	
 	log.Printf("[REGISTRY] Retrieved operation %s (type: %s, status: %s)", id, op.Type, op.Status)
 	return op, nil
 }
 
+// appendPartial records an incremental result for op
+func (r *OperationRegistry) appendPartial(op *Operation, v interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	op.partials = append(op.partials, v)
+}
+
+// partialResults returns a copy of op's incremental results, or nil if it
+// has published none
+func (r *OperationRegistry) partialResults(op *Operation) []interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(op.partials) == 0 {
+		return nil
+	}
+	out := make([]interface{}, len(op.partials))
+	copy(out, op.partials)
+	return out
+}
+
 // getOperationIDs returns all operation IDs (for debugging)
 func (r *OperationRegistry) getOperationIDs() []string {
 	ids := make([]string, 0, len(r.operations))
@@ -88,6 +258,62 @@ func (r *OperationRegistry) List() []string {
 	return ids
 }
 
+// filter returns the operations selected by match
+func (r *OperationRegistry) filter(match func(*Operation) bool) []*Operation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	var ops []*Operation
+	for _, op := range r.operations {
+		if match(op) {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// details returns a snapshot of the operations selected by match, ordered by
+// start time
+func (r *OperationRegistry) details(match func(*Operation) bool) []OperationInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	infos := []OperationInfo{}
+	for _, op := range r.operations {
+		if !match(op) {
+			continue
+		}
+		info := OperationInfo{
+			ID:        op.ID,
+			Type:      op.Type,
+			Status:    op.Status,
+			Owner:     op.Owner,
+			StartTime: op.StartTime,
+		}
+		if len(op.Tags) > 0 {
+			info.Tags = make(map[string]string, len(op.Tags))
+			for k, v := range op.Tags {
+				info.Tags[k] = v
+			}
+		}
+		if !op.EndTime.IsZero() {
+			end := op.EndTime
+			info.EndTime = &end
+		}
+		if op.Error != nil {
+			info.Error = op.Error.Error()
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].StartTime.Equal(infos[j].StartTime) {
+			return infos[i].ID < infos[j].ID
+		}
+		return infos[i].StartTime.Before(infos[j].StartTime)
+	})
+	return infos
+}
+
 // startCleanup starts the background cleanup goroutine
 func (r *OperationRegistry) startCleanup() {
 	r.wg.Add(1)
@@ -117,38 +343,114 @@ func (r *OperationRegistry) cleanupExpired() {
 	
 	for id, op := range r.operations {
 		// Remove operations that have been completed/failed for longer than retention period
-		if op.Status != StatusRunning {
+		if op.Status.IsTerminal() {
 			if now.Sub(op.EndTime) > r.config.RetentionPeriod {
 				delete(r.operations, id)
+				r.markExpired(id)
+				r.notifyEvent(EventReaped, op, nil, nil)
 			}
 		} else {
 			// Remove operations that have been running longer than max lifetime
 			if now.Sub(op.StartTime) > r.config.MaxLifetime {
 				// Cancel the operation
+				state, _ := r.finishLocked(op, StatusFailed, nil, fmt.Errorf("operation exceeded maximum lifetime"))
 				if op.cancelFunc != nil {
 					op.cancelFunc()
 				}
-				op.Status = StatusFailed
-				op.Error = fmt.Errorf("operation exceeded maximum lifetime")
-				op.EndTime = now
+				r.notifyEvent(EventFailed, op, &state, func(ev *OperationEvent) { ev.Error = state.Error.Error() })
 				// Don't delete immediately, let retention period handle it
 			}
 		}
 	}
 }
 
-// Stop stops the registry and cleanup goroutine
-func (r *OperationRegistry) Stop() {
-	close(r.stopCh)
-	r.wg.Wait()
+// evictExcess removes the finished operations that ended longest ago until
+// at most MaxRetained remain, regardless of the retention period
+func (r *OperationRegistry) evictExcess() {
+	if r.config.MaxRetained <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	var finished []*Operation
+	for _, op := range r.operations {
+		if op.Status.IsTerminal() {
+			finished = append(finished, op)
+		}
+	}
+	excess := len(finished) - r.config.MaxRetained
+	if excess <= 0 {
+		return
+	}
 	
-	// Cancel all running operations
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].EndTime.Before(finished[j].EndTime)
+	})
+	for _, op := range finished[:excess] {
+		delete(r.operations, op.ID)
+		r.markExpired(op.ID)
+		r.notifyEvent(EventReaped, op, nil, nil)
+	}
+}
+
+// notifyEvent forwards a lifecycle event to notify, if set. ended is the
+// state op ended in for a terminal event and nil otherwise.
+func (r *OperationRegistry) notifyEvent(eventType EventType, op *Operation, ended *opState, fill func(*OperationEvent)) {
+	if r.notify != nil {
+		r.notify(eventType, op, ended, fill)
+	}
+}
+
+// markExpired records id as reaped, evicting the oldest entry once the set
+// exceeds maxExpiredIDs. Caller must hold r.mu.
+func (r *OperationRegistry) markExpired(id string) {
+	r.expired[id] = struct{}{}
+	r.expiredOrder = append(r.expiredOrder, id)
+	if len(r.expiredOrder) > maxExpiredIDs {
+		delete(r.expired, r.expiredOrder[0])
+		r.expiredOrder = r.expiredOrder[1:]
+	}
+}
+
+// reset cancels every running operation and forgets all operations,
+// including the record of expired IDs. The cleanup goroutine keeps running.
+func (r *OperationRegistry) reset() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
 	for _, op := range r.operations {
+		// Mark it ended first so the operation's goroutine reports nothing
+		r.finishLocked(op, StatusCancelled, nil, fmt.Errorf("operation cancelled by reset"))
 		if op.cancelFunc != nil {
 			op.cancelFunc()
 		}
 	}
+	r.operations = make(map[string]*Operation)
+	r.expired = make(map[string]struct{})
+	r.expiredOrder = nil
+}
+
+// Stop stops the registry and cleanup goroutine. It is safe to call more
+// than once and concurrently with other methods; calls after the first do
+// nothing.
+func (r *OperationRegistry) Stop() {
+	r.stopOnce.Do(func() {
+		r.mu.Lock()
+		r.stopped = true
+		r.mu.Unlock()
+		
+		close(r.stopCh)
+		r.wg.Wait()
+		
+		// Cancel all running operations
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		
+		for _, op := range r.operations {
+			if op.cancelFunc != nil {
+				op.cancelFunc()
+			}
+		}
+	})
 }
\ No newline at end of file
@@ -0,0 +1,220 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// Names of the standard tools installed by RegisterTools and
+// RegisterManagementTools
+const (
+	ContinueOperationTool = "continue_operation"
+	CancelOperationTool   = "cancel_operation"
+	ListOperationsTool    = "list_operations"
+)
+
+// defaultContinueWait is how long continue_operation waits when the client
+// does not pass wait_time
+const defaultContinueWait = 30 * time.Second
+
+var continueOperationSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"operation_id": {
+			"type": "string",
+			"description": "The operation ID returned by the original tool call"
+		},
+		"wait_time": {
+			"type": "integer",
+			"description": "Maximum seconds to wait for completion (default: 30)"
+		}
+	},
+	"required": ["operation_id"]
+}`)
+
+var cancelOperationSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"operation_id": {
+			"type": "string",
+			"description": "The operation ID to cancel"
+		}
+	},
+	"required": ["operation_id"]
+}`)
+
+var listOperationsSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"status": {
+			"type": "string",
+			"enum": ["running", "completed", "failed", "cancelled"],
+			"description": "Only list operations with this status"
+		}
+	}
+}`)
+
+// RegisterTools installs the standard continue_operation and cancel_operation
+// tools on router, so a tool that returns a "processing" result from Execute
+// does not have to hand-roll them. Both answer with the JSON encoding of the
+// outcome as text content; an unknown or already-finished operation is
+// reported as a tool error rather than a protocol error.
+func (e *OperationExecutor) RegisterTools(router *handler.ToolRouter) {
+	router.Register(protocol.Tool{
+		Name:        ContinueOperationTool,
+		Description: "Check the status of a long-running operation, waiting up to wait_time seconds for it to finish",
+		InputSchema: continueOperationSchema,
+	}, e.callContinue)
+	
+	router.Register(protocol.Tool{
+		Name:        CancelOperationTool,
+		Description: "Cancel a running long-running operation",
+		InputSchema: cancelOperationSchema,
+	}, e.callCancel)
+}
+
+// RegisterManagementTools installs the list_operations and cancel_operation
+// tools on router, letting clients see and stop the executor's operations.
+// list_operations answers with {"operations":[...]} as text content, listing
+// only the operations the caller may continue or cancel.
+func (e *OperationExecutor) RegisterManagementTools(router *handler.ToolRouter) {
+	router.Register(protocol.Tool{
+		Name:        ListOperationsTool,
+		Description: "List tracked long-running operations, optionally only those with the given status",
+		InputSchema: listOperationsSchema,
+	}, e.callList)
+	
+	router.Register(protocol.Tool{
+		Name:        CancelOperationTool,
+		Description: "Cancel a running long-running operation",
+		InputSchema: cancelOperationSchema,
+	}, e.callCancel)
+}
+
+// callContinue implements the continue_operation tool
+func (e *OperationExecutor) callContinue(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	operationID := req.GetString("operation_id", "")
+	if operationID == "" {
+		return toolError("operation_id is required"), nil
+	}
+	
+	waitTime := defaultContinueWait
+	if seconds := req.GetInt("wait_time", -1); seconds >= 0 {
+		waitTime = time.Duration(seconds) * time.Second
+	}
+	
+	result, err := e.Continue(ctx, operationID, waitTime)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return toolError(err.Error()), nil
+	}
+	
+	resp, err := protocol.NewJSONToolResult(result)
+	if err != nil {
+		return nil, err
+	}
+	resp.IsError = result.Status == StatusFailed || result.Status == StatusCancelled
+	return resp, nil
+}
+
+// callCancel implements the cancel_operation tool
+func (e *OperationExecutor) callCancel(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	operationID := req.GetString("operation_id", "")
+	if operationID == "" {
+		return toolError("operation_id is required"), nil
+	}
+	
+	if err := e.CancelFor(ctx, operationID); err != nil {
+		return toolError(err.Error()), nil
+	}
+	
+	return protocol.NewJSONToolResult(map[string]string{
+		"status":       "cancelled",
+		"operation_id": operationID,
+	})
+}
+
+// callList implements the list_operations tool
+func (e *OperationExecutor) callList(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	status := OperationStatus(req.GetString("status", ""))
+	if status != "" && !status.IsValid() {
+		return toolError(fmt.Sprintf("unknown status %q", status)), nil
+	}
+	
+	operations := e.registry.details(func(op *Operation) bool {
+		if status != "" && op.Status != status {
+			return false
+		}
+		return checkOwner(ctx, op) == nil
+	})
+	return protocol.NewJSONToolResult(map[string]interface{}{
+		"operations": operations,
+	})
+}
+
+// toolRequestKey carries the triggering tools/call request into operations
+// started by WrapTool
+type toolRequestKey struct{}
+
+// ToolRequestFromContext returns the tools/call request that started the
+// operation, for operations run through WrapTool
+func ToolRequestFromContext(ctx context.Context) (*protocol.CallToolRequest, bool) {
+	req, ok := ctx.Value(toolRequestKey{}).(*protocol.CallToolRequest)
+	return req, ok
+}
+
+// WrapTool turns fn into a tool handler that runs it on executor. A call that
+// finishes within timeout answers with {"status":"completed","result":...};
+// one that fails answers with {"status":"failed","error":...} and IsError set;
+// one still running answers with {"status":"processing","operation_id":...}
+// so the client can poll with continue_operation. fn reads the tool
+// arguments through ToolRequestFromContext.
+func WrapTool(executor *OperationExecutor, opType string, fn OperationFunc, timeout time.Duration) handler.CallFunc {
+	return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		operation := func(opCtx context.Context) (interface{}, error) {
+			return fn(context.WithValue(opCtx, toolRequestKey{}, req))
+		}
+		
+		result, err := executor.Execute(ctx, operation, ExecuteOptions{
+			Type:    opType,
+			Timeout: timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		
+		switch result.Status {
+		case StatusRunning:
+			return protocol.NewJSONToolResult(map[string]interface{}{
+				"status":       "processing",
+				"operation_id": result.OperationID,
+				"message":      result.Message,
+			})
+		case StatusFailed, StatusCancelled:
+			return protocol.NewJSONToolError(map[string]interface{}{
+				"status": result.Status,
+				"error":  result.Error,
+			})
+		default:
+			return protocol.NewJSONToolResult(map[string]interface{}{
+				"status": "completed",
+				"result": result.Result,
+			})
+		}
+	}
+}
+
+// toolError builds a tool response reporting msg as a failed call
+func toolError(msg string) *protocol.CallToolResponse {
+	return &protocol.CallToolResponse{
+		Content: []protocol.ToolContent{{Type: "text", Text: msg}},
+		IsError: true,
+	}
+}
@@ -0,0 +1,266 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// callTool invokes name on router and decodes the JSON text result into a
+// ContinueResult-shaped map
+func callTool(t *testing.T, router *handler.ToolRouter, name string, args map[string]interface{}) (*protocol.CallToolResponse, map[string]interface{}) {
+	t.Helper()
+	resp, err := router.CallTool(context.Background(), &protocol.CallToolRequest{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", name, err)
+	}
+	if len(resp.Content) != 1 {
+		t.Fatalf("%s: expected one content item, got %+v", name, resp.Content)
+	}
+	var body map[string]interface{}
+	json.Unmarshal([]byte(resp.Content[0].Text), &body)
+	return resp, body
+}
+
+func TestRegisterTools_List(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	router := handler.NewToolRouter()
+	executor.RegisterTools(router)
+	
+	list, err := router.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Tools) != 2 || list.Tools[0].Name != ContinueOperationTool || list.Tools[1].Name != CancelOperationTool {
+		t.Fatalf("unexpected tools %+v", list.Tools)
+	}
+	for _, tool := range list.Tools {
+		if !json.Valid(tool.InputSchema) {
+			t.Errorf("%s: input schema is not valid JSON", tool.Name)
+		}
+	}
+}
+
+func TestRegisterTools_Continue(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	router := handler.NewToolRouter()
+	executor.RegisterTools(router)
+	
+	release := make(chan struct{})
+	result, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "finished", nil
+	}, ExecuteOptions{Type: "slow", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StatusRunning {
+		t.Fatalf("expected running, got %s", result.Status)
+	}
+	
+	resp, body := callTool(t, router, ContinueOperationTool, map[string]interface{}{
+		"operation_id": result.OperationID,
+		"wait_time":    float64(0),
+	})
+	if resp.IsError || body["status"] != "running" || body["operation_id"] != result.OperationID {
+		t.Errorf("expected a running result, got %+v", resp.Content[0].Text)
+	}
+	
+	close(release)
+	resp, body = callTool(t, router, ContinueOperationTool, map[string]interface{}{
+		"operation_id": result.OperationID,
+		"wait_time":    float64(1),
+	})
+	if resp.IsError || body["status"] != "completed" || body["result"] != "finished" {
+		t.Errorf("expected a completed result, got %+v", resp.Content[0].Text)
+	}
+	
+	resp, _ = callTool(t, router, ContinueOperationTool, map[string]interface{}{"operation_id": "nope"})
+	if !resp.IsError {
+		t.Errorf("expected a tool error for an unknown operation, got %+v", resp.Content[0].Text)
+	}
+	
+	resp, _ = callTool(t, router, ContinueOperationTool, map[string]interface{}{})
+	if !resp.IsError {
+		t.Errorf("expected a tool error without operation_id")
+	}
+}
+
+// Test continue_operation resumes an operation started by a connection that
+// went away, and keeps answering after the result has been delivered once
+func TestRegisterTools_ContinueAfterReconnect(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	router := handler.NewToolRouter()
+	executor.RegisterTools(router)
+	
+	// The first connection starts the operation and dies before it finishes
+	connCtx, disconnect := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	done := make(chan *ExecuteResult, 1)
+	go func() {
+		result, _ := executor.Execute(connCtx, func(ctx context.Context) (interface{}, error) {
+			<-release
+			return "report", nil
+		}, ExecuteOptions{Type: "report", Timeout: 1 * time.Second})
+		done <- result
+	}()
+	time.Sleep(20 * time.Millisecond)
+	disconnect()
+	result := <-done
+	if result.Status != StatusRunning {
+		t.Fatalf("expected the operation to outlive the connection, got %+v", result)
+	}
+	close(release)
+	
+	// A new connection picks it up by ID, as often as it likes
+	for i := 0; i < 2; i++ {
+		resp, body := callTool(t, router, ContinueOperationTool, map[string]interface{}{
+			"operation_id": result.OperationID,
+			"wait_time":    float64(1),
+		})
+		if resp.IsError || body["status"] != "completed" || body["result"] != "report" {
+			t.Errorf("continue %d: expected the completed result, got %+v", i, resp.Content[0].Text)
+		}
+	}
+}
+
+func TestRegisterTools_Cancel(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	router := handler.NewToolRouter()
+	executor.RegisterTools(router)
+	
+	result, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, ExecuteOptions{Type: "cancellable", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	
+	resp, body := callTool(t, router, CancelOperationTool, map[string]interface{}{"operation_id": result.OperationID})
+	if resp.IsError || body["status"] != "cancelled" {
+		t.Fatalf("expected a cancelled result, got %+v", resp.Content[0].Text)
+	}
+	
+	resp, body = callTool(t, router, ContinueOperationTool, map[string]interface{}{"operation_id": result.OperationID})
+	if !resp.IsError || body["status"] != "cancelled" {
+		t.Errorf("expected continue to report the operation as cancelled, got %+v", resp.Content[0].Text)
+	}
+	
+	resp, _ = callTool(t, router, CancelOperationTool, map[string]interface{}{"operation_id": result.OperationID})
+	if !resp.IsError {
+		t.Errorf("expected a tool error cancelling a finished operation")
+	}
+}
+
+func TestWrapTool(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	
+	release := make(chan struct{})
+	defer close(release)
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "echo"}, WrapTool(executor, "echo", func(ctx context.Context) (interface{}, error) {
+		req, ok := ToolRequestFromContext(ctx)
+		if !ok {
+			return nil, errors.New("no tool request in context")
+		}
+		return req.GetString("text", ""), nil
+	}, time.Second))
+	router.Register(protocol.Tool{Name: "fail"}, WrapTool(executor, "fail", func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("boom")
+	}, time.Second))
+	router.Register(protocol.Tool{Name: "slow"}, WrapTool(executor, "slow", func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "late", nil
+	}, 10*time.Millisecond))
+	
+	resp, body := callTool(t, router, "echo", map[string]interface{}{"text": "hi"})
+	if resp.IsError || len(body) != 2 || body["status"] != "completed" || body["result"] != "hi" {
+		t.Errorf("immediate completion: got %s", resp.Content[0].Text)
+	}
+	
+	resp, body = callTool(t, router, "fail", nil)
+	if !resp.IsError || len(body) != 2 || body["status"] != "failed" || body["error"] != "boom" {
+		t.Errorf("failure: got %s (isError=%v)", resp.Content[0].Text, resp.IsError)
+	}
+	
+	resp, body = callTool(t, router, "slow", nil)
+	if resp.IsError || len(body) != 3 || body["status"] != "processing" || body["operation_id"] == "" || body["message"] == "" {
+		t.Fatalf("timeout: got %s", resp.Content[0].Text)
+	}
+	if _, err := executor.registry.Get(body["operation_id"].(string)); err != nil {
+		t.Errorf("processing operation_id does not name a registered operation: %v", err)
+	}
+}
+
+func TestRegisterManagementTools_List(t *testing.T) {
+	executor := createTestExecutor()
+	defer executor.Stop()
+	router := handler.NewToolRouter()
+	executor.RegisterManagementTools(router)
+	
+	block := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	mine, err := executor.Execute(WithOwner(context.Background(), "alice"), block, ExecuteOptions{Type: "mine", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := executor.Execute(WithOwner(context.Background(), "bob"), block, ExecuteOptions{Type: "theirs", Timeout: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	
+	list := func(args map[string]interface{}) []OperationInfo {
+		t.Helper()
+		resp, err := router.CallTool(WithOwner(context.Background(), "alice"), &protocol.CallToolRequest{Name: ListOperationsTool, Arguments: args})
+		if err != nil || resp.IsError {
+			t.Fatalf("list_operations failed: %v %+v", err, resp)
+		}
+		var body struct {
+			Operations []OperationInfo `json:"operations"`
+		}
+		if err := json.Unmarshal([]byte(resp.Content[0].Text), &body); err != nil {
+			t.Fatalf("decode result: %v", err)
+		}
+		return body.Operations
+	}
+	
+	ops := list(nil)
+	if len(ops) != 1 || ops[0].ID != mine.OperationID || ops[0].Status != StatusRunning {
+		t.Fatalf("expected only alice's running operation, got %+v", ops)
+	}
+	
+	resp, err := router.CallTool(WithOwner(context.Background(), "alice"), &protocol.CallToolRequest{
+		Name:      CancelOperationTool,
+		Arguments: map[string]interface{}{"operation_id": mine.OperationID},
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("cancel failed: %v %+v", err, resp)
+	}
+	if ops := list(map[string]interface{}{"status": "running"}); len(ops) != 0 {
+		t.Errorf("expected no running operations after cancel, got %+v", ops)
+	}
+	if ops := list(map[string]interface{}{"status": "failed"}); len(ops) != 0 {
+		t.Errorf("expected no failed operations after cancel, got %+v", ops)
+	}
+	if ops := list(map[string]interface{}{"status": "cancelled"}); len(ops) != 1 || ops[0].ID != mine.OperationID {
+		t.Errorf("expected the cancelled operation listed as cancelled, got %+v", ops)
+	}
+	
+	resp, _ = callTool(t, router, ListOperationsTool, map[string]interface{}{"status": "exploded"})
+	if !resp.IsError {
+		t.Errorf("expected a tool error for an unknown status")
+	}
+}
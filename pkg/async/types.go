@@ -2,6 +2,8 @@ package async
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -15,12 +17,53 @@ const (
 	StatusRunning   OperationStatus = "running"
 	StatusCompleted OperationStatus = "completed"
 	StatusFailed    OperationStatus = "failed"
+	StatusCancelled OperationStatus = "cancelled"
 )
 
+// IsValid reports whether s is one of the known status values
+func (s OperationStatus) IsValid() bool {
+	switch s {
+	case StatusRunning, StatusCompleted, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether s is a final state the operation cannot leave
+func (s OperationStatus) IsTerminal() bool {
+	switch s {
+	case StatusCompleted, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// MarshalJSON encodes the status as its plain string value
+func (s OperationStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON decodes a status string, rejecting unknown values
+func (s *OperationStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("operation status must be a string: %w", err)
+	}
+	status := OperationStatus(str)
+	if !status.IsValid() {
+		return fmt.Errorf("unknown operation status: %q", str)
+	}
+	*s = status
+	return nil
+}
+
 // Operation represents a tracked async operation
 type Operation struct {
 	ID         string
 	Type       string
+	DedupKey   string
+	Tags       map[string]string
+	Owner      string
 	Status     OperationStatus
 	Result     interface{}
 	Error      error
@@ -28,12 +71,39 @@ type Operation struct {
 	EndTime    time.Time
 	CompleteCh chan struct{}
 	cancelFunc context.CancelFunc // For cancelling the operation
+	partials   []interface{}      // Incremental results, guarded by the registry lock
 }
 
+// SupersededReason is the cancellation reason of an operation replaced by
+// Supersede
+const SupersededReason = "superseded"
+
 // ExecuteOptions configures how an operation should be executed
 type ExecuteOptions struct {
 	Type    string        // Operation type (e.g., "generate_image")
 	Timeout time.Duration // How long to wait before returning "processing" status
+
+	// PropagateValues lists context keys whose values are copied from the
+	// caller's ctx into the operation ctx. The operation ctx is detached from
+	// the caller's cancellation, so request-scoped values (trace IDs, auth)
+	// are otherwise lost.
+	PropagateValues []interface{}
+
+	// DedupKey, when set, makes Execute attach to an already-running
+	// operation with the same key instead of starting a duplicate. Useful
+	// for expensive work (e.g. "embed document X") triggered concurrently.
+	DedupKey string
+
+	// Tags attach arbitrary key/value labels to the operation (e.g. the
+	// upstream provider it calls) so operations can be selected in bulk,
+	// as CancelByTag does.
+	Tags map[string]string
+
+	// Owner, when set, ties the operation to a session: Continue and
+	// CancelFor then refuse callers whose context carries a different
+	// owner. Empty falls back to OwnerFromContext of Execute's ctx; an
+	// operation with no owner is open to everyone.
+	Owner string
 }
 
 // ExecutorConfig configures the operation executor
@@ -42,6 +112,8 @@ type ExecutorConfig struct {
 	MaxLifetime     time.Duration // Maximum operation lifetime (default: 10m)
 	RetentionPeriod time.Duration // How long to keep completed operations (default: 5m)
 	CleanupInterval time.Duration // How often to clean up expired operations (default: 1m)
+	EventBufferSize int           // Capacity of the Events() channel (default: 256 when <= 0)
+	MaxRetained     int           // Most finished operations kept; oldest evicted first (default: 0, unlimited)
 }
 
 // DefaultConfig returns a default configuration
@@ -74,4 +146,20 @@ type ContinueResult struct {
 	Error         string                 `json:"error,omitempty"`
 	Message       string                 `json:"message,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
-}
\ No newline at end of file
+	
+	// PartialResults holds the incremental results the operation has
+	// published with AppendResult so far, oldest first
+	PartialResults []interface{} `json:"partial_results,omitempty"`
+}
+// OperationInfo is a snapshot of one tracked operation, as reported by
+// ListOperationDetails
+type OperationInfo struct {
+	ID        string            `json:"operation_id"`
+	Type      string            `json:"operation_type"`
+	Status    OperationStatus   `json:"status"`
+	Owner     string            `json:"owner,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	StartTime time.Time         `json:"start_time"`
+	EndTime   *time.Time        `json:"end_time,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
@@ -0,0 +1,71 @@
+package async
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Test unmarshaling known status values
+func TestOperationStatus_UnmarshalValid(t *testing.T) {
+	for _, want := range []OperationStatus{StatusRunning, StatusCompleted, StatusFailed, StatusCancelled} {
+		var got OperationStatus
+		if err := json.Unmarshal([]byte(`"`+string(want)+`"`), &got); err != nil {
+			t.Errorf("unmarshal %q: unexpected error: %v", want, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	}
+}
+
+// Test unmarshaling rejects unknown status values
+func TestOperationStatus_UnmarshalInvalid(t *testing.T) {
+	var result ContinueResult
+	err := json.Unmarshal([]byte(`{"status":"exploded","operation_id":"abc","operation_type":"x"}`), &result)
+	if err == nil {
+		t.Fatalf("expected error for unknown status, got %s", result.Status)
+	}
+	
+	var status OperationStatus
+	if err := json.Unmarshal([]byte(`42`), &status); err == nil {
+		t.Error("expected error for non-string status")
+	}
+}
+
+// Test status round-trips through JSON as a plain string
+func TestOperationStatus_Marshal(t *testing.T) {
+	data, err := json.Marshal(ExecuteResult{Status: StatusCompleted})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"status":"completed"}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+// Test IsTerminal classification
+func TestOperationStatus_IsTerminal(t *testing.T) {
+	if StatusRunning.IsTerminal() {
+		t.Error("running should not be terminal")
+	}
+	for _, s := range []OperationStatus{StatusCompleted, StatusFailed, StatusCancelled} {
+		if !s.IsTerminal() {
+			t.Errorf("%s should be terminal", s)
+		}
+	}
+}
+
+// Test IsValid accepts only the known statuses
+func TestOperationStatus_IsValid(t *testing.T) {
+	for _, s := range []OperationStatus{StatusRunning, StatusCompleted, StatusFailed, StatusCancelled} {
+		if !s.IsValid() {
+			t.Errorf("%s should be valid", s)
+		}
+	}
+	for _, s := range []OperationStatus{"", "Running", "exploded"} {
+		if s.IsValid() {
+			t.Errorf("%q should not be valid", s)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// ErrCircuitOpen is returned, wrapped, when a call is rejected because the
+// tool's circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitBreaker returns a ToolMiddleware that fast-fails calls to a tool
+// whose handler keeps erroring. Each tool name has its own breaker: after
+// threshold consecutive errors it opens and rejects calls with
+// ErrCircuitOpen for cooldown. It then lets a single trial call through;
+// success closes the breaker, failure opens it for another cooldown. A call
+// that panics counts as a failure, and so does a trial still running after
+// a whole cooldown: it is taken to be abandoned.
+func CircuitBreaker(threshold int, cooldown time.Duration) ToolMiddleware {
+	if threshold < 1 {
+		threshold = 1
+	}
+	var mu sync.Mutex
+	breakers := make(map[string]*breaker)
+
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+			mu.Lock()
+			b, ok := breakers[req.Name]
+			if !ok {
+				b = &breaker{threshold: threshold, cooldown: cooldown}
+				breakers[req.Name] = b
+			}
+			mu.Unlock()
+
+			retryIn, trial, ok := b.admit(time.Now())
+			if !ok {
+				return nil, fmt.Errorf("%w for tool %q, retry in %v", ErrCircuitOpen, req.Name, retryIn.Round(time.Millisecond))
+			}
+			success := false
+			defer func() { b.record(trial, success, time.Now()) }()
+			resp, err := next(ctx, req)
+			success = err == nil
+			return resp, err
+		}
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker is the state machine behind CircuitBreaker for a single tool.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+
+	state    breakerState
+	failures int
+	openedAt time.Time
+
+	// trial numbers the trial calls; trialAt is when the latest started
+	trial   uint64
+	trialAt time.Time
+}
+
+// admit reports whether a call may proceed and, for a trial call, its
+// number (0 otherwise). While open it returns the time left in the
+// cooldown.
+func (b *breaker) admit(now time.Time) (time.Duration, uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen && now.Sub(b.trialAt) >= b.cooldown {
+		// The trial has run for a whole cooldown: count it as failed, as
+		// of then, and ignore its outcome should it ever return.
+		b.state = breakerOpen
+		b.openedAt = b.trialAt.Add(b.cooldown)
+		b.trial++
+	}
+
+	switch b.state {
+	case breakerOpen:
+		if elapsed := now.Sub(b.openedAt); elapsed < b.cooldown {
+			return b.cooldown - elapsed, 0, false
+		}
+		// Cooldown over: let one trial call through.
+		b.state = breakerHalfOpen
+		b.trial++
+		b.trialAt = now
+		return 0, b.trial, true
+	case breakerHalfOpen:
+		// A trial call is already in flight.
+		return b.cooldown - now.Sub(b.trialAt), 0, false
+	default:
+		return 0, 0, true
+	}
+}
+
+// record updates the breaker with the outcome of an admitted call. The
+// outcome of a trial that has since been written off is ignored.
+func (b *breaker) record(trial uint64, success bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial != 0 && trial != b.trial {
+		return
+	}
+	if success {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = now
+	}
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// flakyBackend fails while failing is true and counts the calls that reach it.
+type flakyBackend struct {
+	failing bool
+	calls   int
+}
+
+func (f *flakyBackend) call(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	f.calls++
+	if f.failing {
+		return nil, errors.New("backend unavailable")
+	}
+	return &protocol.CallToolResponse{}, nil
+}
+
+func TestCircuitBreakerTripsAfterThreshold(t *testing.T) {
+	backend := &flakyBackend{failing: true}
+	call := ChainToolMiddleware(backend.call, CircuitBreaker(3, time.Hour))
+	req := &protocol.CallToolRequest{Name: "generate_image"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := call(context.Background(), req); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: got %v, want the backend error", i, err)
+		}
+	}
+
+	// Open: calls fail fast without reaching the backend.
+	for i := 0; i < 5; i++ {
+		if _, err := call(context.Background(), req); !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call while open: got %v, want ErrCircuitOpen", err)
+		}
+	}
+	if backend.calls != 3 {
+		t.Errorf("backend calls = %d, want 3", backend.calls)
+	}
+
+	// Breakers are per tool.
+	if _, err := call(context.Background(), &protocol.CallToolRequest{Name: "other"}); errors.Is(err, ErrCircuitOpen) {
+		t.Error("breaker for one tool should not affect another")
+	}
+}
+
+func TestCircuitBreakerSuccessResetsCount(t *testing.T) {
+	backend := &flakyBackend{}
+	call := ChainToolMiddleware(backend.call, CircuitBreaker(2, time.Hour))
+	req := &protocol.CallToolRequest{Name: "t"}
+
+	for _, failing := range []bool{true, false, true, false, true} {
+		backend.failing = failing
+		if _, err := call(context.Background(), req); errors.Is(err, ErrCircuitOpen) {
+			t.Fatal("non-consecutive failures must not trip the breaker")
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpenRecovery(t *testing.T) {
+	backend := &flakyBackend{failing: true}
+	call := ChainToolMiddleware(backend.call, CircuitBreaker(1, 30*time.Millisecond))
+	req := &protocol.CallToolRequest{Name: "t"}
+
+	call(context.Background(), req) // trips
+	if _, err := call(context.Background(), req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected breaker open during cooldown, got %v", err)
+	}
+
+	// After cooldown a failed trial re-opens the breaker.
+	time.Sleep(40 * time.Millisecond)
+	if _, err := call(context.Background(), req); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("trial call should reach the backend and fail, got %v", err)
+	}
+	if _, err := call(context.Background(), req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("failed trial should re-open the breaker, got %v", err)
+	}
+
+	// After another cooldown a successful trial closes it.
+	backend.failing = false
+	time.Sleep(40 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if _, err := call(context.Background(), req); err != nil {
+			t.Fatalf("call %d after recovery: %v", i, err)
+		}
+	}
+}
+
+func TestCircuitBreakerPanicCountsAsFailure(t *testing.T) {
+	panicking := func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		panic("boom")
+	}
+	call := ChainToolMiddleware(panicking, CircuitBreaker(1, time.Hour))
+	req := &protocol.CallToolRequest{Name: "t"}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("the panic should propagate")
+			}
+		}()
+		call(context.Background(), req)
+	}()
+	if _, err := call(context.Background(), req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("a panic should trip the breaker, got %v", err)
+	}
+}
+
+func TestCircuitBreakerAbandonedTrial(t *testing.T) {
+	hung := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	call := ChainToolMiddleware(func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		calls++
+		if calls == 2 {
+			// The first trial hangs until released, then succeeds
+			close(hung)
+			<-release
+			return &protocol.CallToolResponse{}, nil
+		}
+		return nil, errors.New("backend unavailable")
+	}, CircuitBreaker(1, 30*time.Millisecond))
+	req := &protocol.CallToolRequest{Name: "t"}
+
+	call(context.Background(), req) // trips
+	time.Sleep(40 * time.Millisecond)
+	go call(context.Background(), req)
+	<-hung
+
+	if _, err := call(context.Background(), req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected calls rejected while the trial runs, got %v", err)
+	}
+
+	// A cooldown later the trial counts as failed, opening the breaker for
+	// another cooldown; after that a new trial is let through.
+	time.Sleep(40 * time.Millisecond)
+	if _, err := call(context.Background(), req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the abandoned trial to re-open the breaker, got %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, err := call(context.Background(), req); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("a new trial should reach the backend and fail, got %v", err)
+	}
+
+	// The abandoned trial's late success does not close the breaker.
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+	if _, err := call(context.Background(), req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("a stale trial must not close the breaker, got %v", err)
+	}
+}
@@ -2,6 +2,9 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"sync"
+
 	"github.com/gomcpgo/mcp/pkg/protocol"
 )
 
@@ -32,6 +35,18 @@ type ResourceHandler interface {
 	ReadResource(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResponse, error)
 }
 
+// StreamingResourceHandler is an optional extension of ResourceHandler for
+// large resources. When the client asks for a streamed read, the server calls
+// ReadResourceStream instead of ReadResource; the handler pushes content
+// pieces through emit (each becomes a chunk notification) and returns the
+// final response, which typically carries only metadata or the tail.
+type StreamingResourceHandler interface {
+	ResourceHandler
+
+	// ReadResourceStream reads a resource, emitting content in chunks
+	ReadResourceStream(ctx context.Context, req *protocol.ReadResourceRequest, emit func(protocol.ResourceContent) error) (*protocol.ReadResourceResponse, error)
+}
+
 // PromptHandler handles prompt-related operations
 type PromptHandler interface {
 	// ListPrompts returns available prompts
@@ -41,11 +56,15 @@ type PromptHandler interface {
 	GetPrompt(ctx context.Context, req *protocol.GetPromptRequest) (*protocol.GetPromptResponse, error)
 }
 
-// HandlerRegistry maintains a collection of handlers for different capabilities
+// HandlerRegistry maintains a collection of handlers for different capabilities.
+// It is safe for concurrent use, so handlers may be registered, replaced or
+// unregistered while the server is dispatching requests.
 type HandlerRegistry struct {
+	mu              sync.RWMutex
 	toolHandler     ToolHandler
 	resourceHandler ResourceHandler
 	promptHandler   PromptHandler
+	handler         Handler
 }
 
 // NewHandlerRegistry creates a new handler registry
@@ -53,47 +72,146 @@ func NewHandlerRegistry() *HandlerRegistry {
 	return &HandlerRegistry{}
 }
 
-// RegisterToolHandler registers a tool handler
+// RegisterToolHandler registers a tool handler, replacing any existing one
 func (r *HandlerRegistry) RegisterToolHandler(h ToolHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.toolHandler = h
 }
 
-// RegisterResourceHandler registers a resource handler
+// RegisterResourceHandler registers a resource handler, replacing any existing one
 func (r *HandlerRegistry) RegisterResourceHandler(h ResourceHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.resourceHandler = h
 }
 
-// RegisterPromptHandler registers a prompt handler
+// RegisterResourceScheme routes resources/read requests for URIs with the
+// given scheme to h. The first call installs a ResourceRouter as the
+// resource handler, replacing any handler registered with
+// RegisterResourceHandler; later calls add schemes to that router.
+func (r *HandlerRegistry) RegisterResourceScheme(scheme string, h ResourceHandler) {
+	r.mu.Lock()
+	router, ok := r.resourceHandler.(*ResourceRouter)
+	if !ok {
+		router = NewResourceRouter()
+		r.resourceHandler = router
+	}
+	r.mu.Unlock()
+	router.RegisterResourceScheme(scheme, h)
+}
+
+// RegisterPromptHandler registers a prompt handler, replacing any existing one
 func (r *HandlerRegistry) RegisterPromptHandler(h PromptHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.promptHandler = h
 }
 
+// ErrPromptHandlerNotRouter is returned by RegisterPrompt when the prompt
+// handler registered with RegisterPromptHandler is not a PromptRouter, so
+// prompts cannot be added to it.
+var ErrPromptHandlerNotRouter = errors.New("prompt handler is not a PromptRouter")
+
+// RegisterPrompt adds prompt, rendered by get. Without a prompt handler the
+// first call installs a PromptRouter; later calls, or calls after
+// RegisterPromptHandler with a PromptRouter, add prompts to that router.
+// Any other prompt handler is left alone and ErrPromptHandlerNotRouter is
+// returned.
+func (r *HandlerRegistry) RegisterPrompt(prompt protocol.Prompt, get PromptFunc) error {
+	r.mu.Lock()
+	router, ok := r.promptHandler.(*PromptRouter)
+	if !ok {
+		if r.promptHandler != nil {
+			r.mu.Unlock()
+			return ErrPromptHandlerNotRouter
+		}
+		router = NewPromptRouter()
+		r.promptHandler = router
+	}
+	r.mu.Unlock()
+	router.Register(prompt, get)
+	return nil
+}
+
+// UnregisterPrompt removes a prompt added with RegisterPrompt and reports
+// whether it existed
+func (r *HandlerRegistry) UnregisterPrompt(name string) bool {
+	r.mu.RLock()
+	router, ok := r.promptHandler.(*PromptRouter)
+	r.mu.RUnlock()
+	return ok && router.Remove(name)
+}
+
+// RegisterHandler registers a generic handler, replacing any existing one.
+// The server routes methods it does not implement itself to its
+// HandleRequest, making it a catch-all for custom methods. Initialize is not
+// called; the server answers initialize on its own.
+func (r *HandlerRegistry) RegisterHandler(h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handler = h
+}
+
+// UnregisterToolHandler removes the tool handler
+func (r *HandlerRegistry) UnregisterToolHandler() {
+	r.RegisterToolHandler(nil)
+}
+
+// UnregisterResourceHandler removes the resource handler
+func (r *HandlerRegistry) UnregisterResourceHandler() {
+	r.RegisterResourceHandler(nil)
+}
+
+// UnregisterPromptHandler removes the prompt handler
+func (r *HandlerRegistry) UnregisterPromptHandler() {
+	r.RegisterPromptHandler(nil)
+}
+
+// UnregisterHandler removes the generic handler
+func (r *HandlerRegistry) UnregisterHandler() {
+	r.RegisterHandler(nil)
+}
+
 // GetToolHandler returns the registered tool handler
 func (r *HandlerRegistry) GetToolHandler() ToolHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.toolHandler
 }
 
 // GetResourceHandler returns the registered resource handler
 func (r *HandlerRegistry) GetResourceHandler() ResourceHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.resourceHandler
 }
 
 // GetPromptHandler returns the registered prompt handler
 func (r *HandlerRegistry) GetPromptHandler() PromptHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.promptHandler
 }
 
+// GetHandler returns the registered generic handler
+func (r *HandlerRegistry) GetHandler() Handler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.handler
+}
+
 // HasToolHandler checks if a tool handler is registered
 func (r *HandlerRegistry) HasToolHandler() bool {
-	return r.toolHandler != nil
+	return r.GetToolHandler() != nil
 }
 
 // HasResourceHandler checks if a resource handler is registered
 func (r *HandlerRegistry) HasResourceHandler() bool {
-	return r.resourceHandler != nil
+	return r.GetResourceHandler() != nil
 }
 
 // HasPromptHandler checks if a prompt handler is registered
 func (r *HandlerRegistry) HasPromptHandler() bool {
-	return r.promptHandler != nil
-}
\ No newline at end of file
+	return r.GetPromptHandler() != nil
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/gomcpgo/mcp/pkg/protocol"
@@ -148,3 +149,64 @@ func TestHandlerRegistry(t *testing.T) {
 		t.Error("GetPrompt() was not called on prompt handler")
 	}
 }
+
+func TestHandlerRegistryUnregister(t *testing.T) {
+	registry := NewHandlerRegistry()
+	registry.RegisterToolHandler(&mockToolHandler{})
+	registry.RegisterResourceHandler(&mockResourceHandler{})
+	registry.RegisterPromptHandler(&mockPromptHandler{})
+
+	registry.UnregisterToolHandler()
+	registry.UnregisterResourceHandler()
+	registry.UnregisterPromptHandler()
+
+	if registry.HasToolHandler() {
+		t.Error("expected no tool handler after unregister")
+	}
+	if registry.HasResourceHandler() {
+		t.Error("expected no resource handler after unregister")
+	}
+	if registry.HasPromptHandler() {
+		t.Error("expected no prompt handler after unregister")
+	}
+}
+
+func TestHandlerRegistryReplace(t *testing.T) {
+	registry := NewHandlerRegistry()
+	first := &mockToolHandler{}
+	second := &mockToolHandler{}
+
+	registry.RegisterToolHandler(first)
+	registry.RegisterToolHandler(second)
+
+	if got := registry.GetToolHandler(); got != second {
+		t.Error("RegisterToolHandler did not replace the existing handler")
+	}
+}
+
+func TestHandlerRegistryConcurrentAccess(t *testing.T) {
+	registry := NewHandlerRegistry()
+	h := &mockToolHandler{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				registry.RegisterToolHandler(h)
+				registry.UnregisterToolHandler()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got := registry.GetToolHandler(); got != nil && got != h {
+					t.Error("GetToolHandler returned an unexpected handler")
+				}
+				registry.HasToolHandler()
+			}
+		}()
+	}
+	wg.Wait()
+}
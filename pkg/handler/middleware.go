@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// CallFunc is the signature of ToolHandler.CallTool, used as the unit that
+// ToolMiddleware wraps.
+type CallFunc func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error)
+
+// ToolMiddleware intercepts tools/call after the params have been parsed.
+// It may inspect or rewrite the request (inject default arguments, rename
+// the tool), short-circuit with its own response, or post-process the result
+// returned by next.
+type ToolMiddleware func(next CallFunc) CallFunc
+
+// ChainToolMiddleware wraps call with mws so that mws[0] is the outermost
+// layer, i.e. the first to see the request and the last to see the response.
+func ChainToolMiddleware(call CallFunc, mws ...ToolMiddleware) CallFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		call = mws[i](call)
+	}
+	return call
+}
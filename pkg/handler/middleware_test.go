@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestChainToolMiddleware_Order(t *testing.T) {
+	var order []string
+	record := func(name string) ToolMiddleware {
+		return func(next CallFunc) CallFunc {
+			return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+				order = append(order, name+":before")
+				resp, err := next(ctx, req)
+				order = append(order, name+":after")
+				return resp, err
+			}
+		}
+	}
+	base := func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		order = append(order, "handler")
+		return &protocol.CallToolResponse{}, nil
+	}
+
+	call := ChainToolMiddleware(base, record("outer"), record("inner"))
+	if _, err := call(context.Background(), &protocol.CallToolRequest{}); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+
+	want := []string{"outer:before", "inner:before", "handler", "inner:after", "outer:after"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
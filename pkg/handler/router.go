@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// ToolRouter is a ToolHandler that dispatches each tools/call to the CallFunc
+// registered under the tool's name. Tools are listed in registration order.
+// It is safe for concurrent use.
+type ToolRouter struct {
+	mu    sync.RWMutex
+	tools []protocol.Tool
+	calls map[string]CallFunc
+}
+
+// NewToolRouter creates an empty tool router
+func NewToolRouter() *ToolRouter {
+	return &ToolRouter{calls: make(map[string]CallFunc)}
+}
+
+// Register adds tool, answered by call. Registering a name again replaces
+// the previous definition in place.
+func (r *ToolRouter) Register(tool protocol.Tool, call CallFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.calls[tool.Name]; exists {
+		for i := range r.tools {
+			if r.tools[i].Name == tool.Name {
+				r.tools[i] = tool
+			}
+		}
+	} else {
+		r.tools = append(r.tools, tool)
+	}
+	r.calls[tool.Name] = call
+}
+
+// ListTools returns the registered tools
+func (r *ToolRouter) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tools := make([]protocol.Tool, len(r.tools))
+	copy(tools, r.tools)
+	return &protocol.ListToolsResponse{Tools: tools}, nil
+}
+
+// CallTool runs the CallFunc registered for req.Name. Unknown tools are
+// rejected with InvalidParams, as the MCP spec requires.
+func (r *ToolRouter) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	r.mu.RLock()
+	call, ok := r.calls[req.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &protocol.Error{
+			Code:    protocol.InvalidParams,
+			Message: fmt.Sprintf("unknown tool: %s", req.Name),
+		}
+	}
+	return call(ctx, req)
+}
+
+// ResourceRouter is a ResourceHandler that dispatches resources/read to the
+// handler registered for the URI's scheme, so each handler only deals with
+// its own kind of resource. ListResources aggregates every handler's list in
+// registration order. It is safe for concurrent use.
+type ResourceRouter struct {
+	mu       sync.RWMutex
+	schemes  []string
+	handlers map[string]ResourceHandler
+}
+
+// NewResourceRouter creates an empty resource router
+func NewResourceRouter() *ResourceRouter {
+	return &ResourceRouter{handlers: make(map[string]ResourceHandler)}
+}
+
+// RegisterResourceScheme routes URIs with the given scheme (e.g. "file",
+// "db"; matched case-insensitively) to h, replacing any previous handler
+// for that scheme.
+func (r *ResourceRouter) RegisterResourceScheme(scheme string, h ResourceHandler) {
+	scheme = strings.ToLower(scheme)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.handlers[scheme]; !exists {
+		r.schemes = append(r.schemes, scheme)
+	}
+	r.handlers[scheme] = h
+}
+
+// ListResources returns the resources of every registered scheme handler
+func (r *ResourceRouter) ListResources(ctx context.Context) (*protocol.ListResourcesResponse, error) {
+	r.mu.RLock()
+	handlers := make([]ResourceHandler, len(r.schemes))
+	for i, scheme := range r.schemes {
+		handlers[i] = r.handlers[scheme]
+	}
+	schemes := append([]string(nil), r.schemes...)
+	r.mu.RUnlock()
+
+	resources := []protocol.Resource{}
+	for i, h := range handlers {
+		list, err := h.ListResources(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list %s resources: %w", schemes[i], err)
+		}
+		if list != nil {
+			resources = append(resources, list.Resources...)
+		}
+	}
+	return &protocol.ListResourcesResponse{Resources: resources}, nil
+}
+
+// ReadResource passes req to the handler for its URI's scheme. URIs with no
+// registered scheme are rejected with MethodNotFound.
+func (r *ResourceRouter) ReadResource(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResponse, error) {
+	h, err := r.route(req.URI)
+	if err != nil {
+		return nil, err
+	}
+	return h.ReadResource(ctx, req)
+}
+
+// ReadResourceStream passes a streamed read to the handler for the URI's
+// scheme, making the router a StreamingResourceHandler. A handler that
+// cannot stream answers with a plain ReadResource instead.
+func (r *ResourceRouter) ReadResourceStream(ctx context.Context, req *protocol.ReadResourceRequest, emit func(protocol.ResourceContent) error) (*protocol.ReadResourceResponse, error) {
+	h, err := r.route(req.URI)
+	if err != nil {
+		return nil, err
+	}
+	if streamer, ok := h.(StreamingResourceHandler); ok {
+		return streamer.ReadResourceStream(ctx, req, emit)
+	}
+	return h.ReadResource(ctx, req)
+}
+
+// route returns the handler registered for uri's scheme
+func (r *ResourceRouter) route(uri string) (ResourceHandler, error) {
+	scheme, _, ok := strings.Cut(uri, ":")
+	if !ok || scheme == "" {
+		return nil, &protocol.Error{
+			Code:    protocol.InvalidParams,
+			Message: fmt.Sprintf("resource URI %q has no scheme", uri),
+		}
+	}
+
+	r.mu.RLock()
+	h, ok := r.handlers[strings.ToLower(scheme)]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &protocol.Error{
+			Code:    protocol.MethodNotFound,
+			Message: fmt.Sprintf("no resource handler for scheme %q", scheme),
+		}
+	}
+	return h, nil
+}
+
+// PromptFunc renders one prompt; it has the signature of
+// PromptHandler.GetPrompt.
+type PromptFunc func(ctx context.Context, req *protocol.GetPromptRequest) (*protocol.GetPromptResponse, error)
+
+// PromptRouter is a PromptHandler that dispatches each prompts/get to the
+// PromptFunc registered under the prompt's name. Prompts can be added and
+// removed at runtime and are listed in registration order. It is safe for
+// concurrent use.
+type PromptRouter struct {
+	mu      sync.RWMutex
+	prompts []protocol.Prompt
+	gets    map[string]PromptFunc
+}
+
+// NewPromptRouter creates an empty prompt router
+func NewPromptRouter() *PromptRouter {
+	return &PromptRouter{gets: make(map[string]PromptFunc)}
+}
+
+// Register adds prompt, rendered by get. Registering a name again replaces
+// the previous definition in place.
+func (r *PromptRouter) Register(prompt protocol.Prompt, get PromptFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.gets[prompt.Name]; exists {
+		for i := range r.prompts {
+			if r.prompts[i].Name == prompt.Name {
+				r.prompts[i] = prompt
+			}
+		}
+	} else {
+		r.prompts = append(r.prompts, prompt)
+	}
+	r.gets[prompt.Name] = get
+}
+
+// Remove deletes the prompt called name and reports whether it existed
+func (r *PromptRouter) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.gets[name]; !exists {
+		return false
+	}
+	delete(r.gets, name)
+	for i := range r.prompts {
+		if r.prompts[i].Name == name {
+			r.prompts = append(r.prompts[:i], r.prompts[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// ListPrompts returns the registered prompts
+func (r *PromptRouter) ListPrompts(ctx context.Context) (*protocol.ListPromptsResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prompts := make([]protocol.Prompt, len(r.prompts))
+	copy(prompts, r.prompts)
+	return &protocol.ListPromptsResponse{Prompts: prompts}, nil
+}
+
+// GetPrompt runs the PromptFunc registered for req.Name. Unknown prompts are
+// rejected with InvalidParams, as the MCP spec requires.
+func (r *PromptRouter) GetPrompt(ctx context.Context, req *protocol.GetPromptRequest) (*protocol.GetPromptResponse, error) {
+	r.mu.RLock()
+	get, ok := r.gets[req.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &protocol.Error{
+			Code:    protocol.InvalidParams,
+			Message: fmt.Sprintf("unknown prompt: %s", req.Name),
+		}
+	}
+	return get(ctx, req)
+}
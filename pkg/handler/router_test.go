@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func echoCall(text string) CallFunc {
+	return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: text}}}, nil
+	}
+}
+
+func TestToolRouter_ListAndCall(t *testing.T) {
+	router := NewToolRouter()
+	router.Register(protocol.Tool{Name: "b"}, echoCall("b1"))
+	router.Register(protocol.Tool{Name: "a"}, echoCall("a"))
+	router.Register(protocol.Tool{Name: "b", Description: "replaced"}, echoCall("b2"))
+
+	list, err := router.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(list.Tools) != 2 || list.Tools[0].Name != "b" || list.Tools[1].Name != "a" {
+		t.Fatalf("tools = %+v, want [b a] in registration order", list.Tools)
+	}
+	if list.Tools[0].Description != "replaced" {
+		t.Errorf("re-registering b did not replace its definition")
+	}
+
+	resp, err := router.CallTool(context.Background(), &protocol.CallToolRequest{Name: "b"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if resp.Content[0].Text != "b2" {
+		t.Errorf("text = %q, want b2", resp.Content[0].Text)
+	}
+}
+
+func TestToolRouter_UnknownTool(t *testing.T) {
+	router := NewToolRouter()
+	_, err := router.CallTool(context.Background(), &protocol.CallToolRequest{Name: "missing"})
+	var rpcErr *protocol.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != protocol.InvalidParams {
+		t.Fatalf("err = %v, want InvalidParams protocol error", err)
+	}
+}
+
+// schemeResourceHandler serves a single resource and echoes the read URI.
+type schemeResourceHandler struct {
+	uri string
+}
+
+func (h schemeResourceHandler) ListResources(ctx context.Context) (*protocol.ListResourcesResponse, error) {
+	return &protocol.ListResourcesResponse{Resources: []protocol.Resource{{URI: h.uri, Name: h.uri}}}, nil
+}
+
+func (h schemeResourceHandler) ReadResource(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResponse, error) {
+	return &protocol.ReadResourceResponse{Contents: []protocol.ResourceContent{{URI: req.URI, Text: h.uri}}}, nil
+}
+
+func TestHandlerRegistry_RegisterResourceScheme(t *testing.T) {
+	registry := NewHandlerRegistry()
+	registry.RegisterResourceScheme("file", schemeResourceHandler{uri: "file:///a.txt"})
+	registry.RegisterResourceScheme("db", schemeResourceHandler{uri: "db://users"})
+	h := registry.GetResourceHandler()
+
+	list, err := h.ListResources(context.Background())
+	if err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	if len(list.Resources) != 2 || list.Resources[0].URI != "file:///a.txt" || list.Resources[1].URI != "db://users" {
+		t.Errorf("resources = %+v, want file then db", list.Resources)
+	}
+
+	for uri, want := range map[string]string{
+		"file:///etc/hosts": "file:///a.txt",
+		"DB://orders":       "db://users",
+	} {
+		resp, err := h.ReadResource(context.Background(), &protocol.ReadResourceRequest{URI: uri})
+		if err != nil {
+			t.Fatalf("ReadResource(%s): %v", uri, err)
+		}
+		if resp.Contents[0].Text != want {
+			t.Errorf("ReadResource(%s) served by %q, want %q", uri, resp.Contents[0].Text, want)
+		}
+	}
+
+	_, err = h.ReadResource(context.Background(), &protocol.ReadResourceRequest{URI: "http://example.com"})
+	var rpcErr *protocol.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != protocol.MethodNotFound {
+		t.Errorf("unknown scheme: err = %v, want MethodNotFound", err)
+	}
+}
+
+// streamingSchemeHandler streams its resource as two chunks
+type streamingSchemeHandler struct {
+	schemeResourceHandler
+}
+
+func (h streamingSchemeHandler) ReadResourceStream(ctx context.Context, req *protocol.ReadResourceRequest, emit func(protocol.ResourceContent) error) (*protocol.ReadResourceResponse, error) {
+	for _, part := range []string{"part1", "part2"} {
+		if err := emit(protocol.ResourceContent{URI: req.URI, Text: part}); err != nil {
+			return nil, err
+		}
+	}
+	return &protocol.ReadResourceResponse{Contents: []protocol.ResourceContent{}}, nil
+}
+
+func TestResourceRouter_ForwardsStreaming(t *testing.T) {
+	router := NewResourceRouter()
+	router.RegisterResourceScheme("big", streamingSchemeHandler{schemeResourceHandler{uri: "big://blob"}})
+	router.RegisterResourceScheme("file", schemeResourceHandler{uri: "file:///a.txt"})
+
+	var h ResourceHandler = router
+	streamer, ok := h.(StreamingResourceHandler)
+	if !ok {
+		t.Fatal("ResourceRouter does not implement StreamingResourceHandler")
+	}
+
+	var chunks []string
+	emit := func(c protocol.ResourceContent) error {
+		chunks = append(chunks, c.Text)
+		return nil
+	}
+	if _, err := streamer.ReadResourceStream(context.Background(), &protocol.ReadResourceRequest{URI: "big://blob"}, emit); err != nil {
+		t.Fatalf("ReadResourceStream: %v", err)
+	}
+	if len(chunks) != 2 || chunks[0] != "part1" || chunks[1] != "part2" {
+		t.Errorf("chunks = %v, want the streaming handler's two parts", chunks)
+	}
+
+	// A scheme whose handler cannot stream is read whole
+	chunks = nil
+	resp, err := streamer.ReadResourceStream(context.Background(), &protocol.ReadResourceRequest{URI: "file:///b.txt"}, emit)
+	if err != nil {
+		t.Fatalf("ReadResourceStream(file): %v", err)
+	}
+	if len(chunks) != 0 || len(resp.Contents) != 1 || resp.Contents[0].Text != "file:///a.txt" {
+		t.Errorf("file read = %+v with chunks %v, want a plain read", resp, chunks)
+	}
+}
+
+func TestPromptRouter_RegisterAndRemove(t *testing.T) {
+	router := NewPromptRouter()
+	router.Register(protocol.Prompt{Name: "greet"}, func(ctx context.Context, req *protocol.GetPromptRequest) (*protocol.GetPromptResponse, error) {
+		return &protocol.GetPromptResponse{Messages: []protocol.Message{{Role: "user", Content: protocol.MessageContent{Type: "text", Text: "hello"}}}}, nil
+	})
+	router.Register(protocol.Prompt{Name: "summarize"}, nil)
+
+	resp, err := router.GetPrompt(context.Background(), &protocol.GetPromptRequest{Name: "greet"})
+	if err != nil || resp.Messages[0].Content.Text != "hello" {
+		t.Fatalf("GetPrompt = %+v, %v", resp, err)
+	}
+
+	if !router.Remove("greet") {
+		t.Fatal("Remove(greet) = false, want true")
+	}
+	if router.Remove("greet") {
+		t.Error("removing twice should report false")
+	}
+	list, _ := router.ListPrompts(context.Background())
+	if len(list.Prompts) != 1 || list.Prompts[0].Name != "summarize" {
+		t.Errorf("prompts = %+v, want [summarize]", list.Prompts)
+	}
+
+	_, err = router.GetPrompt(context.Background(), &protocol.GetPromptRequest{Name: "greet"})
+	var rpcErr *protocol.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("err = %v, want InvalidParams for a removed prompt", err)
+	}
+}
+
+func TestHandlerRegistry_RegisterPrompt(t *testing.T) {
+	registry := NewHandlerRegistry()
+	if err := registry.RegisterPrompt(protocol.Prompt{Name: "greet"}, nil); err != nil {
+		t.Fatalf("RegisterPrompt without a handler: %v", err)
+	}
+	if _, ok := registry.GetPromptHandler().(*PromptRouter); !ok {
+		t.Fatalf("prompt handler = %T, want *PromptRouter", registry.GetPromptHandler())
+	}
+
+	custom := &mockPromptHandler{}
+	registry.RegisterPromptHandler(custom)
+	if err := registry.RegisterPrompt(protocol.Prompt{Name: "summarize"}, nil); !errors.Is(err, ErrPromptHandlerNotRouter) {
+		t.Errorf("err = %v, want ErrPromptHandlerNotRouter", err)
+	}
+	if registry.GetPromptHandler() != custom {
+		t.Error("a failed RegisterPrompt must keep the registered handler")
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// StaticTool declares a tool together with the function that answers it,
+// for servers that do not need a ToolHandler type of their own
+type StaticTool struct {
+	Tool    protocol.Tool
+	Handler CallFunc
+}
+
+// StaticResource declares a resource with fixed contents. Contents with an
+// empty URI are served under Resource.URI.
+type StaticResource struct {
+	Resource protocol.Resource
+	Contents []protocol.ResourceContent
+}
+
+// StaticPrompt declares a prompt together with the function that renders it
+type StaticPrompt struct {
+	Prompt  protocol.Prompt
+	Handler PromptFunc
+}
+
+// RegisterStaticTools adds tools, routed by name. The first call installs a
+// ToolRouter as the tool handler, replacing any handler registered with
+// RegisterToolHandler; later calls add tools to that router.
+func (r *HandlerRegistry) RegisterStaticTools(tools []StaticTool) {
+	r.mu.Lock()
+	router, ok := r.toolHandler.(*ToolRouter)
+	if !ok {
+		router = NewToolRouter()
+		r.toolHandler = router
+	}
+	r.mu.Unlock()
+	for _, t := range tools {
+		router.Register(t.Tool, t.Handler)
+	}
+}
+
+// RegisterStaticResources adds resources with fixed contents, read by exact
+// URI. The first call installs a handler for them, replacing any handler
+// registered with RegisterResourceHandler; later calls add to it.
+func (r *HandlerRegistry) RegisterStaticResources(resources []StaticResource) {
+	r.mu.Lock()
+	h, ok := r.resourceHandler.(*staticResourceHandler)
+	if !ok {
+		h = &staticResourceHandler{contents: make(map[string][]protocol.ResourceContent)}
+		r.resourceHandler = h
+	}
+	r.mu.Unlock()
+	for _, res := range resources {
+		h.add(res)
+	}
+}
+
+// RegisterStaticPrompts adds prompts, routed by name, as RegisterPrompt does
+// for each of them, stopping at the first error
+func (r *HandlerRegistry) RegisterStaticPrompts(prompts []StaticPrompt) error {
+	for _, p := range prompts {
+		if err := r.RegisterPrompt(p.Prompt, p.Handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// staticResourceHandler serves StaticResources in registration order
+type staticResourceHandler struct {
+	mu        sync.RWMutex
+	resources []protocol.Resource
+	contents  map[string][]protocol.ResourceContent
+}
+
+// add registers res, replacing a resource with the same URI in place
+func (h *staticResourceHandler) add(res StaticResource) {
+	contents := make([]protocol.ResourceContent, len(res.Contents))
+	for i, c := range res.Contents {
+		if c.URI == "" {
+			c.URI = res.Resource.URI
+		}
+		contents[i] = c
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.contents[res.Resource.URI]; exists {
+		for i := range h.resources {
+			if h.resources[i].URI == res.Resource.URI {
+				h.resources[i] = res.Resource
+			}
+		}
+	} else {
+		h.resources = append(h.resources, res.Resource)
+	}
+	h.contents[res.Resource.URI] = contents
+}
+
+func (h *staticResourceHandler) ListResources(ctx context.Context) (*protocol.ListResourcesResponse, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	resources := make([]protocol.Resource, len(h.resources))
+	copy(resources, h.resources)
+	return &protocol.ListResourcesResponse{Resources: resources}, nil
+}
+
+// ReadResource returns the fixed contents for req.URI, or ResourceNotFound
+func (h *staticResourceHandler) ReadResource(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResponse, error) {
+	h.mu.RLock()
+	contents, ok := h.contents[req.URI]
+	h.mu.RUnlock()
+	if !ok {
+		return nil, &protocol.Error{
+			Code:    protocol.ResourceNotFound,
+			Message: fmt.Sprintf("resource not found: %s", req.URI),
+			Data:    map[string]interface{}{"uri": req.URI},
+		}
+	}
+	return &protocol.ReadResourceResponse{Contents: append([]protocol.ResourceContent(nil), contents...)}, nil
+}
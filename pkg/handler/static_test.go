@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestRegisterStaticTools(t *testing.T) {
+	registry := NewHandlerRegistry()
+	registry.RegisterStaticTools([]StaticTool{
+		{Tool: protocol.Tool{Name: "greet"}, Handler: func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+			name, _ := req.Arguments["name"].(string)
+			return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: "hello " + name}}}, nil
+		}},
+		{Tool: protocol.Tool{Name: "noop"}, Handler: echoCall("")},
+	})
+	registry.RegisterStaticTools([]StaticTool{{Tool: protocol.Tool{Name: "later"}, Handler: echoCall("later")}})
+
+	tools := registry.GetToolHandler()
+	list, err := tools.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(list.Tools) != 3 || list.Tools[0].Name != "greet" || list.Tools[2].Name != "later" {
+		t.Errorf("tools = %+v, want greet, noop, later", list.Tools)
+	}
+
+	resp, err := tools.CallTool(context.Background(), &protocol.CallToolRequest{Name: "greet", Arguments: map[string]interface{}{"name": "ada"}})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if resp.Content[0].Text != "hello ada" {
+		t.Errorf("text = %q, want %q", resp.Content[0].Text, "hello ada")
+	}
+}
+
+func TestRegisterStaticResources(t *testing.T) {
+	registry := NewHandlerRegistry()
+	registry.RegisterStaticResources([]StaticResource{{
+		Resource: protocol.Resource{URI: "config://app", Name: "app config"},
+		Contents: []protocol.ResourceContent{{MimeType: "application/json", Text: `{"debug":false}`}},
+	}})
+
+	resources := registry.GetResourceHandler()
+	resp, err := resources.ReadResource(context.Background(), &protocol.ReadResourceRequest{URI: "config://app"})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(resp.Contents) != 1 || resp.Contents[0].URI != "config://app" || resp.Contents[0].Text != `{"debug":false}` {
+		t.Errorf("contents = %+v, want the static JSON under the resource URI", resp.Contents)
+	}
+
+	_, err = resources.ReadResource(context.Background(), &protocol.ReadResourceRequest{URI: "config://other"})
+	var rpcErr *protocol.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != protocol.ResourceNotFound {
+		t.Errorf("err = %v, want ResourceNotFound", err)
+	}
+}
@@ -0,0 +1,119 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DecodeArguments decodes req.Arguments into v (a pointer to a struct, map,
+// etc.) by round-tripping through JSON, so handlers get typed inputs instead
+// of type-asserting map entries. A type mismatch between an argument and the
+// target field is returned as an error rather than panicking.
+func DecodeArguments(req *CallToolRequest, v interface{}) error {
+	if req == nil {
+		return fmt.Errorf("decode arguments: nil request")
+	}
+	return decodeArguments(req.Arguments, v)
+}
+
+// UnmarshalArguments decodes a tools/call or prompts/get arguments map into a
+// new T. JSON numbers arrive as float64; the round trip through JSON lets
+// them land in int fields as long as they are whole.
+func UnmarshalArguments[T any](args map[string]interface{}) (T, error) {
+	var v T
+	err := decodeArguments(args, &v)
+	return v, err
+}
+
+func decodeArguments(args map[string]interface{}, v interface{}) error {
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("decode arguments: %w", err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("decode arguments: %w", err)
+	}
+	return nil
+}
+
+// ResourceArgumentKey marks a tools/call argument that references a
+// resource instead of carrying its value inline: {"$resource": "<uri>"}.
+// A server with resource argument resolution enabled replaces it with the
+// resource's content before the tool runs, so large inputs can be uploaded
+// out of band rather than sent in one giant message.
+const ResourceArgumentKey = "$resource"
+
+// ResourceArgument returns an argument value referencing the resource at uri
+func ResourceArgument(uri string) map[string]interface{} {
+	return map[string]interface{}{ResourceArgumentKey: uri}
+}
+
+// ResourceArgumentURI reports whether v is a resource reference made by
+// ResourceArgument, and if so the URI it points at.
+func ResourceArgumentURI(v interface{}) (string, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	uri, ok := m[ResourceArgumentKey].(string)
+	return uri, ok
+}
+
+// GetString returns the string argument name, or def if it is missing or not
+// a string.
+func (r *CallToolRequest) GetString(name, def string) string {
+	if v, ok := r.Arguments[name].(string); ok {
+		return v
+	}
+	return def
+}
+
+// GetInt returns the integer argument name, or def if it is missing, not a
+// number, or has a fractional part. JSON numbers decode as float64, so whole
+// float64 values are accepted.
+func (r *CallToolRequest) GetInt(name string, def int) int {
+	switch v := r.Arguments[name].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		if v == float64(int(v)) {
+			return int(v)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n)
+		}
+	}
+	return def
+}
+
+// GetBool returns the boolean argument name, or def if it is missing or not
+// a bool.
+func (r *CallToolRequest) GetBool(name string, def bool) bool {
+	if v, ok := r.Arguments[name].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// GetStringSlice returns the argument name as a []string, or nil if it is
+// missing, not an array, or contains a non-string element.
+func (r *CallToolRequest) GetStringSlice(name string) []string {
+	switch v := r.Arguments[name].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil
+			}
+			out = append(out, s)
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+type decodeTarget struct {
+	Prompt string   `json:"prompt"`
+	Count  int      `json:"count"`
+	Force  bool     `json:"force"`
+	Tags   []string `json:"tags"`
+}
+
+func TestDecodeArguments(t *testing.T) {
+	req := &CallToolRequest{
+		Name: "generate",
+		Arguments: map[string]interface{}{
+			"prompt": "a cat",
+			"count":  float64(3), // numbers arrive as float64 after JSON decode
+			"force":  true,
+			"tags":   []interface{}{"a", "b"},
+		},
+	}
+
+	var got decodeTarget
+	if err := DecodeArguments(req, &got); err != nil {
+		t.Fatalf("DecodeArguments: %v", err)
+	}
+
+	want := decodeTarget{Prompt: "a cat", Count: 3, Force: true, Tags: []string{"a", "b"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeArguments_MissingFieldsKeepZeroValues(t *testing.T) {
+	req := &CallToolRequest{Arguments: map[string]interface{}{"prompt": "x"}}
+
+	got := decodeTarget{Count: 7}
+	if err := DecodeArguments(req, &got); err != nil {
+		t.Fatalf("DecodeArguments: %v", err)
+	}
+	if got.Prompt != "x" || got.Count != 7 {
+		t.Errorf("decoded = %+v, want prompt=x and count untouched", got)
+	}
+}
+
+func TestDecodeArguments_TypeMismatch(t *testing.T) {
+	req := &CallToolRequest{
+		Arguments: map[string]interface{}{"prompt": float64(42)},
+	}
+
+	var got decodeTarget
+	if err := DecodeArguments(req, &got); err == nil {
+		t.Fatal("expected error decoding a number into a string field")
+	}
+}
+
+func TestCallToolRequestAccessors(t *testing.T) {
+	req := &CallToolRequest{
+		Arguments: map[string]interface{}{
+			"name":      "alice",
+			"count":     float64(5),
+			"ratio":     float64(1.5),
+			"verbose":   true,
+			"tags":      []interface{}{"a", "b"},
+			"mixed":     []interface{}{"a", float64(1)},
+			"wrongStr":  float64(1),
+			"wrongInt":  "five",
+			"wrongBool": "yes",
+		},
+	}
+
+	// Present values
+	if got := req.GetString("name", "def"); got != "alice" {
+		t.Errorf("GetString(name) = %q, want alice", got)
+	}
+	if got := req.GetInt("count", -1); got != 5 {
+		t.Errorf("GetInt(count) = %d, want 5", got)
+	}
+	if got := req.GetBool("verbose", false); !got {
+		t.Error("GetBool(verbose) = false, want true")
+	}
+	if got := req.GetStringSlice("tags"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("GetStringSlice(tags) = %v, want [a b]", got)
+	}
+
+	// Missing values fall back to the default
+	if got := req.GetString("missing", "def"); got != "def" {
+		t.Errorf("GetString(missing) = %q, want def", got)
+	}
+	if got := req.GetInt("missing", 9); got != 9 {
+		t.Errorf("GetInt(missing) = %d, want 9", got)
+	}
+	if got := req.GetBool("missing", true); !got {
+		t.Error("GetBool(missing) = false, want default true")
+	}
+	if got := req.GetStringSlice("missing"); got != nil {
+		t.Errorf("GetStringSlice(missing) = %v, want nil", got)
+	}
+
+	// Wrong types fall back to the default
+	if got := req.GetString("wrongStr", "def"); got != "def" {
+		t.Errorf("GetString(wrongStr) = %q, want def", got)
+	}
+	if got := req.GetInt("wrongInt", 9); got != 9 {
+		t.Errorf("GetInt(wrongInt) = %d, want 9", got)
+	}
+	if got := req.GetInt("ratio", 9); got != 9 {
+		t.Errorf("GetInt(ratio) = %d, want default for fractional value", got)
+	}
+	if got := req.GetBool("wrongBool", false); got {
+		t.Error("GetBool(wrongBool) = true, want default false")
+	}
+	if got := req.GetStringSlice("mixed"); got != nil {
+		t.Errorf("GetStringSlice(mixed) = %v, want nil", got)
+	}
+
+	// Nil arguments map is safe
+	empty := &CallToolRequest{}
+	if got := empty.GetString("name", "def"); got != "def" {
+		t.Errorf("GetString on nil arguments = %q, want def", got)
+	}
+}
+
+type promptArgs struct {
+	Topic   string `json:"topic"`
+	Options struct {
+		MaxWords int     `json:"maxWords"`
+		Ratio    float64 `json:"ratio"`
+	} `json:"options"`
+}
+
+func TestUnmarshalArguments_NestedAndNumeric(t *testing.T) {
+	args := map[string]interface{}{
+		"topic": "go",
+		"options": map[string]interface{}{
+			"maxWords": float64(250),
+			"ratio":    0.75,
+		},
+	}
+
+	got, err := UnmarshalArguments[promptArgs](args)
+	if err != nil {
+		t.Fatalf("UnmarshalArguments: %v", err)
+	}
+	if got.Topic != "go" {
+		t.Errorf("Topic = %q, want go", got.Topic)
+	}
+	if got.Options.MaxWords != 250 {
+		t.Errorf("MaxWords = %d, want 250", got.Options.MaxWords)
+	}
+	if got.Options.Ratio != 0.75 {
+		t.Errorf("Ratio = %v, want 0.75", got.Options.Ratio)
+	}
+}
+
+func TestUnmarshalArguments_Errors(t *testing.T) {
+	if _, err := UnmarshalArguments[promptArgs](map[string]interface{}{
+		"options": map[string]interface{}{"maxWords": 2.5},
+	}); err == nil {
+		t.Error("expected error decoding a fractional number into an int field")
+	}
+	if _, err := UnmarshalArguments[promptArgs](map[string]interface{}{
+		"topic": []interface{}{"not", "a", "string"},
+	}); err == nil {
+		t.Error("expected error decoding an array into a string field")
+	}
+}
+
+func TestUnmarshalArguments_NilMap(t *testing.T) {
+	got, err := UnmarshalArguments[promptArgs](nil)
+	if err != nil {
+		t.Fatalf("UnmarshalArguments(nil): %v", err)
+	}
+	if got.Topic != "" {
+		t.Errorf("expected zero value, got %+v", got)
+	}
+}
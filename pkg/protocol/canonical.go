@@ -0,0 +1,28 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CanonicalJSON encodes v so that equal values always produce identical
+// bytes: every object's keys are sorted, struct fields included, and there
+// is no insignificant whitespace. Numbers keep the representation they
+// marshal to. Strings are escaped as encoding/json does, so the output
+// survives being embedded by json.Marshal unchanged.
+func CanonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decoding into interface{} turns every object into a map, which
+	// encoding/json writes with sorted keys
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
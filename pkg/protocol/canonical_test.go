@@ -0,0 +1,27 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	resp := &CallToolResponse{
+		Content: []ToolContent{{Type: "text", Text: "a <b>"}},
+		StructuredContent: map[string]interface{}{
+			"zeta": 1, "alpha": []interface{}{3, 2.5}, "mid": map[string]interface{}{"y": true, "x": nil},
+		},
+		Meta: map[string]interface{}{"big": 12345678901234567},
+	}
+
+	want := `{"_meta":{"big":12345678901234567},"content":[{"text":"a \u003cb\u003e","type":"text"}],` +
+		`"structuredContent":{"alpha":[3,2.5],"mid":{"x":null,"y":true},"zeta":1}}`
+	for i := 0; i < 20; i++ {
+		got, err := CanonicalJSON(resp)
+		if err != nil {
+			t.Fatalf("CanonicalJSON: %v", err)
+		}
+		if string(got) != want {
+			t.Fatalf("run %d:\n got %s\nwant %s", i, got, want)
+		}
+	}
+}
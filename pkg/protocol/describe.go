@@ -0,0 +1,19 @@
+package protocol
+
+// MethodDescribe is the non-standard introspection request a server answers
+// when introspection is enabled. Its result is a DescribeResponse.
+const MethodDescribe = "x-mcp/describe"
+
+// DescribeResponse summarises a running server for operators and debugging
+// UIs: what it serves and how long it has been up, without the client having
+// to initialize and list every capability.
+type DescribeResponse struct {
+	ServerInfo      ServerInfo   `json:"serverInfo"`
+	LibraryVersion  string       `json:"libraryVersion"`
+	GoVersion       string       `json:"goVersion"`
+	UptimeSeconds   float64      `json:"uptimeSeconds"`
+	Capabilities    Capabilities `json:"capabilities"`
+	ToolCount       int          `json:"toolCount"`
+	ResourceSchemes []string     `json:"resourceSchemes"`
+	Prompts         []string     `json:"prompts"`
+}
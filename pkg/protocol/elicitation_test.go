@@ -158,3 +158,22 @@ func TestElicitationRequestParamsUnmarshal(t *testing.T) {
 		t.Error("RequestedSchema should be populated")
 	}
 }
+
+func TestClientCapabilitiesSupports(t *testing.T) {
+	var caps ClientCapabilities
+	if err := json.Unmarshal([]byte(`{"roots":{"listChanged":true},"experimental":{"images":{},"audio":false}}`), &caps); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for name, want := range map[string]bool{
+		"roots":       true,
+		"sampling":    false,
+		"elicitation": false,
+		"images":      true,
+		"audio":       false,
+		"video":       false,
+	} {
+		if got := caps.Supports(name); got != want {
+			t.Errorf("Supports(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
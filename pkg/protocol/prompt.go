@@ -0,0 +1,78 @@
+package protocol
+
+// Message roles used in prompt results
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// NewUserMessage returns a prompt message carrying text from the user
+func NewUserMessage(text string) Message {
+	return Message{Role: RoleUser, Content: MessageContent{Type: "text", Text: text}}
+}
+
+// NewAssistantMessage returns a prompt message carrying text from the
+// assistant
+func NewAssistantMessage(text string) Message {
+	return Message{Role: RoleAssistant, Content: MessageContent{Type: "text", Text: text}}
+}
+
+// NewResourceMessage returns a prompt message from role that embeds resource
+func NewResourceMessage(role string, resource Resource) Message {
+	return Message{Role: role, Content: MessageContent{Type: "resource", Resource: &resource}}
+}
+
+// NewPromptResponse returns a prompts/get result holding messages in order.
+// With no messages it still encodes "messages" as an empty array.
+func NewPromptResponse(messages ...Message) *GetPromptResponse {
+	return &GetPromptResponse{Messages: append([]Message{}, messages...)}
+}
+
+// PromptBuilder assembles a prompts/get result that mixes text and embedded
+// resources:
+//
+//	resp := protocol.NewPromptBuilder().
+//		User("Review this file:").
+//		UserResource(protocol.Resource{URI: "file:///main.go", Name: "main.go"}).
+//		Assistant("Here is my review.").
+//		Build()
+type PromptBuilder struct {
+	messages []Message
+}
+
+// NewPromptBuilder creates an empty prompt builder
+func NewPromptBuilder() *PromptBuilder {
+	return &PromptBuilder{}
+}
+
+// User appends a text message from the user
+func (b *PromptBuilder) User(text string) *PromptBuilder {
+	return b.Message(NewUserMessage(text))
+}
+
+// Assistant appends a text message from the assistant
+func (b *PromptBuilder) Assistant(text string) *PromptBuilder {
+	return b.Message(NewAssistantMessage(text))
+}
+
+// UserResource appends a user message embedding resource
+func (b *PromptBuilder) UserResource(resource Resource) *PromptBuilder {
+	return b.Message(NewResourceMessage(RoleUser, resource))
+}
+
+// AssistantResource appends an assistant message embedding resource
+func (b *PromptBuilder) AssistantResource(resource Resource) *PromptBuilder {
+	return b.Message(NewResourceMessage(RoleAssistant, resource))
+}
+
+// Message appends msg as is
+func (b *PromptBuilder) Message(msg Message) *PromptBuilder {
+	b.messages = append(b.messages, msg)
+	return b
+}
+
+// Build returns the prompts/get result. The builder can keep being used;
+// later additions do not affect results already built.
+func (b *PromptBuilder) Build() *GetPromptResponse {
+	return NewPromptResponse(b.messages...)
+}
@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPromptHelpersJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *GetPromptResponse
+		want string
+	}{
+		{
+			name: "empty",
+			resp: NewPromptResponse(),
+			want: `{"messages":[]}`,
+		},
+		{
+			name: "text messages",
+			resp: NewPromptResponse(NewUserMessage("hi"), NewAssistantMessage("hello")),
+			want: `{"messages":[` +
+				`{"role":"user","content":{"type":"text","text":"hi"}},` +
+				`{"role":"assistant","content":{"type":"text","text":"hello"}}]}`,
+		},
+		{
+			name: "builder",
+			resp: NewPromptBuilder().
+				User("Review this file:").
+				UserResource(Resource{URI: "file:///main.go", Name: "main.go", MimeType: "text/x-go"}).
+				Assistant("Looks good.").
+				Build(),
+			want: `{"messages":[` +
+				`{"role":"user","content":{"type":"text","text":"Review this file:"}},` +
+				`{"role":"user","content":{"type":"resource","resource":{"uri":"file:///main.go","name":"main.go","mimeType":"text/x-go"}}},` +
+				`{"role":"assistant","content":{"type":"text","text":"Looks good."}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.resp)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got  %s\nwant %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptBuilderBuildIsIndependent(t *testing.T) {
+	b := NewPromptBuilder().User("first")
+	built := b.Build()
+	b.Assistant("second")
+
+	if len(built.Messages) != 1 {
+		t.Errorf("earlier result changed to %+v", built.Messages)
+	}
+	if got := b.Build(); len(got.Messages) != 2 || got.Messages[1].Role != RoleAssistant {
+		t.Errorf("messages = %+v, want user then assistant", got.Messages)
+	}
+}
@@ -0,0 +1,138 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Accepts reports whether the client will take a representation of the given
+// MIME type. A request without AcceptedMimeTypes accepts every type.
+func (r *ReadResourceRequest) Accepts(mimeType string) bool {
+	if len(r.AcceptedMimeTypes) == 0 {
+		return true
+	}
+	for _, accepted := range r.AcceptedMimeTypes {
+		if mimeTypeMatches(accepted, mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// PreferredMimeType picks which of the offered representations to return.
+// The client's order wins: the first accepted entry that matches an offered
+// type decides. When the client sent no preference the first offered type is
+// used, so handlers can list their natural representation first. It returns
+// "" when none of the offered types is acceptable.
+func (r *ReadResourceRequest) PreferredMimeType(offered ...string) string {
+	if len(offered) == 0 {
+		return ""
+	}
+	if len(r.AcceptedMimeTypes) == 0 {
+		return offered[0]
+	}
+	for _, accepted := range r.AcceptedMimeTypes {
+		for _, candidate := range offered {
+			if mimeTypeMatches(accepted, candidate) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// mimeTypeMatches compares a possibly wildcarded pattern ("*/*", "text/*")
+// against a concrete MIME type. Parameters such as "; charset=utf-8" are
+// ignored and comparison is case-insensitive.
+func mimeTypeMatches(pattern, mimeType string) bool {
+	pattern = baseMimeType(pattern)
+	mimeType = baseMimeType(mimeType)
+	if pattern == "*/*" || pattern == "*" || pattern == mimeType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(mimeType, prefix+"/")
+	}
+	return false
+}
+
+func baseMimeType(mimeType string) string {
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(mimeType))
+}
+
+// ErrPathTraversal is returned for a resource URI whose path climbs above its
+// root with ".." segments.
+var ErrPathTraversal = errors.New("resource URI path escapes its root")
+
+// ValidateResourceURI checks that uri is an absolute URI with a scheme and
+// that its path does not use ".." (including percent-encoded forms) to climb
+// above the root.
+func ValidateResourceURI(uri string) error {
+	_, err := parseResourceURI(uri)
+	return err
+}
+
+// NormalizeResourceURI validates uri and returns it in canonical form: the
+// scheme and host lowercased and, for file:// URIs, "." and ".." segments
+// resolved.
+func NormalizeResourceURI(uri string) (string, error) {
+	u, err := parseResourceURI(uri)
+	if err != nil {
+		return "", err
+	}
+	u.Host = strings.ToLower(u.Host)
+	if u.Scheme == "file" && u.Path != "" {
+		cleaned := path.Clean(u.Path)
+		if strings.HasSuffix(u.Path, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		u.Path = cleaned
+		u.RawPath = ""
+	}
+	return u.String(), nil
+}
+
+func parseResourceURI(uri string) (*url.URL, error) {
+	if uri == "" {
+		return nil, errors.New("resource URI is empty")
+	}
+	if strings.ContainsAny(uri, "\x00\r\n") {
+		return nil, fmt.Errorf("resource URI %q contains control characters", uri)
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("malformed resource URI: %w", err)
+	}
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("resource URI %q has no scheme", uri)
+	}
+	if escapesRoot(u.Path) {
+		return nil, fmt.Errorf("%w: %q", ErrPathTraversal, uri)
+	}
+	return u, nil
+}
+
+// escapesRoot reports whether walking p segment by segment ever climbs above
+// the starting directory.
+func escapesRoot(p string) bool {
+	depth := 0
+	for _, seg := range strings.Split(p, "/") {
+		switch seg {
+		case "", ".":
+		case "..":
+			depth--
+			if depth < 0 {
+				return true
+			}
+		default:
+			depth++
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestReadResourceRequestAcceptedMimeTypesJSON(t *testing.T) {
+	var req ReadResourceRequest
+	if err := json.Unmarshal([]byte(`{"uri":"file:///a","acceptedMimeTypes":["text/plain","*/*"]}`), &req); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(req.AcceptedMimeTypes) != 2 || req.AcceptedMimeTypes[0] != "text/plain" {
+		t.Errorf("AcceptedMimeTypes = %v", req.AcceptedMimeTypes)
+	}
+
+	out, _ := json.Marshal(ReadResourceRequest{URI: "file:///a"})
+	if string(out) != `{"uri":"file:///a"}` {
+		t.Errorf("absent preference should be omitted, got %s", out)
+	}
+}
+
+func TestReadResourceRequestAccepts(t *testing.T) {
+	tests := []struct {
+		accepted []string
+		mimeType string
+		want     bool
+	}{
+		{nil, "application/octet-stream", true},
+		{[]string{"text/plain"}, "text/plain", true},
+		{[]string{"text/plain"}, "TEXT/Plain; charset=utf-8", true},
+		{[]string{"text/*"}, "text/markdown", true},
+		{[]string{"text/*"}, "image/png", false},
+		{[]string{"*/*"}, "image/png", true},
+		{[]string{"image/png"}, "text/plain", false},
+	}
+	for _, tt := range tests {
+		req := ReadResourceRequest{AcceptedMimeTypes: tt.accepted}
+		if got := req.Accepts(tt.mimeType); got != tt.want {
+			t.Errorf("Accepts(%q) with %v = %v, want %v", tt.mimeType, tt.accepted, got, tt.want)
+		}
+	}
+}
+
+func TestReadResourceRequestPreferredMimeType(t *testing.T) {
+	offered := []string{"image/png", "text/plain"}
+	tests := []struct {
+		accepted []string
+		want     string
+	}{
+		{nil, "image/png"},
+		{[]string{"text/plain"}, "text/plain"},
+		{[]string{"text/*", "image/*"}, "text/plain"},
+		{[]string{"*/*"}, "image/png"},
+		{[]string{"application/json"}, ""},
+	}
+	for _, tt := range tests {
+		req := ReadResourceRequest{AcceptedMimeTypes: tt.accepted}
+		if got := req.PreferredMimeType(offered...); got != tt.want {
+			t.Errorf("PreferredMimeType with %v = %q, want %q", tt.accepted, got, tt.want)
+		}
+	}
+}
+
+func TestValidateResourceURI(t *testing.T) {
+	valid := []string{
+		"file:///home/user/notes.txt",
+		"file:///a/../b",
+		"https://example.com/docs/index.html",
+		"weather://forecast/today",
+	}
+	for _, uri := range valid {
+		if err := ValidateResourceURI(uri); err != nil {
+			t.Errorf("ValidateResourceURI(%q) = %v, want nil", uri, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"no-scheme/path",
+		"http://[::1",
+		"file:///etc/passwd\n",
+	}
+	for _, uri := range invalid {
+		if err := ValidateResourceURI(uri); err == nil {
+			t.Errorf("ValidateResourceURI(%q) = nil, want error", uri)
+		}
+	}
+
+	traversal := []string{
+		"file:///../etc/passwd",
+		"file:///data/../../etc/passwd",
+		"file:///data/%2e%2e/%2E%2E/etc/passwd",
+		"repo://project/../../secrets",
+	}
+	for _, uri := range traversal {
+		if err := ValidateResourceURI(uri); !errors.Is(err, ErrPathTraversal) {
+			t.Errorf("ValidateResourceURI(%q) = %v, want ErrPathTraversal", uri, err)
+		}
+	}
+}
+
+func TestNormalizeResourceURI(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"FILE:///a/./b/../c.txt", "file:///a/c.txt"},
+		{"file:///a//b/", "file:///a/b/"},
+		{"HTTPS://Example.COM/Path", "https://example.com/Path"},
+		{"weather://forecast/./today", "weather://forecast/./today"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeResourceURI(tt.in)
+		if err != nil {
+			t.Errorf("NormalizeResourceURI(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeResourceURI(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := NormalizeResourceURI("file:///../../etc/shadow"); !errors.Is(err, ErrPathTraversal) {
+		t.Errorf("traversal should be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ValidateAgainstSchema checks value against a JSON Schema. Only the subset
+// used by MCP tool schemas is enforced: type, properties, required, items and
+// enum. Unknown keywords are ignored, so a richer schema still validates the
+// parts this function understands. An empty schema accepts everything.
+func ValidateAgainstSchema(schema json.RawMessage, value interface{}) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	// Normalise value to the shapes encoding/json produces so typed Go
+	// values (structs, int, []string) validate the same as decoded JSON.
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal value: %w", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return fmt.Errorf("unmarshal value: %w", err)
+	}
+	return s.validate("$", decoded)
+}
+
+// ApplySchemaDefaults returns args with the "default" of every property the
+// schema declares filled in where args omits it. Nested object properties
+// get their defaults too, both inside objects the caller passed and inside
+// defaults that are themselves objects. args is not modified; a schema
+// without defaults returns it unchanged.
+func ApplySchemaDefaults(schema json.RawMessage, args map[string]interface{}) (map[string]interface{}, error) {
+	if len(schema) == 0 {
+		return args, nil
+	}
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	return s.applyDefaults(args)
+}
+
+// applyDefaults fills in property defaults on a copy of obj
+func (s *jsonSchema) applyDefaults(obj map[string]interface{}) (map[string]interface{}, error) {
+	if len(s.Properties) == 0 {
+		return obj, nil
+	}
+	out := make(map[string]interface{}, len(obj)+len(s.Properties))
+	for k, v := range obj {
+		out[k] = v
+	}
+	for name, prop := range s.Properties {
+		if prop == nil {
+			continue
+		}
+		value, present := out[name]
+		if !present && len(prop.Default) > 0 {
+			if err := json.Unmarshal(prop.Default, &value); err != nil {
+				return nil, fmt.Errorf("invalid default for %q: %w", name, err)
+			}
+			present = true
+		}
+		if nested, ok := value.(map[string]interface{}); ok && present {
+			filled, err := prop.applyDefaults(nested)
+			if err != nil {
+				return nil, err
+			}
+			value = filled
+		}
+		if present {
+			out[name] = value
+		}
+	}
+	return out, nil
+}
+
+// ValidateStructuredContent checks content against the tool's OutputSchema.
+// Tools without an OutputSchema accept any content.
+func (t *Tool) ValidateStructuredContent(content map[string]interface{}) error {
+	return ValidateAgainstSchema(t.OutputSchema, content)
+}
+
+// jsonSchema is the subset of JSON Schema understood by ValidateAgainstSchema.
+type jsonSchema struct {
+	Type       interface{}            `json:"type"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Required   []string               `json:"required"`
+	Items      *jsonSchema            `json:"items"`
+	Enum       []interface{}          `json:"enum"`
+	Default    json.RawMessage        `json:"default"`
+}
+
+func (s *jsonSchema) validate(path string, value interface{}) error {
+	if s == nil {
+		return nil
+	}
+	if types := s.types(); len(types) > 0 {
+		matched := false
+		for _, typ := range types {
+			if matchesType(typ, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected %v, got %s", path, s.Type, jsonTypeOf(value))
+		}
+	}
+	if len(s.Enum) > 0 {
+		matched := false
+		// Both sides are decoded JSON, so DeepEqual compares JSON type
+		// and value: "1" does not match 1, nor "true" true.
+		for _, allowed := range s.Enum {
+			if reflect.DeepEqual(allowed, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: value %v not in enum %v", path, value, s.Enum)
+		}
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		// Iterate in sorted order so the first reported error is stable.
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := v[name]; ok {
+				if err := s.Properties[name].validate(path+"."+name, prop); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// types returns the schema's "type" keyword as a list; it may be a single
+// string or an array of strings.
+func (s *jsonSchema) types() []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if str, ok := item.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func matchesType(typ string, value interface{}) bool {
+	switch typ {
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == float64(int64(f))
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeOf(value) == typ
+	}
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,139 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var weatherOutputSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"temperature": {"type": "number"},
+		"conditions": {"type": "string", "enum": ["sunny", "cloudy", "rain"]},
+		"hourly": {"type": "array", "items": {"type": "integer"}}
+	},
+	"required": ["temperature", "conditions"]
+}`)
+
+func TestToolValidateStructuredContent(t *testing.T) {
+	tool := Tool{
+		Name:         "weather",
+		InputSchema:  json.RawMessage(`{"type":"object"}`),
+		OutputSchema: weatherOutputSchema,
+	}
+
+	tests := []struct {
+		name    string
+		content map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: map[string]interface{}{"temperature": 21.5, "conditions": "sunny", "hourly": []int{20, 21}},
+		},
+		{
+			name:    "missing required",
+			content: map[string]interface{}{"temperature": 21.5},
+			wantErr: `missing required property "conditions"`,
+		},
+		{
+			name:    "wrong type",
+			content: map[string]interface{}{"temperature": "warm", "conditions": "sunny"},
+			wantErr: "$.temperature: expected number, got string",
+		},
+		{
+			name:    "not in enum",
+			content: map[string]interface{}{"temperature": 1, "conditions": "snow"},
+			wantErr: "not in enum",
+		},
+		{
+			name:    "bad array item",
+			content: map[string]interface{}{"temperature": 1, "conditions": "rain", "hourly": []float64{1, 2.5}},
+			wantErr: "$.hourly[1]: expected integer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tool.ValidateStructuredContent(tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToolValidateStructuredContent_NoSchema(t *testing.T) {
+	tool := Tool{Name: "free-form"}
+	if err := tool.ValidateStructuredContent(map[string]interface{}{"anything": true}); err != nil {
+		t.Errorf("tool without OutputSchema should accept any content: %v", err)
+	}
+}
+
+func TestValidateAgainstSchemaEnumComparesJSONTypes(t *testing.T) {
+	schema := json.RawMessage(`{"enum": [1, "two", true, null, {"a": [1]}]}`)
+
+	tests := []struct {
+		value interface{}
+		ok    bool
+	}{
+		{1, true},
+		{1.0, true},
+		{"1", false},
+		{"two", true},
+		{true, true},
+		{"true", false},
+		{nil, true},
+		{"<nil>", false},
+		{map[string]interface{}{"a": []int{1}}, true},
+		{"map[a:[1]]", false},
+	}
+	for _, tt := range tests {
+		err := ValidateAgainstSchema(schema, tt.value)
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidateAgainstSchema(%#v) = %v, want ok %v", tt.value, err, tt.ok)
+		}
+	}
+}
+
+func TestApplySchemaDefaults(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string"},
+			"limit": {"type": "integer", "default": 10},
+			"sort": {"type": "string", "default": "relevance"},
+			"filter": {
+				"type": "object",
+				"default": {},
+				"properties": {"lang": {"type": "string", "default": "en"}}
+			}
+		}
+	}`)
+	args := map[string]interface{}{"query": "go", "sort": "date"}
+
+	got, err := ApplySchemaDefaults(schema, args)
+	if err != nil {
+		t.Fatalf("ApplySchemaDefaults: %v", err)
+	}
+	want := map[string]interface{}{
+		"query":  "go",
+		"limit":  float64(10),
+		"sort":   "date",
+		"filter": map[string]interface{}{"lang": "en"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %v, want %v", got, want)
+	}
+	if len(args) != 2 {
+		t.Errorf("input args were modified: %v", args)
+	}
+}
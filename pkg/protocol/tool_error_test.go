@@ -0,0 +1,70 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewRecoverableToolError(t *testing.T) {
+	resp := NewRecoverableToolError("rate_limited", "upstream busy, try again shortly")
+	if !resp.IsError {
+		t.Error("IsError should be set")
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var parsed struct {
+		Content []ToolContent `json:"content"`
+		IsError bool          `json:"isError"`
+		Error   map[string]interface{}
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if !parsed.IsError {
+		t.Errorf("isError missing from %s", data)
+	}
+	if parsed.Error["code"] != "rate_limited" || parsed.Error["recoverable"] != true {
+		t.Errorf("error = %v, want code rate_limited and recoverable true", parsed.Error)
+	}
+	if len(parsed.Content) != 1 || parsed.Content[0].Text != "upstream busy, try again shortly" {
+		t.Errorf("content = %+v, want the error text", parsed.Content)
+	}
+}
+
+func TestNewToolErrorIsNotRecoverable(t *testing.T) {
+	data, err := json.Marshal(NewToolError("not_found", "no such file"))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	toolErr, ok := parsed["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("error missing from %s", data)
+	}
+	// recoverable is always present so false is explicit rather than unknown
+	if recoverable, present := toolErr["recoverable"]; !present || recoverable != false {
+		t.Errorf("recoverable = %v (present %v), want explicit false", recoverable, present)
+	}
+}
+
+func TestCallToolResponseOmitsErrorOnSuccess(t *testing.T) {
+	data, err := json.Marshal(CallToolResponse{Content: []ToolContent{{Type: "text", Text: "ok"}}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, present := parsed["error"]; present {
+		t.Errorf("successful result should not carry error: %s", data)
+	}
+}
@@ -213,3 +213,42 @@ func TestCallToolResponse_MarshalStructuredContent(t *testing.T) {
 		t.Error("structuredContent should be omitted when nil")
 	}
 }
+
+// TestTitleMarshalling asserts the display title on tools, resources and
+// prompts is emitted when set and omitted otherwise.
+func TestTitleMarshalling(t *testing.T) {
+	cases := []struct {
+		name     string
+		titled   interface{}
+		untitled interface{}
+	}{
+		{"tool", Tool{Name: "get_weather", Title: "Weather Lookup"}, Tool{Name: "get_weather"}},
+		{"resource", Resource{URI: "file:///a.txt", Name: "a.txt", Title: "Notes"}, Resource{URI: "file:///a.txt", Name: "a.txt"}},
+		{"prompt", Prompt{Name: "code_review", Title: "Code Review"}, Prompt{Name: "code_review"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			titled := marshalToMap(t, tc.titled)
+			if titled["title"] == nil || titled["title"] == "" {
+				t.Errorf("title missing from %v", titled)
+			}
+			if _, present := marshalToMap(t, tc.untitled)["title"]; present {
+				t.Error("title should be omitted when unset")
+			}
+		})
+	}
+}
+
+func marshalToMap(t *testing.T, v interface{}) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return parsed
+}
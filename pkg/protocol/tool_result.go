@@ -0,0 +1,84 @@
+package protocol
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// ToolResultBuilder assembles a tools/call result block by block:
+//
+//	resp := protocol.NewToolResult().
+//		AddText("Rendered chart:").
+//		AddImage(png, "image/png").
+//		Build()
+type ToolResultBuilder struct {
+	content []ToolContent
+	isError bool
+}
+
+// NewToolResult creates an empty tool result builder
+func NewToolResult() *ToolResultBuilder {
+	return &ToolResultBuilder{}
+}
+
+// AddText appends a text block
+func (b *ToolResultBuilder) AddText(text string) *ToolResultBuilder {
+	b.content = append(b.content, ToolContent{Type: "text", Text: text})
+	return b
+}
+
+// AddImage appends an image block holding data, which is base64-encoded
+func (b *ToolResultBuilder) AddImage(data []byte, mimeType string) *ToolResultBuilder {
+	b.content = append(b.content, ToolContent{
+		Type:     "image",
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: mimeType,
+	})
+	return b
+}
+
+// AddResource appends a block embedding resource
+func (b *ToolResultBuilder) AddResource(resource ResourceContent) *ToolResultBuilder {
+	b.content = append(b.content, ToolContent{Type: "resource", Resource: &resource})
+	return b
+}
+
+// Error sets whether the result reports a failed call (isError)
+func (b *ToolResultBuilder) Error(isError bool) *ToolResultBuilder {
+	b.isError = isError
+	return b
+}
+
+// Build returns the result. With no blocks added its content is still
+// encoded as an empty array. Later additions to the builder do not affect
+// results already built.
+func (b *ToolResultBuilder) Build() *CallToolResponse {
+	return &CallToolResponse{
+		Content: append([]ToolContent{}, b.content...),
+		IsError: b.isError,
+	}
+}
+
+// NewJSONToolResult returns a tool result whose single text block is the JSON
+// encoding of v, so handlers never build JSON text by hand
+func NewJSONToolResult(v interface{}) (*CallToolResponse, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool result: %w", err)
+	}
+	return &CallToolResponse{
+		Content: []ToolContent{{Type: "text", Text: string(data)}},
+	}, nil
+}
+
+// NewJSONToolError is NewJSONToolResult for a failed call: the result also
+// has IsError set
+func NewJSONToolError(v interface{}) (*CallToolResponse, error) {
+	resp, err := NewJSONToolResult(v)
+	if err != nil {
+		return nil, err
+	}
+	resp.IsError = true
+	return resp, nil
+}
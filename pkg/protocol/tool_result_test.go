@@ -0,0 +1,126 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToolResultBuilderJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *CallToolResponse
+		want string
+	}{
+		{
+			name: "empty",
+			resp: NewToolResult().Build(),
+			want: `{"content":[]}`,
+		},
+		{
+			name: "text and image",
+			resp: NewToolResult().AddText("chart:").AddImage([]byte("png"), "image/png").Build(),
+			want: `{"content":[` +
+				`{"type":"text","text":"chart:"},` +
+				`{"type":"image","data":"cG5n","mimeType":"image/png"}]}`,
+		},
+		{
+			name: "text, image and resource",
+			resp: NewToolResult().
+				AddText("report").
+				AddImage([]byte{0xff}, "image/jpeg").
+				AddResource(ResourceContent{URI: "file:///report.md", MimeType: "text/markdown", Text: "# Report"}).
+				Build(),
+			want: `{"content":[` +
+				`{"type":"text","text":"report"},` +
+				`{"type":"image","data":"/w==","mimeType":"image/jpeg"},` +
+				`{"type":"resource","resource":{"uri":"file:///report.md","mimeType":"text/markdown","text":"# Report"}}]}`,
+		},
+		{
+			name: "error",
+			resp: NewToolResult().AddText("not found").Error(true).Build(),
+			want: `{"content":[{"type":"text","text":"not found"}],"isError":true}`,
+		},
+		{
+			name: "error cleared",
+			resp: NewToolResult().AddText("ok").Error(true).Error(false).Build(),
+			want: `{"content":[{"type":"text","text":"ok"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.resp)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got  %s\nwant %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolResultBuilderBuildIsIndependent(t *testing.T) {
+	b := NewToolResult().AddText("first")
+	built := b.Build()
+	b.AddText("second")
+
+	if len(built.Content) != 1 {
+		t.Errorf("earlier result changed to %+v", built.Content)
+	}
+}
+
+func TestNewJSONToolResultEscapes(t *testing.T) {
+	type report struct {
+		Title string            `json:"title"`
+		Notes []string          `json:"notes"`
+		Meta  map[string]string `json:"meta"`
+	}
+	in := report{
+		Title: `say "hi"` + "\n\ttabbed \\ backslash",
+		Notes: []string{"<script>&</script>", "unicode: ünïcødé  "},
+		Meta:  map[string]string{`key"with"quotes`: "{not: json}"},
+	}
+
+	for _, tt := range []struct {
+		name    string
+		build   func(interface{}) (*CallToolResponse, error)
+		isError bool
+	}{
+		{"result", NewJSONToolResult, false},
+		{"error", NewJSONToolError, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.build(in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.IsError != tt.isError {
+				t.Errorf("IsError = %v, want %v", resp.IsError, tt.isError)
+			}
+			if len(resp.Content) != 1 || resp.Content[0].Type != "text" {
+				t.Fatalf("content = %+v, want one text block", resp.Content)
+			}
+			text := resp.Content[0].Text
+			if !json.Valid([]byte(text)) {
+				t.Fatalf("text is not valid JSON: %s", text)
+			}
+			var out report
+			if err := json.Unmarshal([]byte(text), &out); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if out.Title != in.Title || out.Notes[0] != in.Notes[0] || out.Notes[1] != in.Notes[1] || out.Meta[`key"with"quotes`] != "{not: json}" {
+				t.Errorf("round trip = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+func TestNewJSONToolResultUnencodable(t *testing.T) {
+	if _, err := NewJSONToolResult(map[string]interface{}{"ch": make(chan int)}); err == nil {
+		t.Error("expected an error for a value JSON cannot encode")
+	}
+	if _, err := NewJSONToolError(func() {}); err == nil {
+		t.Error("expected an error for a value JSON cannot encode")
+	}
+}
@@ -1,16 +1,105 @@
 package protocol
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 )
 
 // JSON-RPC 2.0 message types
 type Request struct {
-	JSONRPC string          `json:"jsonrpc"`
-	ID      interface{}     `json:"id"`
-	Method  string          `json:"method"`
-	Params  json.RawMessage `json:"params,omitempty"`
+	JSONRPC string `json:"jsonrpc"`
+
+	// ID is nil for a notification. Decoded from JSON it is a string, or a
+	// json.Number for a numeric id so the exact literal is echoed back.
+	ID interface{} `json:"id"`
+
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+
+	// nullID records that the id member was present but JSON null, which
+	// ID alone cannot tell apart from an absent id.
+	nullID bool
+
+	// ctx is the per-request context a transport attached; see WithContext.
+	ctx context.Context
+}
+
+// Context returns the context a transport attached to the request, or
+// context.Background() if none was attached. HTTP transports use it to carry
+// the caller's identity and the lifetime of the underlying connection.
+func (r *Request) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of r with its context changed to ctx.
+func (r *Request) WithContext(ctx context.Context) *Request {
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
+}
+
+// IsNotification reports whether the request is a notification, i.e. it
+// arrived without an id member. A request whose id is explicitly null is not
+// a notification; see HasNullID.
+func (r *Request) IsNotification() bool {
+	return r.ID == nil && !r.nullID
+}
+
+// HasNullID reports whether the request carried `"id": null`. JSON-RPC
+// discourages null ids and MCP forbids them, so such a request is invalid
+// rather than a notification.
+func (r *Request) HasNullID() bool {
+	return r.nullID
+}
+
+// UnmarshalJSON decodes a request while keeping track of whether the id
+// member was absent or null. Numeric ids are kept as json.Number.
+func (r *Request) UnmarshalJSON(data []byte) error {
+	type alias Request
+	aux := struct {
+		*alias
+		ID json.RawMessage `json:"id"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.ID = nil
+	r.nullID = false
+	switch {
+	case len(aux.ID) == 0:
+		// No id member: a notification.
+	case string(aux.ID) == "null":
+		r.nullID = true
+	default:
+		// Numeric ids decode as json.Number rather than float64 so the
+		// response echoes the client's exact literal: large integers keep
+		// their precision and are never rewritten in exponent form.
+		dec := json.NewDecoder(bytes.NewReader(aux.ID))
+		dec.UseNumber()
+		if err := dec.Decode(&r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalJSON omits the id member for notifications and writes an explicit
+// null only when the request was decoded with one.
+func (r Request) MarshalJSON() ([]byte, error) {
+	type alias Request
+	if r.IsNotification() {
+		return json.Marshal(struct {
+			alias
+			ID interface{} `json:"id,omitempty"`
+		}{alias: alias(r)})
+	}
+	return json.Marshal(alias(r))
 }
 
 type Response struct {
@@ -18,6 +107,27 @@ type Response struct {
 	ID      interface{} `json:"id"`
 	Result  interface{} `json:"result,omitempty"`
 	Error   *Error      `json:"error,omitempty"`
+
+	// ctx is the context of the request being answered; see WithContext.
+	ctx context.Context
+}
+
+// Context returns the context attached with WithContext, or
+// context.Background() if none was attached. Multi-client transports read
+// the session the response belongs to from it.
+func (r *Response) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of r with its context changed to ctx.
+// The server attaches the context of the request being answered.
+func (r *Response) WithContext(ctx context.Context) *Response {
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
 }
 
 // Notification is a JSON-RPC 2.0 notification: method + optional params, no id.
@@ -46,12 +156,19 @@ func NewNotification(method string, params interface{}) (*Notification, error) {
 	return n, nil
 }
 
+// Error is a JSON-RPC error object. It implements the error interface so a
+// handler can return one to choose the code sent to the client; the server
+// otherwise reports handler errors as InternalError.
 type Error struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
 
+func (e *Error) Error() string {
+	return e.Message
+}
+
 // MCP Protocol types. Title, Icons, and WebsiteURL are MCP 2025-11-25
 // additions to the Implementation type; older servers omit them.
 type ServerInfo struct {
@@ -100,12 +217,69 @@ type ClientCapabilities struct {
 	// mid-tool-call. Per spec, presence alone signals support; the struct
 	// itself is empty today.
 	Elicitation *ElicitationClientCapabilities `json:"elicitation,omitempty"`
+
+	// Roots and Sampling mark support for roots/list and
+	// sampling/createMessage. The server does not issue either request
+	// itself, but handlers can check for them via Supports.
+	Roots    *RootsClientCapabilities    `json:"roots,omitempty"`
+	Sampling *SamplingClientCapabilities `json:"sampling,omitempty"`
+
+	// Experimental carries non-standard capabilities. The framework reads
+	// ExperimentalNotifications from it; handlers can check other keys via
+	// Supports.
+	Experimental map[string]interface{} `json:"experimental,omitempty"`
+}
+
+// Supports reports whether the client declared capability: one of the
+// standard names "elicitation", "roots" and "sampling", or else a key under
+// experimental whose value is anything other than false.
+func (c ClientCapabilities) Supports(capability string) bool {
+	switch capability {
+	case "elicitation":
+		return c.Elicitation != nil
+	case "roots":
+		return c.Roots != nil
+	case "sampling":
+		return c.Sampling != nil
+	}
+	v, ok := c.Experimental[capability]
+	if !ok || v == nil {
+		return false
+	}
+	if b, isBool := v.(bool); isBool {
+		return b
+	}
+	return true
+}
+
+// ExperimentalNotifications is the experimental client capability key a
+// client sets to false to say it cannot receive server notifications. The
+// spec has no standard flag for this, so absence means "supported".
+const ExperimentalNotifications = "notifications"
+
+// AcceptsNotifications reports whether the client can receive server
+// notifications. Only an explicit experimental.notifications=false opts out.
+func (c ClientCapabilities) AcceptsNotifications() bool {
+	if v, ok := c.Experimental[ExperimentalNotifications].(bool); ok {
+		return v
+	}
+	return true
 }
 
 // ElicitationClientCapabilities is the empty marker struct the client sends
 // under capabilities.elicitation when it supports elicitation/create.
 type ElicitationClientCapabilities struct{}
 
+// RootsClientCapabilities is sent under capabilities.roots by clients that
+// can answer roots/list.
+type RootsClientCapabilities struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// SamplingClientCapabilities is the empty marker struct the client sends
+// under capabilities.sampling when it supports sampling/createMessage.
+type SamplingClientCapabilities struct{}
+
 // Initialize types
 type InitializeRequest struct {
 	ProtocolVersion string             `json:"protocolVersion"`
@@ -113,10 +287,16 @@ type InitializeRequest struct {
 	Capabilities    ClientCapabilities `json:"capabilities"`
 }
 
+// InitializeResponse is the initialize result. Instructions is optional
+// free-form guidance on how to use the server; clients may surface it to the
+// model as a system hint. Meta carries server-specific data for hosts that
+// read it, such as feature flags.
 type InitializeResponse struct {
-	ProtocolVersion string       `json:"protocolVersion"`
-	ServerInfo      ServerInfo   `json:"serverInfo"`
-	Capabilities    Capabilities `json:"capabilities"`
+	ProtocolVersion string                 `json:"protocolVersion"`
+	ServerInfo      ServerInfo             `json:"serverInfo"`
+	Capabilities    Capabilities           `json:"capabilities"`
+	Instructions    string                 `json:"instructions,omitempty"`
+	Meta            map[string]interface{} `json:"_meta,omitempty"`
 }
 
 // Tool types
@@ -175,24 +355,71 @@ type CallToolRequest struct {
 	Arguments map[string]interface{} `json:"arguments"`
 }
 
+// CallToolResponse is the tools/call result. StructuredContent is the
+// machine-readable counterpart to Content; when the tool declares an
+// OutputSchema it should conform to it (see Tool.ValidateStructuredContent).
 type CallToolResponse struct {
 	Content           []ToolContent          `json:"content"`
 	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"`
 	IsError           bool                   `json:"isError,omitempty"`
+	Error             *ToolError             `json:"error,omitempty"`
 	Meta              map[string]interface{} `json:"_meta,omitempty"`
 }
 
+// ToolError describes a failed tool call in machine-readable form, so the
+// model or host can decide whether calling again may succeed. It accompanies
+// IsError and the human-readable Content.
+type ToolError struct {
+	Code        string `json:"code"`
+	Recoverable bool   `json:"recoverable"`
+}
+
+// NewToolError returns a failed CallToolResponse carrying text and an
+// unrecoverable error code
+func NewToolError(code, text string) *CallToolResponse {
+	return newToolError(code, text, false)
+}
+
+// NewRecoverableToolError returns a failed CallToolResponse carrying text and
+// an error code, hinting that retrying the call (perhaps with different
+// arguments) may succeed
+func NewRecoverableToolError(code, text string) *CallToolResponse {
+	return newToolError(code, text, true)
+}
+
+func newToolError(code, text string, recoverable bool) *CallToolResponse {
+	return &CallToolResponse{
+		Content: []ToolContent{{Type: "text", Text: text}},
+		IsError: true,
+		Error:   &ToolError{Code: code, Recoverable: recoverable},
+	}
+}
+
+// ToolContent is one block of a tool result: "text" uses Text, "image" uses
+// Data (base64) and MimeType, and "resource" embeds Resource. Any block may
+// carry Annotations.
 type ToolContent struct {
-	Type     string `json:"type"`
-	Text     string `json:"text,omitempty"`
-	Data     string `json:"data,omitempty"`
-	MimeType string `json:"mimeType,omitempty"`
+	Type        string              `json:"type"`
+	Text        string              `json:"text,omitempty"`
+	Data        string              `json:"data,omitempty"`
+	MimeType    string              `json:"mimeType,omitempty"`
+	Resource    *ResourceContent    `json:"resource,omitempty"`
+	Annotations *ContentAnnotations `json:"annotations,omitempty"`
+}
+
+// ContentAnnotations tell the host how to treat a content block. Audience
+// lists who it is meant for (RoleUser, RoleAssistant); Priority ranges from
+// 0 (optional) to 1 (required), with nil meaning no hint.
+type ContentAnnotations struct {
+	Audience []string `json:"audience,omitempty"`
+	Priority *float64 `json:"priority,omitempty"`
 }
 
 // Resource types
 type Resource struct {
 	URI         string `json:"uri"`
 	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
 	Description string `json:"description,omitempty"`
 	MimeType    string `json:"mimeType,omitempty"`
 }
@@ -203,6 +430,10 @@ type ListResourcesResponse struct {
 
 type ReadResourceRequest struct {
 	URI string `json:"uri"`
+	// AcceptedMimeTypes lists the representations the client prefers, most
+	// preferred first. Entries may use wildcards ("text/*", "*/*"). Empty
+	// means the client accepts anything; see PreferredMimeType.
+	AcceptedMimeTypes []string `json:"acceptedMimeTypes,omitempty"`
 }
 
 type ResourceContent struct {
@@ -225,6 +456,7 @@ type PromptArgument struct {
 
 type Prompt struct {
 	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
 	Description string           `json:"description,omitempty"`
 	Arguments   []PromptArgument `json:"arguments,omitempty"`
 }
@@ -294,8 +526,43 @@ const (
 	// NotificationMessage is the MCP 2025-11-25 server→client notification
 	// carrying a structured log line (level, logger, data).
 	NotificationMessage = "notifications/message"
+
+	// NotificationPromptsListChanged tells the client the prompt list has
+	// changed and should be fetched again with prompts/list. Only sent to
+	// clients the server advertised prompts.listChanged to.
+	NotificationPromptsListChanged = "notifications/prompts/list_changed"
+
+	// NotificationResourceChunk is a framework extension (not in the MCP
+	// spec) carrying one piece of a streamed resources/read. Only sent when
+	// the client opted in with `_meta.stream: true` on the read request.
+	NotificationResourceChunk = "notifications/resources/chunk"
+
+	// NotificationToolChunk is a framework extension (not in the MCP spec)
+	// carrying one partial result of a streamed tools/call. Only sent when
+	// the client opted in with `_meta.stream: true` on the call and the
+	// transport can deliver it before the final response.
+	NotificationToolChunk = "notifications/tools/chunk"
 )
 
+// ResourceChunkParams are the params carried by
+// notifications/resources/chunk. RequestID is the id of the resources/read
+// request the chunk belongs to; Index starts at 0 and increases by one per
+// chunk so the client can reassemble in order.
+type ResourceChunkParams struct {
+	RequestID interface{}     `json:"requestId"`
+	Index     int             `json:"index"`
+	Content   ResourceContent `json:"content"`
+}
+
+// ToolChunkParams are the params carried by notifications/tools/chunk.
+// RequestID is the id of the tools/call request the chunk belongs to; Index
+// starts at 0 and increases by one per chunk.
+type ToolChunkParams struct {
+	RequestID interface{}       `json:"requestId"`
+	Index     int               `json:"index"`
+	Chunk     *CallToolResponse `json:"chunk"`
+}
+
 // CancelledParams are the params carried by notifications/cancelled.
 type CancelledParams struct {
 	RequestID interface{} `json:"requestId"`
@@ -364,3 +631,32 @@ const (
 	InvalidParams  = -32602
 	InternalError  = -32603
 )
+
+// Server error codes in the JSON-RPC implementation-defined range that MCP
+// SDKs conventionally give a fixed meaning.
+const (
+	// ConnectionClosed reports that the transport closed before the request
+	// could be answered.
+	ConnectionClosed = -32000
+
+	// RequestTimeout reports a request that was abandoned before completing,
+	// because its deadline passed or it was cancelled.
+	RequestTimeout = -32001
+
+	// ResourceNotFound is returned by resources/read for a URI the server
+	// does not know. The error's data should carry the uri.
+	ResourceNotFound = -32002
+
+	// NotInitialized reports a request that arrived before the client
+	// completed the initialize handshake, when the server enforces the
+	// lifecycle.
+	NotInitialized = -32003
+
+	// RateLimited reports a request over a configured rate limit. The
+	// error's data carries a retryAfter hint in seconds.
+	RateLimited = -32029
+
+	// ServerBusy reports that every request slot was taken and the queue of
+	// waiting requests was full. The client may retry later.
+	ServerBusy = -32030
+)
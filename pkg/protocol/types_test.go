@@ -30,6 +30,14 @@ func TestRequestMarshaling(t *testing.T) {
 			},
 			wantJSON: `{"jsonrpc":"2.0","id":"abc","method":"test"}`,
 		},
+		{
+			name: "notification omits id",
+			request: Request{
+				JSONRPC: "2.0",
+				Method:  "notifications/initialized",
+			},
+			wantJSON: `{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -69,6 +77,68 @@ func TestRequestMarshaling(t *testing.T) {
 	}
 }
 
+func TestRequestIDShapes(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		wantID           interface{}
+		wantNotification bool
+		wantNullID       bool
+	}{
+		{"absent id", `{"jsonrpc":"2.0","method":"m"}`, nil, true, false},
+		{"null id", `{"jsonrpc":"2.0","id":null,"method":"m"}`, nil, false, true},
+		{"number id", `{"jsonrpc":"2.0","id":7,"method":"m"}`, json.Number("7"), false, false},
+		{"string id", `{"jsonrpc":"2.0","id":"x","method":"m"}`, "x", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req Request
+			if err := json.Unmarshal([]byte(tt.input), &req); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if req.ID != tt.wantID {
+				t.Errorf("ID = %v (%T), want %v", req.ID, req.ID, tt.wantID)
+			}
+			if got := req.IsNotification(); got != tt.wantNotification {
+				t.Errorf("IsNotification() = %v, want %v", got, tt.wantNotification)
+			}
+			if got := req.HasNullID(); got != tt.wantNullID {
+				t.Errorf("HasNullID() = %v, want %v", got, tt.wantNullID)
+			}
+
+			// Round-tripping must preserve the shape of the id member.
+			out, err := json.Marshal(req)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var again Request
+			if err := json.Unmarshal(out, &again); err != nil {
+				t.Fatalf("Unmarshal round trip: %v", err)
+			}
+			if again.IsNotification() != req.IsNotification() || again.HasNullID() != req.HasNullID() {
+				t.Errorf("round trip changed id shape: %s", out)
+			}
+		})
+	}
+}
+
+func TestRequestIDEchoesIntegerLiteral(t *testing.T) {
+	for _, id := range []string{"42", "9007199254740993", "-3"} {
+		var req Request
+		if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":`+id+`,"method":"m"}`), &req); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		out, err := json.Marshal(Response{JSONRPC: "2.0", ID: req.ID, Result: "ok"})
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		want := `{"jsonrpc":"2.0","id":` + id + `,"result":"ok"}`
+		if string(out) != want {
+			t.Errorf("response = %s, want %s", out, want)
+		}
+	}
+}
+
 func TestResponseMarshaling(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -346,3 +416,108 @@ func TestToolMarshaling(t *testing.T) {
 		})
 	}
 }
+
+func TestClientCapabilitiesAcceptsNotifications(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want bool
+	}{
+		{name: "no experimental block", json: `{}`, want: true},
+		{name: "explicit true", json: `{"experimental":{"notifications":true}}`, want: true},
+		{name: "explicit false", json: `{"experimental":{"notifications":false}}`, want: false},
+		{name: "non-bool value", json: `{"experimental":{"notifications":"no"}}`, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var caps ClientCapabilities
+			if err := json.Unmarshal([]byte(tt.json), &caps); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got := caps.AcceptsNotifications(); got != tt.want {
+				t.Errorf("AcceptsNotifications() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	codes := map[string][2]int{
+		"ParseError":       {ParseError, -32700},
+		"InvalidRequest":   {InvalidRequest, -32600},
+		"MethodNotFound":   {MethodNotFound, -32601},
+		"InvalidParams":    {InvalidParams, -32602},
+		"InternalError":    {InternalError, -32603},
+		"ConnectionClosed": {ConnectionClosed, -32000},
+		"RequestTimeout":   {RequestTimeout, -32001},
+		"ResourceNotFound": {ResourceNotFound, -32002},
+		"NotInitialized":   {NotInitialized, -32003},
+		"RateLimited":      {RateLimited, -32029},
+		"ServerBusy":       {ServerBusy, -32030},
+	}
+
+	seen := make(map[int]string)
+	for name, c := range codes {
+		if c[0] != c[1] {
+			t.Errorf("%s = %d, want %d", name, c[0], c[1])
+		}
+		if other, dup := seen[c[0]]; dup {
+			t.Errorf("%s and %s share code %d", name, other, c[0])
+		}
+		seen[c[0]] = name
+	}
+}
+
+func TestToolContentAnnotationsJSON(t *testing.T) {
+	zero := 0.0
+	tests := []struct {
+		name    string
+		content ToolContent
+		want    string
+	}{
+		{
+			name:    "nil annotations omitted",
+			content: ToolContent{Type: "text", Text: "hi"},
+			want:    `{"type":"text","text":"hi"}`,
+		},
+		{
+			name:    "empty annotations",
+			content: ToolContent{Type: "text", Text: "hi", Annotations: &ContentAnnotations{}},
+			want:    `{"type":"text","text":"hi","annotations":{}}`,
+		},
+		{
+			name: "audience and priority",
+			content: ToolContent{Type: "text", Text: "hi", Annotations: &ContentAnnotations{
+				Audience: []string{RoleUser, RoleAssistant},
+				Priority: func() *float64 { p := 0.8; return &p }(),
+			}},
+			want: `{"type":"text","text":"hi","annotations":{"audience":["user","assistant"],"priority":0.8}}`,
+		},
+		{
+			name:    "zero priority kept",
+			content: ToolContent{Type: "text", Text: "hi", Annotations: &ContentAnnotations{Priority: &zero}},
+			want:    `{"type":"text","text":"hi","annotations":{"priority":0}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.content)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got  %s\nwant %s", data, tt.want)
+			}
+
+			var back ToolContent
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if (back.Annotations == nil) != (tt.content.Annotations == nil) {
+				t.Errorf("annotations after round trip = %+v, want %+v", back.Annotations, tt.content.Annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// whoamiToolHandler answers every call with the caller's identity.
+type whoamiToolHandler struct{}
+
+func (whoamiToolHandler) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	return &protocol.ListToolsResponse{}, nil
+}
+
+func (whoamiToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	identity, ok := transport.IdentityFromContext(ctx)
+	if !ok {
+		return nil, errors.New("no identity in context")
+	}
+	return &protocol.CallToolResponse{
+		Content: []protocol.ToolContent{{Type: "text", Text: fmt.Sprint(identity)}},
+	}, nil
+}
+
+func TestHTTPIdentityReachesHandler(t *testing.T) {
+	httpTransport := transport.NewHTTPTransport(transport.HTTPOptions{
+		Authenticator: transport.BearerAuthenticator(func(token string) (interface{}, error) {
+			if token != "s3cret" {
+				return nil, errors.New("invalid token")
+			}
+			return "user-42", nil
+		}),
+	})
+	ts := httptest.NewServer(httpTransport)
+	defer ts.Close()
+
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(whoamiToolHandler{})
+	srv := New(Options{
+		Name:      "auth-test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: httpTransport,
+	})
+	go srv.Run()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"whoami","arguments":{}}}`
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var got struct {
+		Result protocol.CallToolResponse `json:"result"`
+		Error  *protocol.Error           `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Error != nil {
+		t.Fatalf("unexpected error: %v", got.Error)
+	}
+	if len(got.Result.Content) != 1 || got.Result.Content[0].Text != "user-42" {
+		t.Errorf("content = %+v, want identity user-42", got.Result.Content)
+	}
+}
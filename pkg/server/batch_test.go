@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// batchMockTransport adds batch support to mockTransport and records every
+// SendBatch call.
+type batchMockTransport struct {
+	*mockTransport
+	batchIn chan []*protocol.Request
+
+	batchMu sync.Mutex
+	batches [][]*protocol.Response
+}
+
+func newBatchMockTransport() *batchMockTransport {
+	return &batchMockTransport{
+		mockTransport: newMockTransport(),
+		batchIn:       make(chan []*protocol.Request, 10),
+	}
+}
+
+func (t *batchMockTransport) ReceiveBatch() <-chan []*protocol.Request {
+	return t.batchIn
+}
+
+func (t *batchMockTransport) SendBatch(responses []*protocol.Response) error {
+	t.batchMu.Lock()
+	defer t.batchMu.Unlock()
+	t.batches = append(t.batches, responses)
+	return nil
+}
+
+func (t *batchMockTransport) batchesSnapshot() [][]*protocol.Response {
+	t.batchMu.Lock()
+	defer t.batchMu.Unlock()
+	out := make([][]*protocol.Response, len(t.batches))
+	copy(out, t.batches)
+	return out
+}
+
+// sleepyToolHandler sleeps for arguments.delayMs before answering, so tests
+// can force handlers in a batch to finish out of order.
+type sleepyToolHandler struct{}
+
+func (sleepyToolHandler) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	return &protocol.ListToolsResponse{}, nil
+}
+
+func (sleepyToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	delay := req.GetInt("delayMs", 0)
+	time.Sleep(time.Duration(delay) * time.Millisecond)
+	return &protocol.CallToolResponse{
+		Content: []protocol.ToolContent{{Type: "text", Text: req.Name}},
+	}, nil
+}
+
+func sleepyCall(id interface{}, name string, delayMs int) *protocol.Request {
+	params, _ := json.Marshal(map[string]interface{}{
+		"name":      name,
+		"arguments": map[string]interface{}{"delayMs": delayMs},
+	})
+	return &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  protocol.MethodToolsCall,
+		Params:  params,
+	}
+}
+
+func TestHandleBatchPreservesRequestOrder(t *testing.T) {
+	transp := newBatchMockTransport()
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(sleepyToolHandler{})
+	srv := New(Options{
+		Name:      "batch-test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: transp,
+	})
+
+	// The first call is the slowest, so handlers complete in reverse order.
+	srv.handleBatch(context.Background(), transp, []*protocol.Request{
+		sleepyCall(1, "slow", 60),
+		{JSONRPC: "2.0", Method: protocol.NotificationInitialized},
+		sleepyCall(2, "medium", 30),
+		sleepyCall(3, "fast", 0),
+	}, time.Now())
+
+	if n := transp.responseCount(); n != 0 {
+		t.Errorf("batch replies must not go through Send; got %d single responses", n)
+	}
+	batches := transp.batchesSnapshot()
+	if len(batches) != 1 {
+		t.Fatalf("expected exactly one batch reply, got %d", len(batches))
+	}
+	got := batches[0]
+	if len(got) != 3 {
+		t.Fatalf("expected 3 responses (notification omitted), got %d", len(got))
+	}
+	for i, want := range []struct {
+		id   interface{}
+		text string
+	}{{1, "slow"}, {2, "medium"}, {3, "fast"}} {
+		if got[i].ID != want.id {
+			t.Errorf("response[%d].ID = %v, want %v", i, got[i].ID, want.id)
+		}
+		result, ok := got[i].Result.(*protocol.CallToolResponse)
+		if !ok {
+			t.Fatalf("response[%d].Result = %T, want *protocol.CallToolResponse", i, got[i].Result)
+		}
+		if result.Content[0].Text != want.text {
+			t.Errorf("response[%d] text = %q, want %q", i, result.Content[0].Text, want.text)
+		}
+	}
+}
+
+func TestHandleBatchAllNotificationsSendsNothing(t *testing.T) {
+	transp := newBatchMockTransport()
+	srv := New(Options{
+		Name:      "batch-test-server",
+		Version:   "1.0.0",
+		Registry:  handler.NewHandlerRegistry(),
+		Transport: transp,
+	})
+
+	srv.handleBatch(context.Background(), transp, []*protocol.Request{
+		{JSONRPC: "2.0", Method: protocol.NotificationInitialized},
+		{JSONRPC: "2.0", Method: "notifications/cancelled", Params: json.RawMessage(`{"requestId":99}`)},
+	}, time.Now())
+
+	if n := len(transp.batchesSnapshot()); n != 0 {
+		t.Errorf("all-notification batch must get no reply; got %d batches", n)
+	}
+	if n := transp.responseCount(); n != 0 {
+		t.Errorf("all-notification batch must get no reply; got %d responses", n)
+	}
+}
+
+func TestRunDispatchesBatchesFromTransport(t *testing.T) {
+	transp := newBatchMockTransport()
+	srv := New(Options{
+		Name:      "batch-test-server",
+		Version:   "1.0.0",
+		Registry:  handler.NewHandlerRegistry(),
+		Transport: transp,
+	})
+	go srv.Run()
+
+	transp.batchIn <- []*protocol.Request{
+		{JSONRPC: "2.0", ID: 1, Method: protocol.MethodPing},
+		{JSONRPC: "2.0", ID: 2, Method: protocol.MethodPing},
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && len(transp.batchesSnapshot()) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	batches := transp.batchesSnapshot()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("expected one batch of 2 responses, got %+v", batches)
+	}
+}
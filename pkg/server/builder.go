@@ -0,0 +1,115 @@
+package server
+
+import (
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// ServerBuilder assembles a HandlerRegistry and Options in one fluent chain.
+// It is a convenience over New; anything it does can be done by building
+// Options by hand.
+type ServerBuilder struct {
+	options  Options
+	tool     handler.ToolHandler
+	resource handler.ResourceHandler
+	prompt   handler.PromptHandler
+}
+
+// Builder starts a new ServerBuilder. Unset options fall back to
+// DefaultOptions when Build calls New.
+func Builder() *ServerBuilder {
+	return &ServerBuilder{}
+}
+
+// Name sets the server name
+func (b *ServerBuilder) Name(name string) *ServerBuilder {
+	b.options.Name = name
+	return b
+}
+
+// Title sets the human-readable server title
+func (b *ServerBuilder) Title(title string) *ServerBuilder {
+	b.options.Title = title
+	return b
+}
+
+// Version sets the server version
+func (b *ServerBuilder) Version(version string) *ServerBuilder {
+	b.options.Version = version
+	return b
+}
+
+// Instructions sets the usage instructions returned during initialize
+func (b *ServerBuilder) Instructions(instructions string) *ServerBuilder {
+	b.options.Instructions = instructions
+	return b
+}
+
+// Transport sets the transport
+func (b *ServerBuilder) Transport(t transport.Transport) *ServerBuilder {
+	b.options.Transport = t
+	return b
+}
+
+// Tool registers the tool handler
+func (b *ServerBuilder) Tool(h handler.ToolHandler) *ServerBuilder {
+	b.tool = h
+	return b
+}
+
+// Resource registers the resource handler
+func (b *ServerBuilder) Resource(h handler.ResourceHandler) *ServerBuilder {
+	b.resource = h
+	return b
+}
+
+// Prompt registers the prompt handler
+func (b *ServerBuilder) Prompt(h handler.PromptHandler) *ServerBuilder {
+	b.prompt = h
+	return b
+}
+
+// With applies functional options on top of what the builder has set so far
+func (b *ServerBuilder) With(opts ...Option) *ServerBuilder {
+	for _, opt := range opts {
+		opt(&b.options)
+	}
+	return b
+}
+
+// Build constructs the server via New. The handlers given to Tool,
+// Resource and Prompt are registered in the registry supplied through
+// WithRegistry, replacing its handler of the same kind, or in a new
+// registry when none was supplied.
+func (b *ServerBuilder) Build() *Server {
+	opts := b.options
+	if opts.Registry == nil {
+		opts.Registry = handler.NewHandlerRegistry()
+	}
+	if b.tool != nil {
+		opts.Registry.RegisterToolHandler(b.tool)
+	}
+	if b.resource != nil {
+		opts.Registry.RegisterResourceHandler(b.resource)
+	}
+	if b.prompt != nil {
+		opts.Registry.RegisterPromptHandler(b.prompt)
+	}
+	return New(opts)
+}
+
+// Quick builds a server named name answering tools with h over stdio, the
+// shortest path to a working MCP server:
+//
+//	server.Quick("echo", "1.0.0", tools).Run()
+//
+// opts are applied last, so any default (including the transport) can be
+// overridden.
+func Quick(name, version string, h handler.ToolHandler, opts ...Option) *Server {
+	return Builder().
+		Name(name).
+		Version(version).
+		Tool(h).
+		With(opts...).
+		Build()
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestBuilderMatchesManualWiring(t *testing.T) {
+	transp := newMockTransport()
+	tool := &mockToolHandler{}
+	resource := &mockResourceHandler{}
+	prompt := &mockPromptHandler{}
+
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(tool)
+	registry.RegisterResourceHandler(resource)
+	registry.RegisterPromptHandler(prompt)
+	manual := New(Options{
+		Name:         "built-server",
+		Version:      "2.0.0",
+		Instructions: "be nice",
+		Registry:     registry,
+		Transport:    transp,
+	})
+
+	built := Builder().
+		Name("built-server").
+		Version("2.0.0").
+		Instructions("be nice").
+		Transport(transp).
+		Tool(tool).
+		Resource(resource).
+		Prompt(prompt).
+		Build()
+
+	if built.options.Name != manual.options.Name {
+		t.Errorf("Name = %q, want %q", built.options.Name, manual.options.Name)
+	}
+	if built.options.Version != manual.options.Version {
+		t.Errorf("Version = %q, want %q", built.options.Version, manual.options.Version)
+	}
+	if built.options.Instructions != manual.options.Instructions {
+		t.Errorf("Instructions = %q, want %q", built.options.Instructions, manual.options.Instructions)
+	}
+	if built.transport != manual.transport {
+		t.Error("builder did not use the provided transport")
+	}
+	if built.registry.GetToolHandler() != tool {
+		t.Error("builder did not register the tool handler")
+	}
+	if built.registry.GetResourceHandler() != resource {
+		t.Error("builder did not register the resource handler")
+	}
+	if built.registry.GetPromptHandler() != prompt {
+		t.Error("builder did not register the prompt handler")
+	}
+}
+
+func TestBuilderDefaults(t *testing.T) {
+	srv := Builder().Transport(newMockTransport()).Build()
+
+	defaults := DefaultOptions()
+	if srv.options.Name != defaults.Name {
+		t.Errorf("Name = %q, want default %q", srv.options.Name, defaults.Name)
+	}
+	if srv.options.Version != defaults.Version {
+		t.Errorf("Version = %q, want default %q", srv.options.Version, defaults.Version)
+	}
+	if srv.registry == nil || srv.registry.HasToolHandler() {
+		t.Error("expected an empty, non-nil registry")
+	}
+}
+
+func TestBuilderMergesIntoSuppliedRegistry(t *testing.T) {
+	resource := &mockResourceHandler{}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterResourceHandler(resource)
+
+	tool := &mockToolHandler{}
+	srv := Builder().
+		Transport(newMockTransport()).
+		With(WithRegistry(registry)).
+		Tool(tool).
+		Build()
+
+	if srv.registry != registry {
+		t.Fatal("builder replaced the supplied registry")
+	}
+	if srv.registry.GetToolHandler() != tool {
+		t.Error("builder did not add its tool handler to the supplied registry")
+	}
+	if srv.registry.GetResourceHandler() != resource {
+		t.Error("builder dropped the supplied registry's resource handler")
+	}
+}
+
+func TestQuickServerAnswersInitializeAndToolsList(t *testing.T) {
+	transp := newMockTransport()
+	tools := handler.NewToolRouter()
+	tools.Register(protocol.Tool{Name: "echo"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		return &protocol.CallToolResponse{}, nil
+	})
+	srv := Quick("quick-server", "0.1.0", tools, WithTransport(transp))
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodInitialize,
+		Params: json.RawMessage(`{"protocolVersion":"2025-11-25","capabilities":{}}`),
+	}, time.Now())
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 2, Method: protocol.MethodToolsList,
+	}, time.Now())
+
+	if transp.responseCount() != 2 {
+		t.Fatalf("expected 2 responses, got %d", transp.responseCount())
+	}
+	init, ok := transp.responseAt(0).Result.(*protocol.InitializeResponse)
+	if !ok {
+		t.Fatalf("initialize result = %T, want *protocol.InitializeResponse", transp.responseAt(0).Result)
+	}
+	if init.ServerInfo.Name != "quick-server" || init.ServerInfo.Version != "0.1.0" {
+		t.Errorf("serverInfo = %+v, want quick-server 0.1.0", init.ServerInfo)
+	}
+	if init.Capabilities.Tools == nil {
+		t.Error("tools capability not advertised")
+	}
+	list, ok := transp.responseAt(1).Result.(*protocol.ListToolsResponse)
+	if !ok || len(list.Tools) != 1 || list.Tools[0].Name != "echo" {
+		t.Errorf("tools/list result = %+v, want the echo tool", transp.responseAt(1).Result)
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// toolHandler returns the handler answering tools/list and tools/call: the
+// registered ToolHandler, combined with the built-in operation tools when an
+// async executor is configured. Returns nil when there are no tools at all.
+func (s *Server) toolHandler() handler.ToolHandler {
+	registered := s.registry.GetToolHandler()
+	if s.builtinTools == nil {
+		return registered
+	}
+	return &builtinToolHandler{builtins: s.builtinTools, next: registered}
+}
+
+// builtinToolHandler serves the built-in tools and passes every other call to
+// next, which may be nil
+type builtinToolHandler struct {
+	builtins *handler.ToolRouter
+	next     handler.ToolHandler
+}
+
+// ListTools returns next's tools followed by the built-in ones. A tool of
+// next's that shares a built-in name is left out, since calls to that name
+// reach the built-in.
+func (h *builtinToolHandler) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	builtins, err := h.builtins.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if h.next == nil {
+		return builtins, nil
+	}
+
+	list, err := h.next.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &protocol.ListToolsResponse{Tools: []protocol.Tool{}}
+	if list != nil {
+		for _, tool := range list.Tools {
+			if !h.isBuiltin(tool.Name) {
+				resp.Tools = append(resp.Tools, tool)
+			}
+		}
+	}
+	resp.Tools = append(resp.Tools, builtins.Tools...)
+	return resp, nil
+}
+
+// CallTool runs a built-in tool, or hands the call to next
+func (h *builtinToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	if h.isBuiltin(req.Name) || h.next == nil {
+		return h.builtins.CallTool(ctx, req)
+	}
+	return h.next.CallTool(ctx, req)
+}
+
+// isBuiltin reports whether name is one of the built-in tools
+func (h *builtinToolHandler) isBuiltin(name string) bool {
+	list, _ := h.builtins.ListTools(context.Background())
+	for _, tool := range list.Tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/async"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestAsyncExecutorTools(t *testing.T) {
+	executor := async.NewExecutor(async.DefaultConfig())
+	defer executor.Stop()
+
+	started, err := executor.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, async.ExecuteOptions{Type: "render", Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(&mockToolHandler{}).
+		With(WithAsyncExecutor(executor)).
+		Build()
+
+	call := func(name string, args map[string]interface{}) *protocol.CallToolResponse {
+		t.Helper()
+		n := mockTransport.responseCount()
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0", ID: n + 1, Method: protocol.MethodToolsCall,
+			Params: mustMarshal(t, map[string]interface{}{"name": name, "arguments": args}),
+		}, time.Now())
+		resp := mockTransport.responseAt(n)
+		if resp.Error != nil {
+			t.Fatalf("%s: unexpected error %+v", name, resp.Error)
+		}
+		result, ok := resp.Result.(*protocol.CallToolResponse)
+		if !ok {
+			t.Fatalf("%s: unexpected result %T", name, resp.Result)
+		}
+		return result
+	}
+	listOperations := func() []async.OperationInfo {
+		t.Helper()
+		var body struct {
+			Operations []async.OperationInfo `json:"operations"`
+		}
+		if err := json.Unmarshal([]byte(call(async.ListOperationsTool, nil).Content[0].Text), &body); err != nil {
+			t.Fatalf("decode list_operations: %v", err)
+		}
+		return body.Operations
+	}
+
+	ops := listOperations()
+	if len(ops) != 1 || ops[0].ID != started.OperationID || ops[0].Status != async.StatusRunning {
+		t.Fatalf("list_operations = %+v, want the running operation", ops)
+	}
+
+	if result := call(async.CancelOperationTool, map[string]interface{}{"operation_id": started.OperationID}); result.IsError {
+		t.Fatalf("cancel_operation failed: %s", result.Content[0].Text)
+	}
+	if ops := listOperations(); len(ops) != 1 || ops[0].Status != async.StatusCancelled {
+		t.Errorf("list_operations after cancel = %+v, want the operation cancelled", ops)
+	}
+	if !executor.ListOperationDetails()[0].Status.IsTerminal() {
+		t.Errorf("executor still reports the operation running")
+	}
+}
+
+func TestAsyncExecutorToolsListed(t *testing.T) {
+	executor := async.NewExecutor(async.DefaultConfig())
+	defer executor.Stop()
+
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		With(WithAsyncExecutor(executor)).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsList,
+	}, time.Now())
+	list, ok := mockTransport.responseAt(0).Result.(*protocol.ListToolsResponse)
+	if !ok {
+		t.Fatalf("unexpected result %+v", mockTransport.responseAt(0))
+	}
+	names := map[string]bool{}
+	for _, tool := range list.Tools {
+		names[tool.Name] = true
+	}
+	for _, want := range []string{async.ContinueOperationTool, async.CancelOperationTool, async.ListOperationsTool} {
+		if !names[want] {
+			t.Errorf("tools/list is missing %s: %+v", want, list.Tools)
+		}
+	}
+
+	if caps := srv.serverCapabilities(protocol.ClientCapabilities{}); caps.Tools == nil {
+		t.Errorf("tools capability not advertised with only built-in tools")
+	}
+}
@@ -241,9 +241,9 @@ func TestRequestTracker_Concurrent(t *testing.T) {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
-			_, _ = tracker.register(context.Background(), id)
-			tracker.cancel(id)
-			tracker.unregister(id)
+			_, _ = tracker.register(context.Background(), "", id)
+			tracker.cancel("", id)
+			tracker.unregister("", id)
 		}(i)
 	}
 
@@ -251,10 +251,66 @@ func TestRequestTracker_Concurrent(t *testing.T) {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
-			_, _ = tracker.register(context.Background(), id)
-			tracker.unregister(id)
+			_, _ = tracker.register(context.Background(), "", id)
+			tracker.unregister("", id)
 		}(i)
 	}
 
 	wg.Wait()
 }
+
+// TestRunReturnCancelsInFlightHandlers verifies that a client disconnect
+// (EOF, delivered as a nil request) cancels the handlers still running.
+func TestRunReturnCancelsInFlightHandlers(t *testing.T) {
+	tool := &cancellingToolHandler{
+		ctxSeen:     make(chan context.Context, 1),
+		returnDelay: 3 * time.Second,
+	}
+	transp := buildServerWithHandler(t, tool)
+
+	sendCall(t, transp, 201)
+
+	var handlerCtx context.Context
+	select {
+	case handlerCtx = <-tool.ctxSeen:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("CallTool was not invoked within 500ms")
+	}
+
+	transp.requests <- nil // EOF
+
+	select {
+	case <-handlerCtx.Done():
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("handler ctx was not cancelled after the client disconnected")
+	}
+}
+
+// TestRunReturnCancelsTransportContextHandlers covers handlers running under
+// a context attached by the transport, as HTTP requests are.
+func TestRunReturnCancelsTransportContextHandlers(t *testing.T) {
+	tool := &cancellingToolHandler{
+		ctxSeen:     make(chan context.Context, 1),
+		returnDelay: 3 * time.Second,
+	}
+	transp := buildServerWithHandler(t, tool)
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "test-tool", "arguments": map[string]interface{}{}})
+	req := &protocol.Request{JSONRPC: "2.0", ID: 202, Method: protocol.MethodToolsCall, Params: params}
+	transp.requests <- req.WithContext(context.WithValue(context.Background(), struct{}{}, "conn"))
+
+	var handlerCtx context.Context
+	select {
+	case handlerCtx = <-tool.ctxSeen:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("CallTool was not invoked within 500ms")
+	}
+
+	transp.requests <- nil
+
+	select {
+	case <-handlerCtx.Done():
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("handler ctx was not cancelled after Run returned")
+	}
+}
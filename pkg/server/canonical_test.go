@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestCanonicalJSONResults(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(&mockToolHandler{result: &protocol.CallToolResponse{
+			Content:           []protocol.ToolContent{{Type: "text", Text: "ok"}},
+			StructuredContent: map[string]interface{}{"b": 2, "a": 1, "c": map[string]interface{}{"z": 0, "y": 0}},
+		}}).
+		With(WithCanonicalJSON()).
+		Build()
+
+	for id := 1; id <= 2; id++ {
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0", ID: id, Method: protocol.MethodToolsCall,
+			Params: json.RawMessage(`{"name":"test_tool","arguments":{}}`),
+		}, time.Now())
+	}
+
+	want := `{"content":[{"text":"ok","type":"text"}],"structuredContent":{"a":1,"b":2,"c":{"y":0,"z":0}}}`
+	for i := 0; i < 2; i++ {
+		result, ok := mockTransport.responseAt(i).Result.(json.RawMessage)
+		if !ok {
+			t.Fatalf("result = %T, want canonical json.RawMessage", mockTransport.responseAt(i).Result)
+		}
+		if string(result) != want {
+			t.Errorf("response %d:\n got %s\nwant %s", i, result, want)
+		}
+	}
+}
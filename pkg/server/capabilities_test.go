@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func newCapabilitiesTestServer() *Server {
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(&mockToolHandler{})
+	registry.RegisterResourceHandler(&mockResourceHandler{})
+	return New(Options{
+		Name:      "caps-test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: newMockTransport(),
+		Capabilities: &protocol.Capabilities{
+			Tools:     &protocol.ToolsInfo{ListChanged: true},
+			Resources: &protocol.ResourcesInfo{Subscribe: true, ListChanged: true},
+			// No prompt handler is registered, so this must not be advertised.
+			Prompts: &protocol.PromptsInfo{ListChanged: true},
+		},
+	})
+}
+
+func TestCapabilitiesAdvertisedToNotificationCapableClient(t *testing.T) {
+	srv := newCapabilitiesTestServer()
+
+	resp, err := srv.handleInitialize(context.Background(), json.RawMessage(`{"capabilities":{}}`))
+	if err != nil {
+		t.Fatalf("handleInitialize: %v", err)
+	}
+	caps := resp.Capabilities
+	if caps.Tools == nil || !caps.Tools.ListChanged {
+		t.Errorf("tools = %+v, want listChanged", caps.Tools)
+	}
+	if caps.Resources == nil || !caps.Resources.Subscribe || !caps.Resources.ListChanged {
+		t.Errorf("resources = %+v, want subscribe and listChanged", caps.Resources)
+	}
+	if caps.Prompts != nil {
+		t.Errorf("prompts = %+v, want nil without a prompt handler", caps.Prompts)
+	}
+}
+
+func TestCapabilitiesReducedForClientWithoutNotifications(t *testing.T) {
+	srv := newCapabilitiesTestServer()
+
+	resp, err := srv.handleInitialize(context.Background(),
+		json.RawMessage(`{"capabilities":{"experimental":{"notifications":false}}}`))
+	if err != nil {
+		t.Fatalf("handleInitialize: %v", err)
+	}
+	caps := resp.Capabilities
+	if caps.Tools == nil || caps.Tools.ListChanged {
+		t.Errorf("tools = %+v, want present without listChanged", caps.Tools)
+	}
+	if caps.Resources == nil || caps.Resources.Subscribe || caps.Resources.ListChanged {
+		t.Errorf("resources = %+v, want present without subscribe/listChanged", caps.Resources)
+	}
+	if caps.Logging == nil {
+		t.Error("logging should always be advertised")
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+type clientCapsKey struct{}
+
+// ClientSupports reports whether the client declared capability during
+// initialize, as decided by protocol.ClientCapabilities.Supports. Handlers
+// use it to adapt their output, e.g. falling back to text when the client
+// lacks an experimental image capability. It returns false outside a
+// request handler and before the client has initialized.
+func ClientSupports(ctx context.Context, capability string) bool {
+	caps, ok := ctx.Value(clientCapsKey{}).(*protocol.ClientCapabilities)
+	return ok && caps != nil && caps.Supports(capability)
+}
+
+// clientCapabilities returns the capabilities the client that sent the
+// request handled in ctx declared at initialize, or nil before it has.
+// handleInitialize replaces the pointer rather than mutating it, so callers
+// may keep it.
+func (s *Server) clientCapabilities(ctx context.Context) *protocol.ClientCapabilities {
+	s.clientCapsMu.RLock()
+	defer s.clientCapsMu.RUnlock()
+	if sessionID, ok := s.handshakeSession(ctx); ok {
+		return s.sessionCaps[sessionID]
+	}
+	return s.clientCaps
+}
+
+// setClientCapabilities records the capabilities the client that sent the
+// initialize handled in ctx declared. Sessions the transport no longer has
+// are forgotten along the way.
+func (s *Server) setClientCapabilities(ctx context.Context, caps *protocol.ClientCapabilities) {
+	sessionID, ok := s.handshakeSession(ctx)
+	s.clientCapsMu.Lock()
+	defer s.clientCapsMu.Unlock()
+	if !ok {
+		s.clientCaps = caps
+		return
+	}
+	if s.sessionCaps == nil {
+		s.sessionCaps = make(map[string]*protocol.ClientCapabilities)
+	}
+	open := s.openSessions()
+	for id := range s.sessionCaps {
+		if !open[id] {
+			delete(s.sessionCaps, id)
+		}
+	}
+	s.sessionCaps[sessionID] = caps
+}
+
+// initializedClients returns the capabilities of every client that has
+// initialized, keyed by session; a single-peer client is under "".
+func (s *Server) initializedClients() map[string]*protocol.ClientCapabilities {
+	s.clientCapsMu.RLock()
+	defer s.clientCapsMu.RUnlock()
+	clients := make(map[string]*protocol.ClientCapabilities, len(s.sessionCaps)+1)
+	for id, caps := range s.sessionCaps {
+		clients[id] = caps
+	}
+	if s.clientCaps != nil {
+		clients[""] = s.clientCaps
+	}
+	return clients
+}
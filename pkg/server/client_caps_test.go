@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// chartTool returns an image when the client supports the experimental
+// "images" capability and a text description otherwise.
+func chartTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	if ClientSupports(ctx, "images") {
+		return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "image", Data: "iVBORw0KGgo=", MimeType: "image/png"}}}, nil
+	}
+	return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: "sales rose 12%"}}}, nil
+}
+
+func TestClientSupportsDrivesHandlerOutput(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		capabilities string
+		wantType     string
+	}{
+		{"images supported", `{"experimental":{"images":{}}}`, "image"},
+		{"images disabled", `{"experimental":{"images":false},"roots":{}}`, "text"},
+		{"nothing declared", `{}`, "text"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			router := handler.NewToolRouter()
+			router.Register(protocol.Tool{Name: "chart"}, chartTool)
+			mockTransport := newMockTransport()
+			srv := Builder().Transport(mockTransport).Tool(router).Build()
+
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0", ID: 1, Method: protocol.MethodInitialize,
+				Params: json.RawMessage(`{"protocolVersion":"2025-06-18","clientInfo":{"name":"c","version":"1"},"capabilities":` + tt.capabilities + `}`),
+			}, time.Now())
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0", ID: 2, Method: protocol.MethodToolsCall,
+				Params: json.RawMessage(`{"name":"chart","arguments":{}}`),
+			}, time.Now())
+
+			resp := mockTransport.responseAt(1)
+			result, ok := resp.Result.(*protocol.CallToolResponse)
+			if !ok {
+				t.Fatalf("result = %T (%+v), want *protocol.CallToolResponse", resp.Result, resp.Error)
+			}
+			if result.Content[0].Type != tt.wantType {
+				t.Errorf("content type = %q, want %q", result.Content[0].Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestClientSupportsOutsideRequest(t *testing.T) {
+	if ClientSupports(context.Background(), "elicitation") {
+		t.Error("ClientSupports without a request context should be false")
+	}
+}
+
+func TestClientCapabilitiesArePerSession(t *testing.T) {
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "chart"}, chartTool)
+	transp := &sessionTransport{mockTransport: newMockTransport(), sent: make(map[string][]*protocol.Notification)}
+	srv := Builder().Transport(transp).Tool(router).Build()
+
+	for i, tc := range []struct{ session, capabilities string }{
+		{"a", `{"experimental":{"images":{}},"elicitation":{}}`},
+		{"b", `{}`},
+	} {
+		ctx := transport.WithSessionID(context.Background(), tc.session)
+		srv.handleRequest(ctx, (&protocol.Request{
+			JSONRPC: "2.0", ID: i, Method: protocol.MethodInitialize,
+			Params: json.RawMessage(`{"protocolVersion":"2025-06-18","clientInfo":{"name":"c","version":"1"},"capabilities":` + tc.capabilities + `}`),
+		}).WithContext(ctx), time.Now())
+	}
+
+	for _, tc := range []struct {
+		session, wantType string
+		wantElicit        bool
+	}{
+		{"a", "image", true},
+		{"b", "text", false},
+	} {
+		ctx := transport.WithSessionID(context.Background(), tc.session)
+		before := transp.responseCount()
+		srv.handleRequest(ctx, (&protocol.Request{
+			JSONRPC: "2.0", ID: 10, Method: protocol.MethodToolsCall,
+			Params: json.RawMessage(`{"name":"chart","arguments":{}}`),
+		}).WithContext(ctx), time.Now())
+
+		result, ok := transp.responseAt(before).Result.(*protocol.CallToolResponse)
+		if !ok {
+			t.Fatalf("session %s: result = %T, want *protocol.CallToolResponse", tc.session, transp.responseAt(before).Result)
+		}
+		if result.Content[0].Type != tc.wantType {
+			t.Errorf("session %s: content type = %q, want %q", tc.session, result.Content[0].Type, tc.wantType)
+		}
+		if got := srv.clientSupportsElicitation(ctx); got != tc.wantElicit {
+			t.Errorf("session %s: elicitation supported = %v, want %v", tc.session, got, tc.wantElicit)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package server
+
+import "time"
+
+// Clock is the server's source of the current time (see Options.Clock)
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by time.Now
+type SystemClock struct{}
+
+// Now returns the current system time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// stepClock is a fake Clock that advances by step on every reading
+type stepClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+func TestClockDrivesLoggedDuration(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	clock := &stepClock{now: time.Unix(1700000000, 0), step: 250 * time.Millisecond}
+	srv := Builder().Transport(newMockTransport()).With(WithClock(clock)).Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: 1, Method: protocol.MethodPing}, clock.Now())
+
+	if want := "MCP server handled ping (id 1) in 250ms"; !strings.Contains(buf.String(), want) {
+		t.Errorf("log %q does not contain %q", buf.String(), want)
+	}
+}
+
+func TestClockDrivesMaxRequestAge(t *testing.T) {
+	clock := &stepClock{now: time.Unix(1700000000, 0)}
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).With(WithClock(clock), WithMaxRequestAge(time.Minute)).Build()
+
+	// Received two minutes ago by the server's clock, no matter how long the
+	// test itself takes
+	receivedAt := clock.Now().Add(-2 * time.Minute)
+	srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: 1, Method: protocol.MethodPing}, receivedAt)
+	if resp := mockTransport.responseAt(0); resp == nil || resp.Error == nil || resp.Error.Code != protocol.RequestTimeout {
+		t.Errorf("stale request answered %+v, want RequestTimeout", resp)
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: 2, Method: protocol.MethodPing}, clock.Now())
+	if resp := mockTransport.responseAt(1); resp == nil || resp.Error != nil {
+		t.Errorf("fresh request answered %+v, want the ping result", resp)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errServerBusy is returned by acquire when the wait queue is full
+var errServerBusy = errors.New("server busy")
+
+// concurrencyLimiter is a counting semaphore with an optionally bounded
+// queue of waiters.
+type concurrencyLimiter struct {
+	slots    chan struct{}
+	maxQueue int
+
+	mu      sync.Mutex
+	waiting int
+}
+
+func newConcurrencyLimiter(max, maxQueue int) *concurrencyLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &concurrencyLimiter{
+		slots:    make(chan struct{}, max),
+		maxQueue: maxQueue,
+	}
+}
+
+// acquire takes a slot, waiting for one unless the queue is full. It
+// returns errServerBusy when the queue is full and ctx.Err() if ctx ends
+// first; otherwise the caller must call release once done.
+func (l *concurrencyLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	release = func() { <-l.slots }
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	l.mu.Lock()
+	if l.maxQueue > 0 && l.waiting >= l.maxQueue {
+		l.mu.Unlock()
+		return nil, errServerBusy
+	}
+	l.waiting++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.waiting--
+		l.mu.Unlock()
+	}()
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// depth returns the number of requests waiting for a slot
+func (l *concurrencyLimiter) depth() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waiting
+}
+
+// QueueDepth returns how many requests are waiting for a free slot under
+// MaxConcurrentRequests. It is always 0 when concurrency is unlimited.
+func (s *Server) QueueDepth() int {
+	return s.slots.depth()
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// blockingServer returns a server whose only tool blocks until release is
+// closed
+func blockingServer(release chan struct{}, opts ...Option) (*Server, *mockTransport) {
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "block"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		<-release
+		return &protocol.CallToolResponse{}, nil
+	})
+	mockTransport := newMockTransport()
+	return Builder().Transport(mockTransport).Tool(router).With(opts...).Build(), mockTransport
+}
+
+func callBlock(srv *Server, id int) {
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: id, Method: protocol.MethodToolsCall,
+		Params: json.RawMessage(`{"name":"block","arguments":{}}`),
+	}, time.Now())
+}
+
+// waitForDepth polls until srv reports want queued requests
+func waitForDepth(t *testing.T, srv *Server, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.QueueDepth() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("QueueDepth = %d, want %d", srv.QueueDepth(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestQueueDepth(t *testing.T) {
+	release := make(chan struct{})
+	srv, mockTransport := blockingServer(release, WithMaxConcurrentRequests(1, 0))
+
+	if got := srv.QueueDepth(); got != 0 {
+		t.Fatalf("idle QueueDepth = %d, want 0", got)
+	}
+	for id := 1; id <= 3; id++ {
+		go callBlock(srv, id)
+	}
+	waitForDepth(t, srv, 2)
+
+	close(release)
+	waitForDepth(t, srv, 0)
+	deadline := time.Now().Add(2 * time.Second)
+	for mockTransport.responseCount() != 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := mockTransport.responseCount(); got != 3 {
+		t.Errorf("got %d responses, want all 3 queued requests answered", got)
+	}
+}
+
+func TestQueueDepthRejectsAtCap(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	srv, mockTransport := blockingServer(release, WithMaxConcurrentRequests(1, 1))
+
+	go callBlock(srv, 1)
+	go callBlock(srv, 2)
+	waitForDepth(t, srv, 1)
+
+	// The slot and the queue are both full, so this is rejected at once
+	callBlock(srv, 3)
+	resp := mockTransport.responseAt(0)
+	if resp == nil || resp.ID != 3 {
+		t.Fatalf("first response = %+v, want the rejected request 3", resp)
+	}
+	if resp.Error == nil || resp.Error.Code != protocol.ServerBusy {
+		t.Errorf("error = %+v, want ServerBusy", resp.Error)
+	}
+
+	// ping bypasses the limit
+	srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: 4, Method: protocol.MethodPing}, time.Now())
+	if resp := mockTransport.responseAt(1); resp == nil || resp.Error != nil {
+		t.Errorf("ping under load = %+v, want a result", resp)
+	}
+}
+
+func TestQueuedRequestExpiresWhileWaiting(t *testing.T) {
+	release := make(chan struct{})
+	srv, mockTransport := blockingServer(release, WithMaxConcurrentRequests(1, 0), WithMaxRequestAge(50*time.Millisecond))
+
+	go callBlock(srv, 1)
+	time.Sleep(20 * time.Millisecond) // let request 1 take the slot
+	queued := make(chan struct{})
+	go func() {
+		callBlock(srv, 2)
+		close(queued)
+	}()
+	waitForDepth(t, srv, 1)
+
+	// Request 2 was fresh when it queued but is stale once it gets the slot
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	<-queued
+
+	deadline := time.Now().Add(2 * time.Second)
+	for mockTransport.responseCount() != 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := mockTransport.responseCount(); got != 2 {
+		t.Fatalf("got %d responses, want both requests answered", got)
+	}
+	for i := 0; i < 2; i++ {
+		resp := mockTransport.responseAt(i)
+		switch resp.ID {
+		case 1:
+			if resp.Error != nil {
+				t.Errorf("request 1 failed: %+v", resp.Error)
+			}
+		case 2:
+			if resp.Error == nil || resp.Error.Code != protocol.RequestTimeout {
+				t.Errorf("request 2 = %+v, want RequestTimeout", resp.Error)
+			}
+		}
+	}
+}
+
+func TestQueuedRequestAbandonedGetsError(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	srv, mockTransport := blockingServer(release, WithMaxConcurrentRequests(1, 0))
+
+	go callBlock(srv, 1)
+	waitForDepth(t, srv, 0)
+	time.Sleep(20 * time.Millisecond) // let request 1 take the slot
+
+	// The connection behind request 2 goes away while it waits for the slot
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		req := &protocol.Request{
+			JSONRPC: "2.0", ID: 2, Method: protocol.MethodToolsCall,
+			Params: json.RawMessage(`{"name":"block","arguments":{}}`),
+		}
+		srv.handleRequest(context.Background(), req.WithContext(ctx), time.Now())
+		close(done)
+	}()
+	waitForDepth(t, srv, 1)
+	cancel()
+	<-done
+
+	resp := mockTransport.responseAt(0)
+	if resp == nil || resp.ID != 2 || resp.Error == nil || resp.Error.Code != protocol.RequestTimeout {
+		t.Errorf("abandoned request answered %+v, want RequestTimeout", resp)
+	}
+}
+
+func TestTimedOutHandlerKeepsSlotUntilItReturns(t *testing.T) {
+	release := make(chan struct{})
+	handlerCtx := make(chan context.Context, 1)
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "block"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		handlerCtx <- ctx
+		<-release // ignores its context
+		return &protocol.CallToolResponse{}, nil
+	})
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).Tool(router).With(
+		WithMaxConcurrentRequests(1, 0),
+		WithMethodTimeout(protocol.MethodToolsCall, 20*time.Millisecond),
+	).Build()
+
+	callBlock(srv, 1)
+	if resp := mockTransport.responseAt(0); resp.Error == nil || resp.Error.Code != protocol.RequestTimeout {
+		t.Fatalf("response = %+v, want RequestTimeout", resp)
+	}
+	if ctx := <-handlerCtx; ctx.Err() == nil {
+		t.Error("the abandoned handler's context should be cancelled")
+	}
+
+	// The abandoned handler still holds the slot, so the next call queues
+	go callBlock(srv, 2)
+	waitForDepth(t, srv, 1)
+
+	close(release)
+	waitForDepth(t, srv, 0)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestContextErrorsBecomeRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		call    handler.CallFunc
+		message string
+	}{
+		{
+			name:    "deadline",
+			timeout: 20 * time.Millisecond,
+			call: func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			message: "request tools/call timed out",
+		},
+		{
+			name: "wrapped deadline",
+			call: func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+				return nil, fmt.Errorf("upstream call: %w", context.DeadlineExceeded)
+			},
+			message: "request tools/call timed out",
+		},
+		{
+			name: "cancelled",
+			call: func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+				return nil, context.Canceled
+			},
+			message: "request tools/call was cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := handler.NewToolRouter()
+			router.Register(protocol.Tool{Name: "slow"}, tt.call)
+			mockTransport := newMockTransport()
+			srv := Builder().
+				Transport(mockTransport).
+				Tool(router).
+				With(WithRequestTimeout(tt.timeout)).
+				Build()
+
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+				Params: json.RawMessage(`{"name":"slow"}`),
+			}, time.Now())
+
+			resp := mockTransport.responseAt(0)
+			if resp.Error == nil {
+				t.Fatalf("expected an error response, got %+v", resp)
+			}
+			if resp.Error.Code != protocol.RequestTimeout || resp.Error.Message != tt.message {
+				t.Errorf("error = %+v, want code %d and message %q", resp.Error, protocol.RequestTimeout, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// decodeParams unmarshals request params into v. With Options.StrictDecoding
+// a field v does not declare is rejected with InvalidParams; "_meta" is
+// exempt because the spec allows it on every request.
+func (s *Server) decodeParams(params json.RawMessage, v interface{}) error {
+	if !s.options.StrictDecoding || len(params) == 0 {
+		return json.Unmarshal(params, v)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(params, &fields); err == nil {
+		if _, ok := fields["_meta"]; ok {
+			delete(fields, "_meta")
+			if params, err = json.Marshal(fields); err != nil {
+				return err
+			}
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return &protocol.Error{Code: protocol.InvalidParams, Message: fmt.Sprintf("strict decoding: %v", err)}
+	}
+	return nil
+}
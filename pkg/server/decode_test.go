@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func callWithParams(t *testing.T, strict bool, params string) *protocol.Response {
+	t.Helper()
+	mockTransport := newMockTransport()
+	b := Builder().
+		Transport(mockTransport).
+		Tool(&mockToolHandler{result: &protocol.CallToolResponse{
+			Content: []protocol.ToolContent{{Type: "text", Text: "ok"}},
+		}})
+	if strict {
+		b = b.With(WithStrictDecoding())
+	}
+	srv := b.Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+		Params: json.RawMessage(params),
+	}, time.Now())
+	if mockTransport.responseCount() != 1 {
+		t.Fatalf("expected 1 response, got %d", mockTransport.responseCount())
+	}
+	return mockTransport.responseAt(0)
+}
+
+func TestStrictDecodingRejectsUnknownFields(t *testing.T) {
+	resp := callWithParams(t, true, `{"name":"test_tool","arguments":{},"bogus":true}`)
+	if resp.Error == nil {
+		t.Fatal("expected an error for an unknown field in strict mode")
+	}
+	if resp.Error.Code != protocol.InvalidParams {
+		t.Errorf("code = %d, want InvalidParams", resp.Error.Code)
+	}
+}
+
+func TestStrictDecodingOffIgnoresUnknownFields(t *testing.T) {
+	resp := callWithParams(t, false, `{"name":"test_tool","arguments":{},"bogus":true}`)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+}
+
+func TestStrictDecodingAllowsMeta(t *testing.T) {
+	resp := callWithParams(t, true, `{"name":"test_tool","arguments":{},"_meta":{"progressToken":"p"}}`)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+}
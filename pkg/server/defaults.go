@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// findTool looks name up via ListTools. It returns nil, nil when the handler
+// does not list such a tool.
+func findTool(ctx context.Context, h handler.ToolHandler, name string) (*protocol.Tool, error) {
+	list, err := h.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Tools {
+		if list.Tools[i].Name == name {
+			return &list.Tools[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// applyArgumentDefaults fills in the defaults declared in the tool's
+// InputSchema for arguments the client omitted. Unknown tools are left
+// alone for the handler to reject.
+func applyArgumentDefaults(ctx context.Context, h handler.ToolHandler, req *protocol.CallToolRequest) error {
+	tool, err := findTool(ctx, h, req.Name)
+	if err != nil || tool == nil {
+		return err
+	}
+	args, err := protocol.ApplySchemaDefaults(tool.InputSchema, req.Arguments)
+	if err != nil {
+		return fmt.Errorf("tool %s: %w", req.Name, err)
+	}
+	req.Arguments = args
+	return nil
+}
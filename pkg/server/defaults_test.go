@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestArgumentDefaultsInjected(t *testing.T) {
+	var got map[string]interface{}
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{
+		Name: "search",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"query": {"type": "string"},
+				"limit": {"type": "integer", "default": 25}
+			},
+			"required": ["query"]
+		}`),
+	}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		got = req.Arguments
+		return &protocol.CallToolResponse{}, nil
+	})
+
+	for _, tt := range []struct {
+		name      string
+		opts      []Option
+		args      string
+		wantLimit interface{}
+	}{
+		{"omitted", []Option{WithArgumentDefaults()}, `{"query":"mcp"}`, float64(25)},
+		{"explicit", []Option{WithArgumentDefaults()}, `{"query":"mcp","limit":5}`, float64(5)},
+		{"disabled", nil, `{"query":"mcp"}`, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			mockTransport := newMockTransport()
+			srv := Builder().Transport(mockTransport).Tool(router).With(tt.opts...).Build()
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+				Params: json.RawMessage(`{"name":"search","arguments":` + tt.args + `}`),
+			}, time.Now())
+
+			if resp := mockTransport.responseAt(0); resp.Error != nil {
+				t.Fatalf("tools/call failed: %+v", resp.Error)
+			}
+			if got["query"] != "mcp" || got["limit"] != tt.wantLimit {
+				t.Errorf("handler arguments = %v, want limit %v", got, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/version"
+)
+
+// isDescribeMethod reports whether method is the introspection request and
+// Options.Introspection allows it
+func (s *Server) isDescribeMethod(method string) bool {
+	return s.options.Introspection && method == protocol.MethodDescribe
+}
+
+// describe answers protocol.MethodDescribe. Capabilities are those the
+// caller's initialize negotiated, or what a client with no capabilities
+// would get before it initialized.
+func (s *Server) describe(ctx context.Context) (*protocol.DescribeResponse, error) {
+	var client protocol.ClientCapabilities
+	if caps := s.clientCapabilities(ctx); caps != nil {
+		client = *caps
+	}
+	info := version.GetInfo()
+	resp := &protocol.DescribeResponse{
+		ServerInfo: protocol.ServerInfo{
+			Name:       s.options.Name,
+			Title:      s.options.Title,
+			Version:    s.options.Version,
+			Icons:      s.options.Icons,
+			WebsiteURL: s.options.WebsiteURL,
+		},
+		LibraryVersion:  info.Version,
+		GoVersion:       info.GoVersion,
+		UptimeSeconds:   s.options.Clock.Now().Sub(s.startedAt).Seconds(),
+		Capabilities:    s.serverCapabilities(client),
+		ResourceSchemes: []string{},
+		Prompts:         []string{},
+	}
+
+	if h := s.toolHandler(); h != nil {
+		list, err := h.ListTools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list tools: %w", err)
+		}
+		if list != nil {
+			resp.ToolCount = len(list.Tools)
+		}
+	}
+
+	if h := s.registry.GetResourceHandler(); h != nil {
+		list, err := h.ListResources(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list resources: %w", err)
+		}
+		seen := map[string]bool{}
+		for _, resource := range resourcesOf(list) {
+			scheme, _, ok := strings.Cut(resource.URI, ":")
+			scheme = strings.ToLower(scheme)
+			if ok && scheme != "" && !seen[scheme] {
+				seen[scheme] = true
+				resp.ResourceSchemes = append(resp.ResourceSchemes, scheme)
+			}
+		}
+		sort.Strings(resp.ResourceSchemes)
+	}
+
+	if h := s.registry.GetPromptHandler(); h != nil {
+		list, err := h.ListPrompts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list prompts: %w", err)
+		}
+		for _, prompt := range promptsOf(list) {
+			resp.Prompts = append(resp.Prompts, prompt.Name)
+		}
+	}
+	return resp, nil
+}
+
+// resourcesOf returns list's resources, tolerating a nil list
+func resourcesOf(list *protocol.ListResourcesResponse) []protocol.Resource {
+	if list == nil {
+		return nil
+	}
+	return list.Resources
+}
+
+// promptsOf returns list's prompts, tolerating a nil list
+func promptsOf(list *protocol.ListPromptsResponse) []protocol.Prompt {
+	if list == nil {
+		return nil
+	}
+	return list.Prompts
+}
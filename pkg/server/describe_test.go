@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/version"
+)
+
+func TestDescribe(t *testing.T) {
+	clock := &stepClock{now: time.Unix(1700000000, 0)}
+
+	tools := handler.NewToolRouter()
+	tools.Register(protocol.Tool{Name: "echo"}, nil)
+	tools.Register(protocol.Tool{Name: "sum"}, nil)
+	prompts := handler.NewPromptRouter()
+	prompts.Register(protocol.Prompt{Name: "review"}, nil)
+	resources := handler.NewHandlerRegistry()
+	resources.RegisterStaticResources([]handler.StaticResource{
+		{Resource: protocol.Resource{URI: "file:///a.txt", Name: "a"}},
+		{Resource: protocol.Resource{URI: "db://users", Name: "users"}},
+		{Resource: protocol.Resource{URI: "file:///b.txt", Name: "b"}},
+	})
+
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Name("describe-test").
+		Version("2.1.0").
+		Transport(mockTransport).
+		Tool(tools).
+		Resource(resources.GetResourceHandler()).
+		Prompt(prompts).
+		With(WithIntrospection(true), WithClock(clock)).
+		Build()
+
+	clock.mu.Lock()
+	clock.now = clock.now.Add(90 * time.Second)
+	clock.mu.Unlock()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodDescribe,
+	}, time.Now())
+
+	resp := mockTransport.responseAt(0)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error %+v", resp.Error)
+	}
+	got, ok := resp.Result.(*protocol.DescribeResponse)
+	if !ok {
+		t.Fatalf("result = %T, want *protocol.DescribeResponse", resp.Result)
+	}
+	if got.ServerInfo.Name != "describe-test" || got.ServerInfo.Version != "2.1.0" {
+		t.Errorf("serverInfo = %+v", got.ServerInfo)
+	}
+	if got.LibraryVersion != version.Version || got.GoVersion == "" {
+		t.Errorf("libraryVersion = %q, goVersion = %q", got.LibraryVersion, got.GoVersion)
+	}
+	if got.UptimeSeconds != 90 {
+		t.Errorf("uptimeSeconds = %v, want 90", got.UptimeSeconds)
+	}
+	if got.ToolCount != 2 {
+		t.Errorf("toolCount = %d, want 2", got.ToolCount)
+	}
+	if len(got.ResourceSchemes) != 2 || got.ResourceSchemes[0] != "db" || got.ResourceSchemes[1] != "file" {
+		t.Errorf("resourceSchemes = %v, want [db file]", got.ResourceSchemes)
+	}
+	if len(got.Prompts) != 1 || got.Prompts[0] != "review" {
+		t.Errorf("prompts = %v, want [review]", got.Prompts)
+	}
+	if got.Capabilities.Tools == nil || got.Capabilities.Resources == nil || got.Capabilities.Prompts == nil {
+		t.Errorf("capabilities = %+v, want tools, resources and prompts", got.Capabilities)
+	}
+}
+
+func TestDescribeDisabledByDefault(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodDescribe,
+	}, time.Now())
+
+	if resp := mockTransport.responseAt(0); resp.Error == nil {
+		t.Errorf("expected an error for %s without introspection, got %+v", protocol.MethodDescribe, resp.Result)
+	}
+}
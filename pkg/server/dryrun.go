@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// dryRunKey is the context key marking a request the client sent with
+// `_meta.dryRun: true`.
+type dryRunKey struct{}
+
+// IsDryRun reports whether the request being handled was sent with
+// `_meta.dryRun: true`. Tool handlers should validate their inputs and skip
+// side effects when it returns true.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// validateToolCall is the validation-only dry-run path: it looks the tool up
+// via ListTools, checks the arguments against its InputSchema and reports the
+// outcome without calling CallTool. Validation failures come back as an
+// IsError result so the client sees them as a tool-level error.
+func validateToolCall(ctx context.Context, h handler.ToolHandler, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	tool, err := findTool(ctx, h, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if tool == nil {
+		return nil, fmt.Errorf("unknown tool: %s", req.Name)
+	}
+	args := req.Arguments
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	if err := protocol.ValidateAgainstSchema(tool.InputSchema, args); err != nil {
+		return &protocol.CallToolResponse{
+			Content: []protocol.ToolContent{{Type: "text", Text: fmt.Sprintf("dry run: invalid arguments: %v", err)}},
+			IsError: true,
+		}, nil
+	}
+	return &protocol.CallToolResponse{
+		Content: []protocol.ToolContent{{Type: "text", Text: "dry run: arguments valid"}},
+	}, nil
+}
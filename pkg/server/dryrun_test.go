@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// sideEffectToolHandler records whether CallTool ran and whether it saw the
+// dry-run flag.
+type sideEffectToolHandler struct {
+	called    bool
+	sawDryRun bool
+}
+
+func (h *sideEffectToolHandler) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	return &protocol.ListToolsResponse{Tools: []protocol.Tool{{
+		Name:        "delete_file",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"path":{"type":"string"}},"required":["path"]}`),
+	}}}, nil
+}
+
+func (h *sideEffectToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	h.called = true
+	h.sawDryRun = IsDryRun(ctx)
+	return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: "deleted"}}}, nil
+}
+
+func callDryRun(srv *Server, id int, args string) {
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"delete_file","arguments":` + args + `,"_meta":{"dryRun":true}}`),
+	}, time.Now())
+}
+
+func TestDryRunFlagVisibleInHandler(t *testing.T) {
+	tool := &sideEffectToolHandler{}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(tool)
+	srv := New(Options{Registry: registry, Transport: newMockTransport()})
+
+	callDryRun(srv, 1, `{"path":"/tmp/x"}`)
+
+	if !tool.called {
+		t.Fatal("handler should be called when ValidateOnlyDryRun is off")
+	}
+	if !tool.sawDryRun {
+		t.Error("IsDryRun(ctx) = false inside handler, want true")
+	}
+	if IsDryRun(context.Background()) {
+		t.Error("IsDryRun on a plain context should be false")
+	}
+}
+
+func TestValidateOnlyDryRunSkipsHandler(t *testing.T) {
+	tool := &sideEffectToolHandler{}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(tool)
+	transp := newMockTransport()
+	srv := New(Options{Registry: registry, Transport: transp, ValidateOnlyDryRun: true})
+
+	callDryRun(srv, 1, `{"path":"/tmp/x"}`)
+	callDryRun(srv, 2, `{}`)
+
+	if tool.called {
+		t.Fatal("handler must not run in validation-only dry-run mode")
+	}
+	if transp.responseCount() != 2 {
+		t.Fatalf("responses = %d, want 2", transp.responseCount())
+	}
+	valid := transp.responseAt(0).Result.(*protocol.CallToolResponse)
+	if valid.IsError {
+		t.Errorf("valid arguments reported as error: %+v", valid.Content)
+	}
+	invalid := transp.responseAt(1).Result.(*protocol.CallToolResponse)
+	if !invalid.IsError {
+		t.Error("missing required argument should produce an IsError result")
+	}
+}
@@ -46,7 +46,7 @@ func (s *Server) Elicit(
 	message string,
 	requestedSchema json.RawMessage,
 ) (*protocol.ElicitationResult, error) {
-	if !s.clientSupportsElicitation() {
+	if !s.clientSupportsElicitation(ctx) {
 		return nil, ErrElicitationNotSupported
 	}
 
@@ -77,7 +77,7 @@ func (s *Server) Elicit(
 
 	waitCh := s.outbound.register(id)
 
-	if err := s.transport.SendRequest(req); err != nil {
+	if err := s.sendRequest(ctx, req); err != nil {
 		s.outbound.cancel(id)
 		return nil, fmt.Errorf("send elicitation request: %w", err)
 	}
@@ -103,23 +103,22 @@ func (s *Server) Elicit(
 
 	case <-ctx.Done():
 		s.outbound.cancel(id)
-		s.emitCancelled(id, ctx.Err())
+		s.emitCancelled(ctx, id, ctx.Err())
 		return nil, ctx.Err()
 
 	case <-timeoutCh:
 		s.outbound.cancel(id)
-		s.emitCancelled(id, errors.New("elicitation timeout"))
+		s.emitCancelled(ctx, id, errors.New("elicitation timeout"))
 		return nil, fmt.Errorf("elicitation timed out after %v", defaultElicitationTimeout)
 	}
 }
 
-// clientSupportsElicitation reports whether the most recent initialize
-// handshake declared elicitation support. Returns false if no initialize
-// has happened yet.
-func (s *Server) clientSupportsElicitation() bool {
-	s.clientCapsMu.RLock()
-	defer s.clientCapsMu.RUnlock()
-	return s.clientCaps != nil && s.clientCaps.Elicitation != nil
+// clientSupportsElicitation reports whether the client that sent the request
+// handled in ctx declared elicitation support at initialize. Returns false
+// if it has not initialized yet.
+func (s *Server) clientSupportsElicitation(ctx context.Context) bool {
+	caps := s.clientCapabilities(ctx)
+	return caps != nil && caps.Elicitation != nil
 }
 
 // acquireElicitMu takes the elicitation mutex while honouring ctx.
@@ -146,12 +145,12 @@ func (s *Server) acquireElicitMu(ctx context.Context) error {
 
 // emitCancelled sends notifications/cancelled for an outbound server→client
 // request the server has abandoned (timeout or ctx cancel).
-func (s *Server) emitCancelled(id interface{}, cause error) {
+func (s *Server) emitCancelled(ctx context.Context, id interface{}, cause error) {
 	reason := "cancelled"
 	if cause != nil {
 		reason = cause.Error()
 	}
-	if err := s.SendNotification(protocol.NotificationCancelled, protocol.CancelledParams{
+	if err := s.notify(ctx, protocol.NotificationCancelled, protocol.CancelledParams{
 		RequestID: id,
 		Reason:    reason,
 	}); err != nil {
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestErrorMessageFunc(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "query"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		return nil, errors.New("dial tcp 10.0.0.5:5432: connection refused")
+	})
+	router.Register(protocol.Tool{Name: "strict"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		return nil, &protocol.Error{Code: protocol.InvalidParams, Message: "limit must be positive"}
+	})
+
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(router).
+		With(WithErrorMessageFunc(func(code int, err error) string {
+			if code == protocol.InternalError {
+				return "internal error"
+			}
+			return err.Error()
+		})).
+		Build()
+
+	for i, name := range []string{"query", "strict"} {
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0", ID: i + 1, Method: protocol.MethodToolsCall,
+			Params: json.RawMessage(`{"name":"` + name + `","arguments":{}}`),
+		}, time.Now())
+	}
+
+	hidden := mockTransport.responseAt(0).Error
+	if hidden == nil || hidden.Code != protocol.InternalError || hidden.Message != "internal error" {
+		t.Errorf("internal error response = %+v, want the customized message", hidden)
+	}
+	if !strings.Contains(buf.String(), "connection refused") {
+		t.Errorf("original error was not logged:\n%s", buf.String())
+	}
+
+	kept := mockTransport.responseAt(1).Error
+	if kept == nil || kept.Code != protocol.InvalidParams || kept.Message != "limit must be positive" {
+		t.Errorf("protocol error response = %+v, want it unchanged", kept)
+	}
+}
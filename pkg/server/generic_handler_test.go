@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// echoGenericHandler answers every method with its name and raw params.
+type echoGenericHandler struct {
+	methods []string
+}
+
+func (h *echoGenericHandler) Initialize(ctx context.Context, req *protocol.InitializeRequest) (*protocol.InitializeResponse, error) {
+	return &protocol.InitializeResponse{}, nil
+}
+
+func (h *echoGenericHandler) HandleRequest(ctx context.Context, method string, params []byte) (interface{}, error) {
+	h.methods = append(h.methods, method)
+	return map[string]interface{}{"method": method, "params": json.RawMessage(params)}, nil
+}
+
+func TestGenericHandlerReceivesCustomMethods(t *testing.T) {
+	mockTransport := newMockTransport()
+	registry := handler.NewHandlerRegistry()
+	generic := &echoGenericHandler{}
+	registry.RegisterHandler(generic)
+	srv := New(Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: mockTransport,
+	})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: "custom/echo", Params: json.RawMessage(`{"x":1}`),
+	}, time.Now())
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 2, Method: protocol.MethodPing,
+	}, time.Now())
+
+	if mockTransport.responseCount() != 2 {
+		t.Fatalf("responses = %d, want 2", mockTransport.responseCount())
+	}
+	resp := mockTransport.responseAt(0)
+	if resp.Error != nil {
+		t.Fatalf("custom method error: %+v", resp.Error)
+	}
+	result := resp.Result.(map[string]interface{})
+	if result["method"] != "custom/echo" || string(result["params"].(json.RawMessage)) != `{"x":1}` {
+		t.Errorf("result = %+v", result)
+	}
+	if len(generic.methods) != 1 {
+		t.Errorf("generic handler saw %v; built-in methods must not reach it", generic.methods)
+	}
+
+	registry.UnregisterHandler()
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 3, Method: "custom/echo",
+	}, time.Now())
+	if resp := mockTransport.responseAt(2); resp.Error == nil {
+		t.Errorf("expected an error for an unknown method once the handler is gone, got %+v", resp.Result)
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestInitializeMeta(t *testing.T) {
+	tests := []struct {
+		name string
+		meta map[string]interface{}
+	}{
+		{name: "set", meta: map[string]interface{}{"features": []interface{}{"streaming"}}},
+		{name: "empty", meta: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTransport := newMockTransport()
+			srv := Builder().
+				Transport(mockTransport).
+				With(WithInitializeMeta(tt.meta)).
+				Build()
+
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0", ID: 1, Method: protocol.MethodInitialize,
+				Params: json.RawMessage(`{"protocolVersion":"2025-11-25","capabilities":{}}`),
+			}, time.Now())
+
+			raw, err := json.Marshal(mockTransport.responseAt(0).Result)
+			if err != nil {
+				t.Fatalf("marshal result: %v", err)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(raw, &got); err != nil {
+				t.Fatalf("unmarshal result: %v", err)
+			}
+
+			value, present := got["_meta"]
+			if tt.meta == nil {
+				if present {
+					t.Errorf("_meta = %v, want field omitted", value)
+				}
+				return
+			}
+			if string(mustMarshal(t, value)) != string(mustMarshal(t, tt.meta)) {
+				t.Errorf("_meta = %v, want %v", value, tt.meta)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// renameArgument rewrites tools/call requests so a legacy argument name
+// reaches the handler under its new name.
+func renameArgument(from, to string) func(*protocol.Request) {
+	return func(req *protocol.Request) {
+		if req.Method != protocol.MethodToolsCall {
+			return
+		}
+		var params protocol.CallToolRequest
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return
+		}
+		if v, ok := params.Arguments[from]; ok {
+			delete(params.Arguments, from)
+			params.Arguments[to] = v
+			req.Params, _ = json.Marshal(params)
+		}
+	}
+}
+
+func TestRequestAndResponseInterceptors(t *testing.T) {
+	var got map[string]interface{}
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "fetch"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		got = req.Arguments
+		return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: "ok"}}}, nil
+	})
+
+	var seenID interface{}
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(router).
+		With(
+			WithRequestInterceptor(renameArgument("url", "uri")),
+			WithResponseInterceptor(func(id interface{}, result interface{}) interface{} {
+				seenID = id
+				return map[string]interface{}{"wrapped": result}
+			}),
+		).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 9, Method: protocol.MethodToolsCall,
+		Params: json.RawMessage(`{"name":"fetch","arguments":{"url":"https://example.com"}}`),
+	}, time.Now())
+
+	if _, legacy := got["url"]; legacy || got["uri"] != "https://example.com" {
+		t.Errorf("handler arguments = %v, want url renamed to uri", got)
+	}
+
+	resp := mockTransport.responseAt(0)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	wrapped, ok := resp.Result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("result = %T, want the interceptor's wrapper", resp.Result)
+	}
+	if inner, ok := wrapped["wrapped"].(*protocol.CallToolResponse); !ok || inner.Content[0].Text != "ok" {
+		t.Errorf("wrapped result = %+v, want the handler's response", wrapped["wrapped"])
+	}
+	if seenID != 9 {
+		t.Errorf("response interceptor saw id %v, want 9", seenID)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+func TestRequireInitializeGatesRequests(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(&mockToolHandler{result: &protocol.CallToolResponse{}}).
+		With(WithRequireInitialize()).
+		Build()
+
+	call := func(id int) *protocol.Response {
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0", ID: id, Method: protocol.MethodToolsCall,
+			Params: json.RawMessage(`{"name":"echo","arguments":{}}`),
+		}, time.Now())
+		return mockTransport.responseAt(mockTransport.responseCount() - 1)
+	}
+
+	if resp := call(1); resp.Error == nil || resp.Error.Code != protocol.NotInitialized {
+		t.Fatalf("pre-initialize tools/call = %+v, want NotInitialized", resp)
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 2, Method: protocol.MethodPing,
+	}, time.Now())
+	if resp := mockTransport.responseAt(1); resp.Error != nil {
+		t.Errorf("ping must be allowed before initialize, got %+v", resp.Error)
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 3, Method: protocol.MethodInitialize,
+		Params: json.RawMessage(`{"protocolVersion":"2025-06-18","clientInfo":{"name":"c","version":"1"},"capabilities":{}}`),
+	}, time.Now())
+	if resp := mockTransport.responseAt(2); resp.Error != nil {
+		t.Fatalf("initialize failed: %+v", resp.Error)
+	}
+
+	// Still gated until the client confirms with notifications/initialized.
+	if resp := call(4); resp.Error == nil || resp.Error.Code != protocol.NotInitialized {
+		t.Errorf("tools/call between initialize and initialized = %+v, want NotInitialized", resp)
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", Method: protocol.NotificationInitialized,
+	}, time.Now())
+	if resp := call(5); resp.Error != nil {
+		t.Errorf("post-initialize tools/call failed: %+v", resp.Error)
+	}
+}
+
+func TestRequireInitializeTracksEachSession(t *testing.T) {
+	transp := &sessionTransport{mockTransport: newMockTransport(), sent: make(map[string][]*protocol.Notification)}
+	srv := Builder().
+		Transport(transp).
+		Tool(&mockToolHandler{result: &protocol.CallToolResponse{}}).
+		With(WithRequireInitialize()).
+		Build()
+
+	send := func(session string, req *protocol.Request) {
+		srv.handleRequest(context.Background(), req.WithContext(transport.WithSessionID(context.Background(), session)), time.Now())
+	}
+	call := func(session string, id int) *protocol.Response {
+		send(session, &protocol.Request{
+			JSONRPC: "2.0", ID: id, Method: protocol.MethodToolsCall,
+			Params: json.RawMessage(`{"name":"echo","arguments":{}}`),
+		})
+		return transp.responseAt(transp.responseCount() - 1)
+	}
+
+	send("a", &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodInitialize,
+		Params: json.RawMessage(`{"protocolVersion":"2025-06-18","clientInfo":{"name":"c","version":"1"},"capabilities":{}}`),
+	})
+	send("a", &protocol.Request{JSONRPC: "2.0", Method: protocol.NotificationInitialized})
+
+	if !srv.IsSessionReady("a") || srv.IsSessionReady("b") {
+		t.Errorf("ready a=%v b=%v, want only a", srv.IsSessionReady("a"), srv.IsSessionReady("b"))
+	}
+	if resp := call("a", 2); resp.Error != nil {
+		t.Errorf("tools/call from the initialized session failed: %+v", resp.Error)
+	}
+	if resp := call("b", 3); resp.Error == nil || resp.Error.Code != protocol.NotInitialized {
+		t.Errorf("tools/call from the other session = %+v, want NotInitialized", resp)
+	}
+
+	// Re-initializing one session does not affect the other
+	send("b", &protocol.Request{
+		JSONRPC: "2.0", ID: 4, Method: protocol.MethodInitialize,
+		Params: json.RawMessage(`{"protocolVersion":"2025-06-18","clientInfo":{"name":"c","version":"1"},"capabilities":{}}`),
+	})
+	if resp := call("a", 5); resp.Error != nil {
+		t.Errorf("session a lost its handshake when b initialized: %+v", resp.Error)
+	}
+}
+
+func TestRequestsAllowedBeforeInitializeByDefault(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsList,
+	}, time.Now())
+	if resp := mockTransport.responseAt(0); resp.Error != nil {
+		t.Errorf("tools/list without RequireInitialize = %+v, want success", resp.Error)
+	}
+}
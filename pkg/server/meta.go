@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// extractMeta returns the `_meta` object from a request's params, or nil if
+// absent. Like extractProgressToken it tolerates malformed `_meta` so a bad
+// metadata block never fails an otherwise-valid request.
+func extractMeta(params json.RawMessage) map[string]interface{} {
+	if len(params) == 0 {
+		return nil
+	}
+	var envelope struct {
+		Meta map[string]interface{} `json:"_meta"`
+	}
+	if err := json.Unmarshal(params, &envelope); err != nil {
+		log.Printf("malformed _meta on request; ignoring: %v", err)
+		return nil
+	}
+	return envelope.Meta
+}
+
+// clientTimeout reads the deadline a client advertised in
+// `_meta.timeoutMs`. It returns zero when there is none, including for
+// values too large to represent as a time.Duration, and an error for a
+// negative value.
+func clientTimeout(params json.RawMessage) (time.Duration, error) {
+	ms, ok := extractMeta(params)["timeoutMs"].(float64)
+	if !ok || ms == 0 {
+		return 0, nil
+	}
+	if ms < 0 {
+		return 0, fmt.Errorf("_meta.timeoutMs must not be negative, got %v", ms)
+	}
+	if ms >= float64(math.MaxInt64)/float64(time.Millisecond) {
+		return 0, nil
+	}
+	return time.Duration(ms * float64(time.Millisecond)), nil
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// recordingToolHandler captures the last CallToolRequest it received.
+type recordingToolHandler struct {
+	got chan *protocol.CallToolRequest
+}
+
+func (h *recordingToolHandler) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	return &protocol.ListToolsResponse{Tools: []protocol.Tool{}}, nil
+}
+
+func (h *recordingToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	h.got <- req
+	return &protocol.CallToolResponse{
+		Content: []protocol.ToolContent{{Type: "text", Text: "ok"}},
+	}, nil
+}
+
+func TestToolMiddlewareInjectsDefaultArgument(t *testing.T) {
+	transp := newMockTransport()
+	tool := &recordingToolHandler{got: make(chan *protocol.CallToolRequest, 2)}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(tool)
+
+	injectLimit := func(next handler.CallFunc) handler.CallFunc {
+		return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+			if req.Arguments == nil {
+				req.Arguments = map[string]interface{}{}
+			}
+			if _, ok := req.Arguments["limit"]; !ok {
+				req.Arguments["limit"] = float64(10)
+			}
+			return next(ctx, req)
+		}
+	}
+
+	srv := New(Options{
+		Name:           "middleware-test-server",
+		Version:        "1.0.0",
+		Registry:       registry,
+		Transport:      transp,
+		ToolMiddleware: []handler.ToolMiddleware{injectLimit},
+	})
+	go srv.Run()
+
+	transp.requests <- &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"search","arguments":{"query":"x"}}`),
+	}
+	transp.requests <- &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      2,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"search","arguments":{"query":"x","limit":3}}`),
+	}
+
+	limits := map[float64]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case req := <-tool.got:
+			limit, _ := req.Arguments["limit"].(float64)
+			limits[limit] = true
+		case <-time.After(time.Second):
+			t.Fatal("tool handler was not called")
+		}
+	}
+
+	if !limits[10] {
+		t.Error("middleware did not inject default limit when absent")
+	}
+	if !limits[3] {
+		t.Error("middleware overwrote an explicit limit")
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestNotificationWithIDAcknowledged(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		With(WithNotificationIDPolicy(NotificationIDAcknowledge)).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 7, Method: protocol.NotificationInitialized,
+	}, time.Now())
+
+	if got := mockTransport.responseCount(); got != 1 {
+		t.Fatalf("expected 1 response, got %d", got)
+	}
+	resp := mockTransport.responseAt(0)
+	if resp.Error != nil || resp.ID != 7 {
+		t.Errorf("response = %+v, want an empty result for id 7", resp)
+	}
+	if !srv.IsReady() {
+		t.Error("notification should still be handled")
+	}
+}
+
+func TestNotificationWithIDRejected(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		With(WithNotificationIDPolicy(NotificationIDReject)).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 7, Method: protocol.NotificationInitialized,
+	}, time.Now())
+
+	if got := mockTransport.responseCount(); got != 1 {
+		t.Fatalf("expected 1 response, got %d", got)
+	}
+	if resp := mockTransport.responseAt(0); resp.Error == nil || resp.Error.Code != protocol.InvalidRequest {
+		t.Errorf("error = %v, want InvalidRequest", resp.Error)
+	}
+	if srv.IsReady() {
+		t.Error("rejected notification should not be handled")
+	}
+}
+
+func TestNotificationWithoutIDNeverAnswered(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		With(WithNotificationIDPolicy(NotificationIDAcknowledge)).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", Method: protocol.NotificationInitialized,
+	}, time.Now())
+
+	if got := mockTransport.responseCount(); got != 0 {
+		t.Errorf("server sent %d responses to a notification; want none", got)
+	}
+}
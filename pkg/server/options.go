@@ -1,6 +1,10 @@
 package server
 
 import (
+	"context"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/async"
 	"github.com/gomcpgo/mcp/pkg/handler"
 	"github.com/gomcpgo/mcp/pkg/protocol"
 	"github.com/gomcpgo/mcp/pkg/transport"
@@ -17,8 +21,252 @@ type Options struct {
 	WebsiteURL string
 	Registry   *handler.HandlerRegistry
 	Transport  transport.Transport
+
+	// Capabilities holds optional notification-related flags (listChanged,
+	// subscribe) to advertise for the registered handlers. Flags for a
+	// capability whose handler is not registered are ignored, and all of
+	// them are dropped for clients that cannot receive notifications.
+	Capabilities *protocol.Capabilities
+
+	// Instructions is returned in the initialize result as usage guidance
+	// for the client/model. Empty omits the field.
+	Instructions string
+
+	// InitializeMeta is returned as the initialize result's _meta, for
+	// server-specific data hosts may read (feature flags, supported models).
+	// Empty omits the field.
+	InitializeMeta map[string]interface{}
+
+	// ToolMiddleware wraps every tools/call dispatched to the registered
+	// ToolHandler. The first entry is the outermost layer.
+	ToolMiddleware []handler.ToolMiddleware
+
+	// RequestTimeout, when non-zero, bounds every request handler's context.
+	// MethodTimeouts overrides it per method (e.g. a longer deadline for
+	// tools/call than for resources/read).
+	RequestTimeout time.Duration
+	MethodTimeouts map[string]time.Duration
+
+	// RateLimit, when set, caps the request rate for every method that has
+	// no entry in MethodRateLimits; those methods share one bucket.
+	// MethodRateLimits gives a method its own bucket, and a zero RateLimit
+	// entry exempts the method. Requests over the limit are answered with
+	// protocol.RateLimited and a retryAfter hint without running the handler.
+	RateLimit        RateLimit
+	MethodRateLimits map[string]RateLimit
+
+	// CanonicalJSON makes every successful result go out as canonical JSON
+	// (see protocol.CanonicalJSON): object keys sorted, including struct
+	// fields, and no insignificant whitespace. Byte-identical output for
+	// equal results suits signing and golden-file tests, at the cost of an
+	// extra encode per response.
+	CanonicalJSON bool
+
+	// Clock is the time source for request timing, MaxRequestAge and rate
+	// limiting; defaults to the system clock. Tests inject a fake one to get
+	// deterministic durations. Handler deadlines still run on real time.
+	Clock Clock
+
+	// RateLimitKey, when set, derives a client key from each request's
+	// context, and every key is limited separately instead of sharing the
+	// limits above (see RateLimitByIdentity). A bucket is kept for each key
+	// seen, so keys should come from a bounded set such as authenticated
+	// identities.
+	RateLimitKey func(ctx context.Context) string
+
+	// MaxConcurrentRequests, when non-zero, caps how many requests are
+	// handled at once; the rest wait for a free slot (see Server.QueueDepth).
+	// MaxQueueDepth, when also non-zero, bounds that wait: a request
+	// arriving with the queue full is answered with protocol.ServerBusy
+	// instead. ping is never limited. A handler that outlives its request's
+	// timeout keeps its slot until it returns.
+	MaxConcurrentRequests int
+	MaxQueueDepth         int
+
+	// ValidateID, when set, is called with every inbound request id before
+	// dispatch. A non-nil error rejects the request with InvalidRequest.
+	// The id is a string or, for a numeric id, a json.Number (never a
+	// float64); see protocol.Request.ID.
+	ValidateID func(id interface{}) error
+
+	// RequestValidator, when set, inspects every request before dispatch,
+	// e.g. to cap the params size or require _meta fields. A non-nil
+	// return is sent to the client as the error and the handler is skipped.
+	RequestValidator func(*protocol.Request) *protocol.Error
+
+	// ErrorMessageFunc, when set, chooses the message sent to the client for
+	// an error returned by a handler, given the JSON-RPC code it maps to.
+	// Use it to localize messages or hide internal details; the original
+	// error is logged whenever the message is changed. Unset sends the
+	// error's own message.
+	ErrorMessageFunc func(code int, err error) string
+
+	// RequestInterceptor and ResponseInterceptor are compatibility shims run
+	// around dispatch. RequestInterceptor may rewrite the request in place,
+	// e.g. rename a legacy argument in Params, after validation and just
+	// before the handler is chosen. ResponseInterceptor receives every
+	// successful result and returns the one to send; errors bypass it.
+	RequestInterceptor  func(*protocol.Request)
+	ResponseInterceptor func(id interface{}, result interface{}) interface{}
+
+	// RedactKeys lists JSON object keys (matched case-insensitively, at any
+	// depth) whose values are replaced with "***" in the request/response
+	// log, e.g. "api_key" or "password" in tool arguments.
+	RedactKeys []string
+
+	// ValidateOnlyDryRun makes tools/call requests sent with
+	// `_meta.dryRun: true` stop after validating the arguments against the
+	// tool's InputSchema; the handler is never called. When false, dry-run
+	// calls reach the handler, which can detect them via IsDryRun(ctx).
+	ValidateOnlyDryRun bool
+
+	// ApplyArgumentDefaults makes tools/call fill in the "default" of every
+	// InputSchema property the client omitted before the handler (and any
+	// ToolMiddleware) runs, so defaults are declared once in the schema.
+	// The schema is looked up through ListTools on every call.
+	ApplyArgumentDefaults bool
+
+	// ValidateToolOutput makes tools/call check a successful result's
+	// StructuredContent against the tool's OutputSchema before sending it,
+	// catching handlers whose output drifts from what they declare. A
+	// mismatch, or a missing StructuredContent, is logged and answered with
+	// a generic InternalError. The schema is looked up through ListTools.
+	ValidateToolOutput bool
+
+	// ResolveResourceArguments makes tools/call replace every top-level
+	// argument of the form {"$resource": "<uri>"} (see
+	// protocol.ResourceArgument) with that resource's content, read through
+	// the registered ResourceHandler, before the handler runs. Text contents
+	// are concatenated; a resource made of a single blob contributes its
+	// base64 data, and any other resource with a blob fails the call. The
+	// URIs are normalized like resources/read ones when
+	// NormalizeResourceURIs is set.
+	ResolveResourceArguments bool
+
+	// RecoveryHandler is called when a handler panics and returns the error
+	// sent to the client. Returning nil, or leaving this unset, logs the
+	// stack trace and sends a generic InternalError.
+	RecoveryHandler RecoveryHandler
+
+	// SendRetries is how many times a response whose transport Send failed
+	// is sent again, waiting SendRetryBackoff before the first retry and
+	// twice as long before each later one. Only failures the transport
+	// marks with transport.Retryable, meaning nothing was written, are
+	// retried; the stdio transport never marks its writes. Zero sends once,
+	// as before; a transient write error then drops the response.
+	SendRetries      int
+	SendRetryBackoff time.Duration
+
+	// SendErrorHandler, when set, is called with each response that could
+	// not be sent after all retries, e.g. to close the client's session or
+	// record the failure. Otherwise the failure is only logged.
+	SendErrorHandler SendErrorHandler
+
+	// MaxRequestAge, when non-zero, drops requests that waited longer than
+	// this between arriving on the transport and being dispatched, whether
+	// before or while queued for a slot. The client has most likely given
+	// up on them, so they are answered with protocol.RequestTimeout instead
+	// of being run.
+	MaxRequestAge time.Duration
+
+	// MaxResponseBytes, when non-zero, caps the size of a successful result
+	// as marshaled JSON. A larger result is replaced by an InternalError
+	// stating the size and the limit, so one oversized tool output cannot
+	// exhaust client memory or clog the transport.
+	MaxResponseBytes int
+
+	// MaxResultContentBytes, when non-zero, caps the text and data carried
+	// by a tools/call result's content blocks. Content past the limit is cut
+	// off and a text block noting the truncation is appended, so a tool that
+	// accidentally returns a huge file still yields a usable result. Unlike
+	// MaxResponseBytes, the call succeeds.
+	MaxResultContentBytes int
+
+	// NormalizeResourceURIs makes resources/read validate the requested URI
+	// with protocol.NormalizeResourceURI before dispatch: malformed URIs and
+	// ".." path traversal are rejected with InvalidParams, and the handler
+	// sees the canonical form.
+	NormalizeResourceURIs bool
+
+	// StrictCapabilities makes tools/list, resources/list and prompts/list
+	// answer MethodNotFound when no handler is registered, instead of an
+	// empty list that can hide a misconfigured server.
+	StrictCapabilities bool
+
+	// StrictDecoding rejects request params carrying fields the server does
+	// not recognise with InvalidParams, instead of silently ignoring them.
+	// Meant for debugging protocol mismatches: it breaks forward
+	// compatibility with clients that send newer fields. "_meta" is always
+	// accepted.
+	StrictDecoding bool
+
+	// RequireInitialize enforces the MCP lifecycle: until the client has
+	// sent initialize and then notifications/initialized, every request
+	// other than initialize and ping is rejected with protocol.NotInitialized.
+	// On a multi-client transport every session must complete its own.
+	RequireInitialize bool
+
+	// ShutdownMethod, when set, names a method (e.g. "shutdown" or
+	// "notifications/exit") that makes Run return cleanly, for hosts that
+	// signal an intentional disconnect rather than just closing the stream.
+	// Sent as a request it is acknowledged with an empty result first.
+	ShutdownMethod string
+
+	// Introspection makes the server answer protocol.MethodDescribe
+	// (x-mcp/describe) with a protocol.DescribeResponse: server and library
+	// versions, uptime, capabilities, tool count, resource schemes and
+	// prompt names. Off by default, so the method is unknown.
+	Introspection bool
+
+	// NotificationIDPolicy decides what happens to a message in the
+	// notifications/ namespace that carries an id. By default it is handled
+	// and never answered, as JSON-RPC requires; clients that wrongly attach
+	// an id may then wait for a reply forever.
+	NotificationIDPolicy NotificationIDPolicy
+
+	// LogLevel is the threshold for the server's own request log, using the
+	// MCP level names (protocol.LogLevelDebug etc.). At info, the default,
+	// each request is logged as one line with its method, id and duration;
+	// debug adds the full request and response JSON, which is verbose and
+	// costly to render. Levels above info silence the request log.
+	LogLevel string
+
+	// AsyncExecutor, when set, adds the executor's built-in operation tools
+	// (continue_operation, cancel_operation, list_operations) to tools/list
+	// and tools/call, alongside any registered ToolHandler. The built-in
+	// names take precedence over the handler's tools of the same name.
+	AsyncExecutor *async.OperationExecutor
+
+	// Handlers set via WithToolHandler / WithResourceHandler /
+	// WithPromptHandler. New registers them into Registry (or the default
+	// registry) so option order relative to WithRegistry does not matter.
+	toolHandler     handler.ToolHandler
+	resourceHandler handler.ResourceHandler
+	promptHandler   handler.PromptHandler
 }
 
+// RecoveryHandler maps a recovered handler panic to the JSON-RPC error
+// returned for the request. It may also log or record metrics.
+type RecoveryHandler func(ctx context.Context, req *protocol.Request, recovered interface{}) *protocol.Error
+
+// NotificationIDPolicy selects how the server treats a notification sent
+// with an id
+type NotificationIDPolicy int
+
+const (
+	// NotificationIDIgnore handles the notification and sends no response
+	NotificationIDIgnore NotificationIDPolicy = iota
+	// NotificationIDAcknowledge handles the notification and answers the id
+	// with an empty result
+	NotificationIDAcknowledge
+	// NotificationIDReject answers the id with InvalidRequest without
+	// handling the notification
+	NotificationIDReject
+)
+
+// SendErrorHandler is told about a response the transport failed to send
+type SendErrorHandler func(resp *protocol.Response, err error)
+
 // Option is a function that can be used to configure the server
 type Option func(*Options)
 
@@ -50,6 +298,289 @@ func WithTransport(transport transport.Transport) Option {
 	}
 }
 
+// WithToolHandler registers h as the tool handler
+func WithToolHandler(h handler.ToolHandler) Option {
+	return func(o *Options) {
+		o.toolHandler = h
+	}
+}
+
+// WithResourceHandler registers h as the resource handler
+func WithResourceHandler(h handler.ResourceHandler) Option {
+	return func(o *Options) {
+		o.resourceHandler = h
+	}
+}
+
+// WithPromptHandler registers h as the prompt handler
+func WithPromptHandler(h handler.PromptHandler) Option {
+	return func(o *Options) {
+		o.promptHandler = h
+	}
+}
+
+// WithCapabilities sets the optional capability flags to advertise
+func WithCapabilities(caps *protocol.Capabilities) Option {
+	return func(o *Options) {
+		o.Capabilities = caps
+	}
+}
+
+// WithInitializeMeta sets the _meta returned during initialize
+func WithInitializeMeta(meta map[string]interface{}) Option {
+	return func(o *Options) {
+		o.InitializeMeta = meta
+	}
+}
+
+// WithInstructions sets the usage instructions returned during initialize
+func WithInstructions(instructions string) Option {
+	return func(o *Options) {
+		o.Instructions = instructions
+	}
+}
+
+// WithToolMiddleware appends middleware around tools/call
+func WithToolMiddleware(mws ...handler.ToolMiddleware) Option {
+	return func(o *Options) {
+		o.ToolMiddleware = append(o.ToolMiddleware, mws...)
+	}
+}
+
+// WithRequestTimeout sets the default handler deadline for all methods
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.RequestTimeout = timeout
+	}
+}
+
+// WithMethodTimeout sets the handler deadline for a single method
+func WithMethodTimeout(method string, timeout time.Duration) Option {
+	return func(o *Options) {
+		if o.MethodTimeouts == nil {
+			o.MethodTimeouts = make(map[string]time.Duration)
+		}
+		o.MethodTimeouts[method] = timeout
+	}
+}
+
+// WithRateLimit sets the shared rate limit for methods without their own
+func WithRateLimit(rate float64, burst int) Option {
+	return func(o *Options) {
+		o.RateLimit = RateLimit{Rate: rate, Burst: burst}
+	}
+}
+
+// WithCanonicalJSON makes results go out as canonical JSON
+func WithCanonicalJSON() Option {
+	return func(o *Options) {
+		o.CanonicalJSON = true
+	}
+}
+
+// WithClock sets the server's time source
+func WithClock(clock Clock) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+// WithRateLimitKey limits each client key separately
+func WithRateLimitKey(key func(ctx context.Context) string) Option {
+	return func(o *Options) {
+		o.RateLimitKey = key
+	}
+}
+
+// WithMaxConcurrentRequests caps concurrent requests, rejecting new ones
+// once maxQueue are already waiting (0 queues without bound)
+func WithMaxConcurrentRequests(max, maxQueue int) Option {
+	return func(o *Options) {
+		o.MaxConcurrentRequests = max
+		o.MaxQueueDepth = maxQueue
+	}
+}
+
+// WithMethodRateLimit sets the rate limit for a single method
+func WithMethodRateLimit(method string, rate float64, burst int) Option {
+	return func(o *Options) {
+		if o.MethodRateLimits == nil {
+			o.MethodRateLimits = make(map[string]RateLimit)
+		}
+		o.MethodRateLimits[method] = RateLimit{Rate: rate, Burst: burst}
+	}
+}
+
+// WithIDValidator sets the request id validation hook
+func WithIDValidator(validate func(id interface{}) error) Option {
+	return func(o *Options) {
+		o.ValidateID = validate
+	}
+}
+
+// WithRequestValidator sets the pre-dispatch request validation hook
+func WithRequestValidator(validate func(*protocol.Request) *protocol.Error) Option {
+	return func(o *Options) {
+		o.RequestValidator = validate
+	}
+}
+
+// WithErrorMessageFunc sets the hook that chooses client-facing error messages
+func WithErrorMessageFunc(fn func(code int, err error) string) Option {
+	return func(o *Options) {
+		o.ErrorMessageFunc = fn
+	}
+}
+
+// WithRequestInterceptor sets the hook that may rewrite requests before dispatch
+func WithRequestInterceptor(intercept func(*protocol.Request)) Option {
+	return func(o *Options) {
+		o.RequestInterceptor = intercept
+	}
+}
+
+// WithResponseInterceptor sets the hook that may replace successful results
+func WithResponseInterceptor(intercept func(id interface{}, result interface{}) interface{}) Option {
+	return func(o *Options) {
+		o.ResponseInterceptor = intercept
+	}
+}
+
+// WithRedactKeys adds keys to mask in the request/response log
+func WithRedactKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.RedactKeys = append(o.RedactKeys, keys...)
+	}
+}
+
+// WithValidateOnlyDryRun answers dry-run tool calls by schema validation alone
+func WithValidateOnlyDryRun() Option {
+	return func(o *Options) {
+		o.ValidateOnlyDryRun = true
+	}
+}
+
+// WithRecoveryHandler sets the handler for panics raised by request handlers
+func WithRecoveryHandler(h RecoveryHandler) Option {
+	return func(o *Options) {
+		o.RecoveryHandler = h
+	}
+}
+
+// WithSendRetry retries failed response writes with exponential backoff
+func WithSendRetry(retries int, backoff time.Duration) Option {
+	return func(o *Options) {
+		o.SendRetries = retries
+		o.SendRetryBackoff = backoff
+	}
+}
+
+// WithSendErrorHandler sets the callback for responses that could not be sent
+func WithSendErrorHandler(h SendErrorHandler) Option {
+	return func(o *Options) {
+		o.SendErrorHandler = h
+	}
+}
+
+// WithMaxRequestAge sets the maximum time a request may wait before dispatch
+func WithMaxRequestAge(age time.Duration) Option {
+	return func(o *Options) {
+		o.MaxRequestAge = age
+	}
+}
+
+// WithMaxResultContentBytes truncates tool result content beyond n bytes
+func WithMaxResultContentBytes(n int) Option {
+	return func(o *Options) {
+		o.MaxResultContentBytes = n
+	}
+}
+
+// WithMaxResponseBytes sets the largest result, in marshaled bytes, the server sends
+func WithMaxResponseBytes(n int) Option {
+	return func(o *Options) {
+		o.MaxResponseBytes = n
+	}
+}
+
+// WithResourceURINormalization validates and normalizes resource URIs before dispatch
+func WithResourceURINormalization() Option {
+	return func(o *Options) {
+		o.NormalizeResourceURIs = true
+	}
+}
+
+// WithStrictCapabilities rejects list requests for capabilities with no handler
+func WithStrictCapabilities(strict bool) Option {
+	return func(o *Options) {
+		o.StrictCapabilities = strict
+	}
+}
+
+// WithStrictDecoding rejects request params with unknown fields
+func WithStrictDecoding() Option {
+	return func(o *Options) {
+		o.StrictDecoding = true
+	}
+}
+
+// WithRequireInitialize rejects requests that arrive before the handshake
+func WithRequireInitialize() Option {
+	return func(o *Options) {
+		o.RequireInitialize = true
+	}
+}
+
+// WithNotificationIDPolicy sets how notifications sent with an id are answered
+func WithNotificationIDPolicy(policy NotificationIDPolicy) Option {
+	return func(o *Options) {
+		o.NotificationIDPolicy = policy
+	}
+}
+
+// WithIntrospection enables or disables the x-mcp/describe method
+func WithIntrospection(enabled bool) Option {
+	return func(o *Options) {
+		o.Introspection = enabled
+	}
+}
+
+// WithShutdownMethod sets the method that shuts the server down
+func WithShutdownMethod(method string) Option {
+	return func(o *Options) {
+		o.ShutdownMethod = method
+	}
+}
+
+// WithResourceArguments resolves resource references in tools/call arguments
+func WithResourceArguments() Option {
+	return func(o *Options) {
+		o.ResolveResourceArguments = true
+	}
+}
+
+// WithArgumentDefaults injects InputSchema defaults into tools/call arguments
+func WithArgumentDefaults() Option {
+	return func(o *Options) {
+		o.ApplyArgumentDefaults = true
+	}
+}
+
+// WithOutputValidation checks tools/call structured output against the
+// tool's OutputSchema
+func WithOutputValidation() Option {
+	return func(o *Options) {
+		o.ValidateToolOutput = true
+	}
+}
+
+// WithLogLevel sets the threshold for the server's request log
+func WithLogLevel(level string) Option {
+	return func(o *Options) {
+		o.LogLevel = level
+	}
+}
+
 // DefaultOptions returns the default server options
 func DefaultOptions() Options {
 	return Options{
@@ -57,5 +588,15 @@ func DefaultOptions() Options {
 		Version:   "1.0.0",
 		Transport: transport.NewStdioTransport(),
 		Registry:  handler.NewHandlerRegistry(),
+		LogLevel:  protocol.LogLevelInfo,
+		Clock:     SystemClock{},
+	}
+}
+
+// WithAsyncExecutor exposes executor's operations to clients through the
+// built-in continue_operation, cancel_operation and list_operations tools
+func WithAsyncExecutor(executor *async.OperationExecutor) Option {
+	return func(o *Options) {
+		o.AsyncExecutor = executor
 	}
 }
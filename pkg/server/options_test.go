@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/gomcpgo/mcp/pkg/handler"
@@ -122,3 +124,59 @@ func TestServerOptionsValidation(t *testing.T) {
 		t.Error("options should not affect each other")
 	}
 }
+
+func TestHandlerOptionsRegisterHandlers(t *testing.T) {
+	tool := &mockToolHandler{}
+	resource := &mockResourceHandler{}
+	prompt := &mockPromptHandler{}
+
+	// WithRegistry applied after the handler options must still receive
+	// them: handlers merge into whatever registry is set.
+	registry := handler.NewHandlerRegistry()
+	var opts Options
+	for _, o := range []Option{
+		WithToolHandler(tool),
+		WithResourceHandler(resource),
+		WithPromptHandler(prompt),
+		WithRegistry(registry),
+		WithTransport(newMockTransport()),
+	} {
+		o(&opts)
+	}
+
+	srv := New(opts)
+	if srv.registry != registry {
+		t.Fatal("server should use the provided registry")
+	}
+	if registry.GetToolHandler() != tool {
+		t.Error("WithToolHandler did not register the tool handler")
+	}
+	if registry.GetResourceHandler() != resource {
+		t.Error("WithResourceHandler did not register the resource handler")
+	}
+	if registry.GetPromptHandler() != prompt {
+		t.Error("WithPromptHandler did not register the prompt handler")
+	}
+
+	caps, err := srv.handleInitialize(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("handleInitialize: %v", err)
+	}
+	if caps.Capabilities.Tools == nil || caps.Capabilities.Resources == nil || caps.Capabilities.Prompts == nil {
+		t.Errorf("capabilities = %+v, want tools, resources and prompts advertised", caps.Capabilities)
+	}
+}
+
+func TestHandlerOptionsWithoutRegistry(t *testing.T) {
+	var opts Options
+	WithToolHandler(&mockToolHandler{})(&opts)
+	WithTransport(newMockTransport())(&opts)
+
+	srv := New(opts)
+	if !srv.registry.HasToolHandler() {
+		t.Error("WithToolHandler should populate the default registry")
+	}
+	if srv.registry.HasResourceHandler() || srv.registry.HasPromptHandler() {
+		t.Error("only the tool handler should be registered")
+	}
+}
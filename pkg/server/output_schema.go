@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// validateToolOutput checks resp's StructuredContent against the OutputSchema
+// of the tool req called. Error results and tools without an OutputSchema
+// pass unchecked. The violation is only logged: it describes a handler bug,
+// not something the client can act on.
+func validateToolOutput(ctx context.Context, h handler.ToolHandler, req *protocol.CallToolRequest, resp *protocol.CallToolResponse) error {
+	if resp == nil || resp.IsError {
+		return nil
+	}
+	tool, err := findTool(ctx, h, req.Name)
+	if err != nil || tool == nil || len(tool.OutputSchema) == 0 {
+		return err
+	}
+
+	if resp.StructuredContent == nil {
+		log.Printf("Tool %s declares an output schema but returned no structured content", req.Name)
+	} else if content, err := asJSONObject(resp.StructuredContent); err != nil {
+		log.Printf("Tool %s returned structured content that cannot be encoded: %v", req.Name, err)
+	} else if err := tool.ValidateStructuredContent(content); err != nil {
+		log.Printf("Tool %s returned structured content that violates its output schema: %v", req.Name, err)
+	} else {
+		return nil
+	}
+	return &protocol.Error{
+		Code:    protocol.InternalError,
+		Message: fmt.Sprintf("tool %s returned output that does not match its output schema", req.Name),
+	}
+}
+
+// asJSONObject returns content as the client will decode it, so that Go
+// values such as ints and typed slices are validated as JSON numbers and
+// arrays
+func asJSONObject(content map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestToolOutputValidation(t *testing.T) {
+	var output *protocol.CallToolResponse
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{
+		Name:        "weather",
+		InputSchema: json.RawMessage(`{"type":"object"}`),
+		OutputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {"temperature": {"type": "number"}},
+			"required": ["temperature"]
+		}`),
+	}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		return output, nil
+	})
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		output  *protocol.CallToolResponse
+		wantErr bool
+	}{
+		{
+			name:   "matching",
+			opts:   []Option{WithOutputValidation()},
+			output: &protocol.CallToolResponse{StructuredContent: map[string]interface{}{"temperature": 21.5}},
+		},
+		{
+			name:    "wrong type",
+			opts:    []Option{WithOutputValidation()},
+			output:  &protocol.CallToolResponse{StructuredContent: map[string]interface{}{"temperature": "warm"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing required",
+			opts:    []Option{WithOutputValidation()},
+			output:  &protocol.CallToolResponse{StructuredContent: map[string]interface{}{}},
+			wantErr: true,
+		},
+		{
+			name:    "no structured content",
+			opts:    []Option{WithOutputValidation()},
+			output:  &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: "21.5"}}},
+			wantErr: true,
+		},
+		{
+			name:   "error result",
+			opts:   []Option{WithOutputValidation()},
+			output: protocol.NewToolError("unavailable", "no data"),
+		},
+		{
+			name:   "disabled",
+			output: &protocol.CallToolResponse{StructuredContent: map[string]interface{}{"temperature": "warm"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output = tt.output
+			mockTransport := newMockTransport()
+			srv := Builder().Transport(mockTransport).Tool(router).With(tt.opts...).Build()
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+				Params: json.RawMessage(`{"name":"weather","arguments":{}}`),
+			}, time.Now())
+
+			resp := mockTransport.responseAt(0)
+			if !tt.wantErr {
+				if resp.Error != nil {
+					t.Fatalf("unexpected error %+v", resp.Error)
+				}
+				return
+			}
+			if resp.Error == nil || resp.Error.Code != protocol.InternalError {
+				t.Fatalf("error = %+v, want InternalError", resp.Error)
+			}
+			if resp.Error.Message != "tool weather returned output that does not match its output schema" {
+				t.Errorf("message = %q, want the generic schema mismatch message", resp.Error.Message)
+			}
+		})
+	}
+}
+
+func TestToolOutputValidationUsesJSONTypes(t *testing.T) {
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{
+		Name:        "stats",
+		InputSchema: json.RawMessage(`{"type":"object"}`),
+		OutputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"count": {"type": "integer"},
+				"readings": {"type": "array", "items": {"type": "number"}},
+				"labels": {"type": "array", "items": {"type": "string"}}
+			},
+			"required": ["count", "readings", "labels"]
+		}`),
+	}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		return &protocol.CallToolResponse{StructuredContent: map[string]interface{}{
+			"count":    3,
+			"readings": []float64{1.5, 2, 3.25},
+			"labels":   []string{"a", "b", "c"},
+		}}, nil
+	})
+
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).Tool(router).With(WithOutputValidation()).Build()
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+		Params: json.RawMessage(`{"name":"stats","arguments":{}}`),
+	}, time.Now())
+
+	if resp := mockTransport.responseAt(0); resp.Error != nil {
+		t.Errorf("Go ints and typed slices should validate as JSON numbers and arrays, got %+v", resp.Error)
+	}
+}
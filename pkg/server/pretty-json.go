@@ -23,13 +23,15 @@ func truncatedJSON(v interface{}) string {
 }
 
 // PrettyJSON takes any value and returns a formatted JSON string representation.
-// If the input cannot be marshaled to JSON, it returns an error.
+// If the input cannot be marshaled to JSON (it holds a channel or func, say),
+// it logs the error and falls back to the value's %+v formatting so the
+// output is still readable.
 func PrettyJSON(v interface{}) string {
 	// First marshal the object to JSON
 	jsonBytes, err := json.Marshal(v)
 	if err != nil {
 		log.Printf("failed to marshal to JSON: %v", err)
-		return ""
+		return fmt.Sprintf("%+v", v)
 	}
 
 	// Create a buffer for pretty printing
@@ -39,7 +41,7 @@ func PrettyJSON(v interface{}) string {
 	err = json.Indent(&prettyJSON, jsonBytes, "", "    ")
 	if err != nil {
 		log.Printf("failed to indent JSON: %v", err)
-		return ""
+		return string(jsonBytes)
 	}
 
 	return prettyJSON.String()
@@ -26,3 +26,26 @@ func TestTruncatedJSONLeavesSmallPayloadsUnchanged(t *testing.T) {
 		t.Errorf("small payload was altered: got %q, want %q", got, want)
 	}
 }
+
+func TestPrettyJSON(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	if got, want := PrettyJSON(point{1, 2}), "{\n    \"x\": 1,\n    \"y\": 2\n}"; got != want {
+		t.Errorf("PrettyJSON(struct) = %q, want %q", got, want)
+	}
+
+	if got := PrettyJSON(nil); got != "null" {
+		t.Errorf("PrettyJSON(nil) = %q, want null", got)
+	}
+
+	withChan := struct {
+		Name string
+		Ch   chan int
+	}{Name: "stream", Ch: make(chan int)}
+	got := PrettyJSON(withChan)
+	if !strings.Contains(got, "Name:stream") || !strings.Contains(got, "Ch:0x") {
+		t.Errorf("PrettyJSON(unmarshalable) = %q, want a %%+v fallback", got)
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
@@ -24,6 +25,33 @@ func (r *transportProgressReporter) Report(progress float64, total *float64, mes
 	})
 }
 
+// progressTokenKey is the context key for the request's `_meta.progressToken`.
+type progressTokenKey struct{}
+
+// ProgressToken returns the progress token the client attached to the
+// request being handled, if any.
+func ProgressToken(ctx context.Context) (interface{}, bool) {
+	token := ctx.Value(progressTokenKey{})
+	return token, token != nil
+}
+
+// Progress emits notifications/progress for the request being handled,
+// using the progress token from ctx. total may be nil when the amount of
+// work is unknown. If the client did not ask for progress (no token), it is
+// a no-op and returns nil.
+func (s *Server) Progress(ctx context.Context, progress float64, total *float64, message string) error {
+	token, ok := ProgressToken(ctx)
+	if !ok {
+		return nil
+	}
+	return s.notify(ctx, protocol.NotificationProgress, protocol.ProgressParams{
+		ProgressToken: token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+}
+
 // extractProgressToken returns the `_meta.progressToken` value from a
 // request's params, or nil if absent. It tolerates malformed `_meta` silently
 // so a bad metadata block never fails an otherwise-valid request.
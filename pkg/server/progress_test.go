@@ -183,3 +183,75 @@ func mustProgressParams(t *testing.T, raw json.RawMessage) protocol.ProgressPara
 	}
 	return p
 }
+
+// serverProgressToolHandler reports progress through Server.Progress.
+type serverProgressToolHandler struct {
+	srv *Server
+}
+
+func (h *serverProgressToolHandler) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	return &protocol.ListToolsResponse{Tools: []protocol.Tool{}}, nil
+}
+
+func (h *serverProgressToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	total := 4.0
+	if err := h.srv.Progress(ctx, 1, &total, "step 1 of 4"); err != nil {
+		return nil, err
+	}
+	return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: "done"}}}, nil
+}
+
+func TestServerProgress(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		token     interface{}
+		wantNotif bool
+	}{
+		{"with token", "tok-1", true},
+		{"without token", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			transp := newMockTransport()
+			tool := &serverProgressToolHandler{}
+			registry := handler.NewHandlerRegistry()
+			registry.RegisterToolHandler(tool)
+			srv := New(Options{
+				Name:      "progress-test-server",
+				Version:   "1.0.0",
+				Registry:  registry,
+				Transport: transp,
+			})
+			tool.srv = srv
+
+			params := map[string]interface{}{"name": "t", "arguments": map[string]interface{}{}}
+			if tt.token != nil {
+				params["_meta"] = map[string]interface{}{"progressToken": tt.token}
+			}
+			paramsJSON, _ := json.Marshal(params)
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0",
+				ID:      1,
+				Method:  protocol.MethodToolsCall,
+				Params:  paramsJSON,
+			}, time.Now())
+
+			if resp := transp.responseAt(0); resp.Error != nil {
+				t.Fatalf("tool call failed: %v", resp.Error)
+			}
+			notifs := drainNotifications(transp)
+			if !tt.wantNotif {
+				if len(notifs) != 0 {
+					t.Fatalf("expected no notifications, got %d", len(notifs))
+				}
+				return
+			}
+			if len(notifs) != 1 || notifs[0].Method != protocol.NotificationProgress {
+				t.Fatalf("expected one progress notification, got %+v", notifs)
+			}
+			p := mustProgressParams(t, notifs[0].Params)
+			if p.ProgressToken != "tok-1" || p.Progress != 1 || p.Total == nil || *p.Total != 4 || p.Message != "step 1 of 4" {
+				t.Errorf("unexpected progress params %+v", p)
+			}
+		})
+	}
+}
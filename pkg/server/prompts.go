@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// AddPrompt registers prompt, rendered by get, while the server is running
+// and sends notifications/prompts/list_changed so the client refreshes its
+// list. Adding an existing name replaces it. Prompts are added to the
+// server's handler.PromptRouter, for which prompts.listChanged is advertised
+// during initialize. Without a prompt handler one is installed, but only
+// before initialize: a client told there are no prompts would never look.
+// With any other prompt handler AddPrompt fails with
+// handler.ErrPromptHandlerNotRouter.
+func (s *Server) AddPrompt(prompt protocol.Prompt, get handler.PromptFunc) error {
+	if !s.registry.HasPromptHandler() && len(s.initializedClients()) > 0 {
+		return fmt.Errorf("cannot add prompt %q: prompts were not advertised at initialize", prompt.Name)
+	}
+	if err := s.registry.RegisterPrompt(prompt, get); err != nil {
+		return fmt.Errorf("cannot add prompt %q: %w", prompt.Name, err)
+	}
+	return s.notifyPromptsChanged()
+}
+
+// RemovePrompt removes a prompt added with AddPrompt and, if it existed,
+// sends notifications/prompts/list_changed.
+func (s *Server) RemovePrompt(name string) error {
+	if !s.registry.UnregisterPrompt(name) {
+		return nil
+	}
+	return s.notifyPromptsChanged()
+}
+
+// notifyPromptsChanged tells every initialized client that accepts
+// notifications that the prompt list changed. It returns the first error.
+func (s *Server) notifyPromptsChanged() error {
+	var firstErr error
+	for sessionID, caps := range s.initializedClients() {
+		if !caps.AcceptsNotifications() {
+			continue
+		}
+		var err error
+		if sessionID == "" {
+			err = s.SendNotification(protocol.NotificationPromptsListChanged, nil)
+		} else {
+			err = s.SendNotificationTo(sessionID, protocol.NotificationPromptsListChanged, nil)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
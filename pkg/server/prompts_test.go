@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestAddPromptNotifiesAndLists(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).Build()
+
+	// Before initialize there is no client to tell
+	if err := srv.AddPrompt(protocol.Prompt{Name: "greet"}, nil); err != nil {
+		t.Fatalf("AddPrompt: %v", err)
+	}
+	if got := mockTransport.notificationCount(); got != 0 {
+		t.Errorf("got %d notifications before initialize, want 0", got)
+	}
+
+	init, err := srv.handleInitialize(context.Background(), json.RawMessage(`{"capabilities":{}}`))
+	if err != nil {
+		t.Fatalf("handleInitialize: %v", err)
+	}
+	if init.Capabilities.Prompts == nil || !init.Capabilities.Prompts.ListChanged {
+		t.Errorf("prompts capability = %+v, want listChanged", init.Capabilities.Prompts)
+	}
+
+	if err := srv.AddPrompt(protocol.Prompt{Name: "summarize", Description: "Summarize text"}, nil); err != nil {
+		t.Fatalf("AddPrompt: %v", err)
+	}
+	if got := mockTransport.notificationCount(); got != 1 {
+		t.Fatalf("got %d notifications after AddPrompt, want 1", got)
+	}
+	mockTransport.mu.Lock()
+	method := mockTransport.notifications[0].Method
+	mockTransport.mu.Unlock()
+	if method != protocol.NotificationPromptsListChanged {
+		t.Errorf("method = %q, want %q", method, protocol.NotificationPromptsListChanged)
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: 1, Method: protocol.MethodPromptsList}, time.Now())
+	list, ok := mockTransport.responseAt(0).Result.(*protocol.ListPromptsResponse)
+	if !ok || len(list.Prompts) != 2 || list.Prompts[1].Name != "summarize" {
+		t.Fatalf("prompts/list = %+v, want greet and summarize", mockTransport.responseAt(0).Result)
+	}
+
+	if err := srv.RemovePrompt("greet"); err != nil {
+		t.Fatalf("RemovePrompt: %v", err)
+	}
+	if err := srv.RemovePrompt("greet"); err != nil {
+		t.Fatalf("RemovePrompt: %v", err)
+	}
+	if got := mockTransport.notificationCount(); got != 2 {
+		t.Errorf("got %d notifications, want one more for the removal only", got)
+	}
+}
+
+func TestAddPromptRefusedWhenUnannounced(t *testing.T) {
+	// A prompt handler other than a PromptRouter cannot take prompts
+	custom := &mockPromptHandler{}
+	srv := Builder().Transport(newMockTransport()).Prompt(custom).Build()
+	if err := srv.AddPrompt(protocol.Prompt{Name: "greet"}, nil); !errors.Is(err, handler.ErrPromptHandlerNotRouter) {
+		t.Errorf("err = %v, want ErrPromptHandlerNotRouter", err)
+	}
+	if srv.registry.GetPromptHandler() != custom {
+		t.Error("AddPrompt must not replace the prompt handler")
+	}
+
+	// Without a prompt handler, a client initialized without the prompts
+	// capability would never see the prompt
+	srv = Builder().Transport(newMockTransport()).Build()
+	if _, err := srv.handleInitialize(context.Background(), json.RawMessage(`{"capabilities":{}}`)); err != nil {
+		t.Fatalf("handleInitialize: %v", err)
+	}
+	if err := srv.AddPrompt(protocol.Prompt{Name: "greet"}, nil); err == nil {
+		t.Error("AddPrompt after initialize without a prompt handler should fail")
+	}
+	if srv.registry.HasPromptHandler() {
+		t.Error("a refused AddPrompt must not install a prompt handler")
+	}
+}
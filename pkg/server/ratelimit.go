@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// RateLimit configures a token bucket: requests are admitted at Rate per
+// second on average, with bursts of up to Burst. The zero value means no
+// limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l RateLimit) enabled() bool {
+	return l.Rate > 0
+}
+
+// tokenBucket is a mutex-guarded token bucket refilled lazily on each take.
+type tokenBucket struct {
+	mu     sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+}
+
+// take consumes a token if one is available. Otherwise it reports how long
+// until the next token is due.
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := (1 - b.tokens) / b.limit.Rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// rateLimiter holds one bucket per limited method plus a shared bucket for
+// every method without its own limit. With a key function, each distinct
+// key (e.g. client identity) gets its own set of buckets, created on first
+// use.
+type rateLimiter struct {
+	global  RateLimit
+	methods map[string]RateLimit
+	exempt  map[string]bool
+
+	clock   Clock
+	mu      sync.Mutex
+	buckets map[bucketKey]*tokenBucket
+}
+
+// bucketKey identifies a bucket: method is empty for the shared bucket.
+type bucketKey struct {
+	client string
+	method string
+}
+
+func newRateLimiter(global RateLimit, perMethod map[string]RateLimit, clock Clock) *rateLimiter {
+	if !global.enabled() && len(perMethod) == 0 {
+		return nil
+	}
+	l := &rateLimiter{
+		global:  global,
+		methods: make(map[string]RateLimit),
+		exempt:  make(map[string]bool),
+		clock:   clock,
+		buckets: make(map[bucketKey]*tokenBucket),
+	}
+	for method, limit := range perMethod {
+		if limit.enabled() {
+			l.methods[method] = limit
+		} else {
+			l.exempt[method] = true
+		}
+	}
+	return l
+}
+
+// allow takes a token for method from client's buckets, reporting the wait
+// on rejection.
+func (l *rateLimiter) allow(client, method string) (bool, time.Duration) {
+	if l == nil || l.exempt[method] {
+		return true, 0
+	}
+	key := bucketKey{client: client, method: method}
+	limit, ok := l.methods[method]
+	if !ok {
+		if !l.global.enabled() {
+			return true, 0
+		}
+		key.method, limit = "", l.global
+	}
+
+	now := l.clock.Now()
+	l.mu.Lock()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(limit)
+		bucket.last = now
+		l.buckets[key] = bucket
+	}
+	l.mu.Unlock()
+	return bucket.take(now)
+}
+
+// rateLimitedError builds the error sent when a request is over its limit.
+// Data carries retryAfter in seconds.
+func rateLimitedError(method string, retryAfter time.Duration) *protocol.Error {
+	return &protocol.Error{
+		Code:    protocol.RateLimited,
+		Message: "rate limit exceeded for " + method,
+		Data: map[string]interface{}{
+			"retryAfter": math.Ceil(retryAfter.Seconds()*1000) / 1000,
+		},
+	}
+}
+
+// RateLimitByIdentity is a RateLimitKey that gives every authenticated
+// caller (see transport.Authenticator) its own buckets. Unauthenticated
+// requests share one set.
+func RateLimitByIdentity(ctx context.Context) string {
+	identity, ok := transport.IdentityFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(identity)
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+func TestMethodRateLimitRejectsWithRetryHint(t *testing.T) {
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(&mockToolHandler{})
+	transp := newMockTransport()
+	srv := New(Options{
+		Registry:  registry,
+		Transport: transp,
+		MethodRateLimits: map[string]RateLimit{
+			protocol.MethodToolsCall: {Rate: 1, Burst: 2},
+		},
+	})
+
+	for i := 0; i < 4; i++ {
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0",
+			ID:      i,
+			Method:  protocol.MethodToolsCall,
+			Params:  json.RawMessage(`{"name":"test-tool"}`),
+		}, time.Now())
+	}
+	// Other methods are not limited.
+	srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: 99, Method: protocol.MethodPing}, time.Now())
+
+	responses := transp.responsesSnapshot()
+	if len(responses) != 5 {
+		t.Fatalf("responses = %d, want 5", len(responses))
+	}
+	for i, resp := range responses[:2] {
+		if resp.Error != nil {
+			t.Errorf("call %d within burst was rejected: %v", i, resp.Error)
+		}
+	}
+	for i, resp := range responses[2:4] {
+		if resp.Error == nil || resp.Error.Code != protocol.RateLimited {
+			t.Fatalf("call %d over the limit: got %+v, want RateLimited", i+2, resp)
+		}
+		data, ok := resp.Error.Data.(map[string]interface{})
+		if !ok {
+			t.Fatalf("error data = %T, want map", resp.Error.Data)
+		}
+		retryAfter, _ := data["retryAfter"].(float64)
+		if retryAfter <= 0 || retryAfter > 1 {
+			t.Errorf("retryAfter = %v, want within (0, 1] seconds at 1 req/s", data["retryAfter"])
+		}
+	}
+	if responses[4].Error != nil {
+		t.Errorf("unlimited method was rejected: %v", responses[4].Error)
+	}
+}
+
+func TestGlobalRateLimitWithExemptMethod(t *testing.T) {
+	transp := newMockTransport()
+	srv := New(Options{
+		Registry:         handler.NewHandlerRegistry(),
+		Transport:        transp,
+		RateLimit:        RateLimit{Rate: 1, Burst: 1},
+		MethodRateLimits: map[string]RateLimit{protocol.MethodPing: {}},
+	})
+
+	for i := 0; i < 3; i++ {
+		srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: i, Method: protocol.MethodToolsList}, time.Now())
+		srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: 100 + i, Method: protocol.MethodPing}, time.Now())
+	}
+
+	rejected := 0
+	for _, resp := range transp.responsesSnapshot() {
+		if resp.Error != nil && resp.Error.Code == protocol.RateLimited {
+			if resp.ID.(int) >= 100 {
+				t.Errorf("exempt ping %v was rate limited", resp.ID)
+			}
+			rejected++
+		}
+	}
+	if rejected != 2 {
+		t.Errorf("rejected = %d, want 2 of 3 tools/list calls", rejected)
+	}
+}
+
+func TestTokenBucketRefills(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 10, Burst: 1})
+	now := time.Now()
+
+	if ok, _ := b.take(now); !ok {
+		t.Fatal("first take should succeed")
+	}
+	ok, wait := b.take(now)
+	if ok {
+		t.Fatal("second immediate take should fail")
+	}
+	if wait <= 0 || wait > 100*time.Millisecond {
+		t.Errorf("wait = %v, want about 100ms at 10 req/s", wait)
+	}
+	if ok, _ := b.take(now.Add(100 * time.Millisecond)); !ok {
+		t.Error("take after refill interval should succeed")
+	}
+}
+
+func TestRateLimitPerClientKeyRecovers(t *testing.T) {
+	transp := newMockTransport()
+	srv := Builder().
+		Transport(transp).
+		With(
+			WithMethodRateLimit(protocol.MethodToolsList, 20, 1),
+			WithRateLimitKey(RateLimitByIdentity),
+		).
+		Build()
+
+	list := func(identity string, id int) *protocol.Response {
+		req := &protocol.Request{JSONRPC: "2.0", ID: id, Method: protocol.MethodToolsList}
+		req = req.WithContext(transport.WithIdentity(context.Background(), identity))
+		srv.handleRequest(context.Background(), req, time.Now())
+		responses := transp.responsesSnapshot()
+		return responses[len(responses)-1]
+	}
+	limited := func(resp *protocol.Response) bool {
+		return resp.Error != nil && resp.Error.Code == protocol.RateLimited
+	}
+
+	if resp := list("alice", 1); limited(resp) {
+		t.Fatal("alice's first call was rate limited")
+	}
+	if resp := list("alice", 2); !limited(resp) {
+		t.Fatalf("alice's call beyond the burst = %+v, want RateLimited", resp)
+	}
+	// Each identity has its own bucket
+	if resp := list("bob", 3); limited(resp) {
+		t.Error("bob was limited by alice's calls")
+	}
+
+	// At 20 req/s a token is back after 50ms
+	time.Sleep(60 * time.Millisecond)
+	if resp := list("alice", 4); limited(resp) {
+		t.Error("alice was still limited after the refill interval")
+	}
+}
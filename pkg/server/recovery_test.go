@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+type errQuotaExceeded struct{}
+
+// panickingToolHandler panics with the configured value on every call.
+type panickingToolHandler struct {
+	value interface{}
+}
+
+func (h *panickingToolHandler) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	return &protocol.ListToolsResponse{}, nil
+}
+
+func (h *panickingToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	panic(h.value)
+}
+
+func callPanickingTool(t *testing.T, opts Options, value interface{}) *protocol.Response {
+	t.Helper()
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(&panickingToolHandler{value: value})
+	transp := newMockTransport()
+	opts.Registry = registry
+	opts.Transport = transp
+	srv := New(opts)
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"boom"}`),
+	}, time.Now())
+
+	if transp.responseCount() != 1 {
+		t.Fatalf("responses = %d, want 1", transp.responseCount())
+	}
+	return transp.responseAt(0)
+}
+
+func TestPanicDefaultRecovery(t *testing.T) {
+	resp := callPanickingTool(t, Options{}, "kaboom")
+	if resp.Error == nil || resp.Error.Code != protocol.InternalError {
+		t.Fatalf("error = %+v, want InternalError", resp.Error)
+	}
+	if resp.Error.Message == "kaboom" {
+		t.Error("default recovery should not leak the panic value")
+	}
+}
+
+func TestPanicCustomRecoveryHandler(t *testing.T) {
+	const quotaCode = -32001
+	opts := Options{
+		RecoveryHandler: func(ctx context.Context, req *protocol.Request, recovered interface{}) *protocol.Error {
+			if _, ok := recovered.(errQuotaExceeded); ok {
+				return &protocol.Error{Code: quotaCode, Message: "quota exceeded"}
+			}
+			return nil
+		},
+	}
+
+	resp := callPanickingTool(t, opts, errQuotaExceeded{})
+	if resp.Error == nil || resp.Error.Code != quotaCode || resp.Error.Message != "quota exceeded" {
+		t.Errorf("error = %+v, want code %d 'quota exceeded'", resp.Error, quotaCode)
+	}
+
+	// Panics the custom handler declines fall back to the default.
+	resp = callPanickingTool(t, opts, "other")
+	if resp.Error == nil || resp.Error.Code != protocol.InternalError {
+		t.Errorf("error = %+v, want InternalError fallback", resp.Error)
+	}
+}
+
+func TestHandlerReturnedProtocolErrorKeepsCode(t *testing.T) {
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(&mockToolHandler{})
+	transp := newMockTransport()
+	srv := New(Options{Registry: registry, Transport: transp})
+	srv.options.ToolMiddleware = []handler.ToolMiddleware{
+		func(next handler.CallFunc) handler.CallFunc {
+			return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+				return nil, &protocol.Error{Code: protocol.InvalidParams, Message: "bad"}
+			}
+		},
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall, Params: json.RawMessage(`{"name":"x"}`),
+	}, time.Now())
+
+	if resp := transp.responseAt(0); resp.Error == nil || resp.Error.Code != protocol.InvalidParams {
+		t.Errorf("error = %+v, want InvalidParams", resp.Error)
+	}
+}
+
+// misbehavingToolHandler ignores its context: it waits for delay, then
+// panics if panicValue is set, and otherwise blocks until release closes.
+type misbehavingToolHandler struct {
+	delay      time.Duration
+	panicValue interface{}
+	release    chan struct{}
+}
+
+func (h *misbehavingToolHandler) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	return &protocol.ListToolsResponse{}, nil
+}
+
+func (h *misbehavingToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	time.Sleep(h.delay)
+	if h.panicValue != nil {
+		panic(h.panicValue)
+	}
+	<-h.release
+	return &protocol.CallToolResponse{}, nil
+}
+
+func callMisbehavingTool(t *testing.T, tool *misbehavingToolHandler, timeout time.Duration) *mockTransport {
+	t.Helper()
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(tool)
+	transp := newMockTransport()
+	srv := New(Options{Registry: registry, Transport: transp, RequestTimeout: timeout})
+
+	start := time.Now()
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"flaky"}`),
+	}, time.Now())
+	if elapsed := time.Since(start); elapsed > timeout+time.Second {
+		t.Fatalf("request took %v, should be bounded by the %v timeout", elapsed, timeout)
+	}
+	return transp
+}
+
+func assertSingleErrorResponse(t *testing.T, transp *mockTransport, wait time.Duration) *protocol.Error {
+	t.Helper()
+	// Give an abandoned handler time to finish and prove nothing else is sent.
+	time.Sleep(wait)
+	responses := transp.responsesSnapshot()
+	if len(responses) != 1 {
+		t.Fatalf("responses = %d, want exactly 1", len(responses))
+	}
+	if responses[0].ID != 1 || responses[0].Error == nil {
+		t.Fatalf("expected an error response for id 1, got %+v", responses[0])
+	}
+	return responses[0].Error
+}
+
+func TestPanicAfterDelayYieldsOneResponse(t *testing.T) {
+	tool := &misbehavingToolHandler{delay: 20 * time.Millisecond, panicValue: "late boom"}
+	transp := callMisbehavingTool(t, tool, time.Second)
+
+	rpcErr := assertSingleErrorResponse(t, transp, 50*time.Millisecond)
+	if rpcErr.Code != protocol.InternalError || rpcErr.Message != "internal error" {
+		t.Errorf("error = %+v, want the recovered panic error", rpcErr)
+	}
+}
+
+func TestPanicAfterTimeoutYieldsOnlyTimeout(t *testing.T) {
+	tool := &misbehavingToolHandler{delay: 100 * time.Millisecond, panicValue: "too late"}
+	transp := callMisbehavingTool(t, tool, 30*time.Millisecond)
+
+	rpcErr := assertSingleErrorResponse(t, transp, 150*time.Millisecond)
+	if rpcErr.Message != "request tools/call timed out" {
+		t.Errorf("error = %+v, want the timeout error", rpcErr)
+	}
+}
+
+func TestHangingHandlerTimesOut(t *testing.T) {
+	tool := &misbehavingToolHandler{release: make(chan struct{})}
+	transp := callMisbehavingTool(t, tool, 30*time.Millisecond)
+
+	rpcErr := assertSingleErrorResponse(t, transp, 0)
+	if rpcErr.Message != "request tools/call timed out" {
+		t.Errorf("error = %+v, want the timeout error", rpcErr)
+	}
+
+	// Letting the abandoned handler return must not produce a second response.
+	close(tool.release)
+	assertSingleErrorResponse(t, transp, 20*time.Millisecond)
+}
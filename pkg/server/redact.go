@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// redactedValue replaces the value of every redacted key in logged JSON.
+const redactedValue = "***"
+
+// redactJSON returns a copy of v, as generic JSON, with the value of every
+// object key matching one of keys (case-insensitively, at any depth)
+// replaced by "***". If keys is empty or v cannot be round-tripped through
+// JSON, v is returned unchanged and PrettyJSON reports the problem as usual.
+func redactJSON(v interface{}, keys []string) interface{} {
+	if len(keys) == 0 {
+		return v
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return v
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = struct{}{}
+	}
+	return redactValue(generic, set)
+}
+
+func redactValue(v interface{}, keys map[string]struct{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if _, ok := keys[strings.ToLower(k)]; ok {
+				val[k] = redactedValue
+				continue
+			}
+			val[k] = redactValue(child, keys)
+		}
+	case []interface{}:
+		for i, child := range val {
+			val[i] = redactValue(child, keys)
+		}
+	}
+	return v
+}
+
+// logJSON renders v for the request/response log: redacted per
+// Options.RedactKeys, then capped at maxLoggedJSONBytes.
+func (s *Server) logJSON(v interface{}) string {
+	return truncatedJSON(redactJSON(v, s.options.RedactKeys))
+}
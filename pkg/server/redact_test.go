@@ -0,0 +1,104 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestRedactedKeysMaskedInRequestLog(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(&mockToolHandler{result: &protocol.CallToolResponse{}})
+	srv := New(Options{
+		Name:       "redact-test-server",
+		Version:    "1.0.0",
+		Registry:   registry,
+		Transport:  newMockTransport(),
+		RedactKeys: []string{"api_key", "Password"},
+		LogLevel:   protocol.LogLevelDebug,
+	})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodToolsCall,
+		Params: json.RawMessage(`{"name":"deploy","arguments":{
+			"api_key":"sk-secret","nested":{"password":"hunter2"},"region":"eu-west-1"}}`),
+	}, time.Now())
+
+	out := buf.String()
+	for _, secret := range []string{"sk-secret", "hunter2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output leaked %q:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(out, `"***"`) {
+		t.Errorf("log output has no redaction marker:\n%s", out)
+	}
+	if !strings.Contains(out, "eu-west-1") {
+		t.Errorf("non-redacted field missing from log:\n%s", out)
+	}
+}
+
+func TestRedactJSONWithoutKeysIsIdentity(t *testing.T) {
+	v := map[string]string{"api_key": "x"}
+	if got := PrettyJSON(redactJSON(v, nil)); got != PrettyJSON(v) {
+		t.Errorf("redactJSON with no keys altered the value: %s", got)
+	}
+}
+
+func TestRequestLogOmitsBodiesAboveDebug(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(&mockToolHandler{result: &protocol.CallToolResponse{
+		Content: []protocol.ToolContent{{Type: "text", Text: "result-body"}},
+	}})
+	srv := New(Options{
+		Name:      "log-level-test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: newMockTransport(),
+	})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      7,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"deploy","arguments":{"region":"eu-west-1"}}`),
+	}, time.Now())
+
+	out := buf.String()
+	for _, body := range []string{"eu-west-1", "result-body"} {
+		if strings.Contains(out, body) {
+			t.Errorf("info-level log contains full JSON (%q):\n%s", body, out)
+		}
+	}
+	if !strings.Contains(out, "tools/call") || !strings.Contains(out, "id 7") {
+		t.Errorf("info-level log is missing the method/id summary:\n%s", out)
+	}
+
+	buf.Reset()
+	srv.options.LogLevel = protocol.LogLevelWarning
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 8, Method: protocol.MethodPing,
+	}, time.Now())
+	if buf.Len() != 0 {
+		t.Errorf("warning-level log recorded a routine request:\n%s", buf.String())
+	}
+}
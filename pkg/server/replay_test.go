@@ -0,0 +1,104 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+func echoTools() *handler.ToolRouter {
+	tools := handler.NewToolRouter()
+	tools.Register(protocol.Tool{Name: "echo"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		return &protocol.CallToolResponse{
+			Content: []protocol.ToolContent{{Type: "text", Text: req.GetString("text", "")}},
+		}, nil
+	})
+	return tools
+}
+
+func TestRecordAndReplaySession(t *testing.T) {
+	// Record a session
+	var recording bytes.Buffer
+	mock := newMockTransport()
+	srv := Builder().
+		Transport(transport.NewRecordingTransport(mock, &recording)).
+		Tool(echoTools()).
+		Build()
+	done := runServer(srv)
+
+	for _, raw := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-11-25","capabilities":{}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+	} {
+		var req protocol.Request
+		if err := json.Unmarshal([]byte(raw), &req); err != nil {
+			t.Fatalf("unmarshal %s: %v", raw, err)
+		}
+		want := mock.responseCount() + 1
+		mock.requests <- &req
+		if req.IsNotification() {
+			continue
+		}
+		deadline := time.Now().Add(time.Second)
+		for mock.responseCount() < want && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	mock.requests <- nil
+	<-done
+
+	var recorded []string
+	var inbound int
+	for _, line := range strings.Split(strings.TrimSpace(recording.String()), "\n") {
+		var entry transport.RecordedMessage
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("recording line %q: %v", line, err)
+		}
+		switch entry.Direction {
+		case transport.DirectionIn:
+			inbound++
+		case transport.DirectionOut:
+			recorded = append(recorded, string(entry.Message))
+		}
+	}
+	if inbound != 4 || len(recorded) != 3 {
+		t.Fatalf("recorded %d inbound and %d outbound messages, want 4 and 3", inbound, len(recorded))
+	}
+
+	// Replay it against a fresh server
+	replay, err := transport.Replay(strings.NewReader(recording.String()))
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	fresh := Builder().Transport(replay).Tool(echoTools()).Build()
+	done = runServer(fresh)
+	select {
+	case <-replay.Finished():
+	case <-time.After(2 * time.Second):
+		t.Fatal("replay did not finish")
+	}
+	<-done
+
+	sent := replay.Sent()
+	if len(sent) != len(recorded) {
+		t.Fatalf("replay produced %d responses, want %d", len(sent), len(recorded))
+	}
+	for i, resp := range sent {
+		got, _ := json.Marshal(resp)
+		if string(got) != recorded[i] {
+			t.Errorf("response %d:\n got %s\nwant %s", i, got, recorded[i])
+		}
+	}
+	if !fresh.IsReady() {
+		t.Error("replayed notifications/initialized did not reach the server")
+	}
+}
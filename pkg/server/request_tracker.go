@@ -10,11 +10,24 @@ import (
 // notifications/cancelled messages can cancel the matching handler's context
 // and so that handleRequest can suppress responses for cancelled requests.
 //
-// Keys are the request IDs normalized via fmt.Sprintf("%v", id). JSON-RPC
-// allows string or number IDs; this matches the client-side normalization.
+// Entries are keyed by the session the request came from and its ID,
+// normalized via fmt.Sprintf("%v", id). JSON-RPC allows string or number
+// IDs; this matches the client-side normalization. Each session of a
+// multi-client transport numbers its requests independently, so the same ID
+// may be in flight in several sessions at once; a single-peer transport uses
+// the empty session.
 type requestTracker struct {
 	mu      sync.Mutex
-	entries map[string]*trackerEntry
+	entries map[requestKey]*trackerEntry
+}
+
+type requestKey struct {
+	session string
+	id      string
+}
+
+func newRequestKey(session string, id interface{}) requestKey {
+	return requestKey{session: session, id: fmt.Sprintf("%v", id)}
 }
 
 type trackerEntry struct {
@@ -24,28 +37,28 @@ type trackerEntry struct {
 
 func newRequestTracker() *requestTracker {
 	return &requestTracker{
-		entries: make(map[string]*trackerEntry),
+		entries: make(map[requestKey]*trackerEntry),
 	}
 }
 
 // register derives a cancellable context from parent and stores the cancel
-// func against id so a later cancel(id) can fire it. Returns the ctx the
-// handler should use and the cancel func the caller must invoke on return
-// (via defer) so the tracker entry is cleaned up.
-func (t *requestTracker) register(parent context.Context, id interface{}) (context.Context, context.CancelFunc) {
+// func against id in session so a later cancel(session, id) can fire it.
+// Returns the ctx the handler should use and the cancel func the caller must
+// invoke on return (via defer) so the tracker entry is cleaned up.
+func (t *requestTracker) register(parent context.Context, session string, id interface{}) (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(parent)
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	t.entries[fmt.Sprintf("%v", id)] = &trackerEntry{cancel: cancel}
+	t.entries[newRequestKey(session, id)] = &trackerEntry{cancel: cancel}
 	return ctx, cancel
 }
 
-// cancel fires the cancel func for id if one is tracked and flags the entry
-// as cancelled so handleRequest can suppress the response when the handler
-// eventually returns. Returns true if a tracked entry was cancelled.
-func (t *requestTracker) cancel(id interface{}) bool {
+// cancel fires the cancel func for id in session if one is tracked and flags
+// the entry as cancelled so handleRequest can suppress the response when the
+// handler eventually returns. Returns true if a tracked entry was cancelled.
+func (t *requestTracker) cancel(session string, id interface{}) bool {
 	t.mu.Lock()
-	entry, ok := t.entries[fmt.Sprintf("%v", id)]
+	entry, ok := t.entries[newRequestKey(session, id)]
 	if !ok {
 		t.mu.Unlock()
 		return false
@@ -59,22 +72,23 @@ func (t *requestTracker) cancel(id interface{}) bool {
 	return true
 }
 
-// wasCancelled reports whether id was marked cancelled. Intended to be called
-// after the handler returns to decide if the response should be suppressed.
-func (t *requestTracker) wasCancelled(id interface{}) bool {
+// wasCancelled reports whether id in session was marked cancelled. Intended
+// to be called after the handler returns to decide if the response should be
+// suppressed.
+func (t *requestTracker) wasCancelled(session string, id interface{}) bool {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	entry, ok := t.entries[fmt.Sprintf("%v", id)]
+	entry, ok := t.entries[newRequestKey(session, id)]
 	if !ok {
 		return false
 	}
 	return entry.cancelled
 }
 
-// unregister removes id from the tracker. Called after the handler returns
-// so the map does not grow unbounded.
-func (t *requestTracker) unregister(id interface{}) {
+// unregister removes id in session from the tracker. Called after the
+// handler returns so the map does not grow unbounded.
+func (t *requestTracker) unregister(session string, id interface{}) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	delete(t.entries, fmt.Sprintf("%v", id))
+	delete(t.entries, newRequestKey(session, id))
 }
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// resolveResourceArguments replaces each top-level argument that references
+// a resource with the resource's content. References are normalized like
+// resources/read URIs when NormalizeResourceURIs is set. A reference that
+// cannot be read fails the call; a *protocol.Error from the resource handler
+// (e.g. ResourceNotFound) keeps its code. Text contents are joined, while a
+// binary resource must consist of a single blob.
+func (s *Server) resolveResourceArguments(ctx context.Context, req *protocol.CallToolRequest) error {
+	for name, value := range req.Arguments {
+		uri, ok := protocol.ResourceArgumentURI(value)
+		if !ok {
+			continue
+		}
+		uri, err := s.normalizeResourceURI(uri)
+		if err != nil {
+			return &protocol.Error{
+				Code:    protocol.InvalidParams,
+				Message: fmt.Sprintf("argument %q: %v", name, err),
+			}
+		}
+		h := s.registry.GetResourceHandler()
+		if h == nil {
+			return &protocol.Error{
+				Code:    protocol.InvalidParams,
+				Message: fmt.Sprintf("argument %q references resource %s, but resources are not supported", name, uri),
+			}
+		}
+		resp, err := h.ReadResource(ctx, &protocol.ReadResourceRequest{URI: uri})
+		if err != nil {
+			return fmt.Errorf("argument %q: read %s: %w", name, uri, err)
+		}
+		content, err := resourceArgumentContent(resp)
+		if err != nil {
+			return &protocol.Error{
+				Code:    protocol.InvalidParams,
+				Message: fmt.Sprintf("argument %q: resource %s %v", name, uri, err),
+			}
+		}
+		req.Arguments[name] = content
+	}
+	return nil
+}
+
+// resourceArgumentContent returns the value that replaces a reference to
+// the resource read as resp: its text, or its base64 blob when it is a
+// single binary content. Blobs cannot be joined, so any other mix of
+// contents including one is an error.
+func resourceArgumentContent(resp *protocol.ReadResourceResponse) (string, error) {
+	if resp == nil {
+		return "", nil
+	}
+	if len(resp.Contents) == 1 && resp.Contents[0].Text == "" {
+		return resp.Contents[0].Blob, nil
+	}
+	var content strings.Builder
+	for _, c := range resp.Contents {
+		if c.Blob != "" {
+			return "", fmt.Errorf("has %d contents including binary data, which cannot be passed as one argument", len(resp.Contents))
+		}
+		content.WriteString(c.Text)
+	}
+	return content.String(), nil
+}
+
+// normalizeResourceURI returns uri in canonical form when
+// NormalizeResourceURIs is set, or unchanged otherwise
+func (s *Server) normalizeResourceURI(uri string) (string, error) {
+	if !s.options.NormalizeResourceURIs {
+		return uri, nil
+	}
+	return protocol.NormalizeResourceURI(uri)
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestResourceArgumentsResolvedBeforeHandler(t *testing.T) {
+	resources := handler.NewHandlerRegistry()
+	resources.RegisterStaticResources([]handler.StaticResource{{
+		Resource: protocol.Resource{URI: "upload://doc-1", Name: "doc"},
+		Contents: []protocol.ResourceContent{{Text: "a very long "}, {Text: "document"}},
+	}})
+
+	tool := &recordingToolHandler{got: make(chan *protocol.CallToolRequest, 1)}
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(tool).
+		Resource(resources.GetResourceHandler()).
+		With(WithResourceArguments()).
+		Build()
+
+	params, _ := json.Marshal(protocol.CallToolRequest{
+		Name: "test_tool",
+		Arguments: map[string]interface{}{
+			"document": protocol.ResourceArgument("upload://doc-1"),
+			"style":    "brief",
+		},
+	})
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall, Params: params,
+	}, time.Now())
+
+	if resp := mockTransport.responseAt(0); resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	args := (<-tool.got).Arguments
+	if args["document"] != "a very long document" {
+		t.Errorf("document = %v, want the resource text", args["document"])
+	}
+	if args["style"] != "brief" {
+		t.Errorf("style = %v, want it untouched", args["style"])
+	}
+}
+
+func TestResourceArgumentsUnknownResource(t *testing.T) {
+	resources := handler.NewHandlerRegistry()
+	resources.RegisterStaticResources(nil)
+
+	tool := &recordingToolHandler{got: make(chan *protocol.CallToolRequest, 1)}
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(tool).
+		Resource(resources.GetResourceHandler()).
+		With(WithResourceArguments()).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+		Params: json.RawMessage(`{"name":"test_tool","arguments":{"document":{"$resource":"upload://missing"}}}`),
+	}, time.Now())
+
+	resp := mockTransport.responseAt(0)
+	if resp.Error == nil || resp.Error.Code != protocol.ResourceNotFound {
+		t.Fatalf("error = %v, want ResourceNotFound", resp.Error)
+	}
+	if len(tool.got) != 0 {
+		t.Error("handler should not run when a reference cannot be resolved")
+	}
+}
+
+// callWithResourceArgument calls test_tool with a document argument
+// referencing uri against resources and returns the response and the
+// arguments the tool saw, if it ran
+func callWithResourceArgument(t *testing.T, resources []handler.StaticResource, uri string, opts ...Option) (*protocol.Response, map[string]interface{}) {
+	t.Helper()
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterStaticResources(resources)
+
+	tool := &recordingToolHandler{got: make(chan *protocol.CallToolRequest, 1)}
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(tool).
+		Resource(registry.GetResourceHandler()).
+		With(append([]Option{WithResourceArguments()}, opts...)...).
+		Build()
+
+	params, _ := json.Marshal(protocol.CallToolRequest{
+		Name:      "test_tool",
+		Arguments: map[string]interface{}{"document": protocol.ResourceArgument(uri)},
+	})
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall, Params: params,
+	}, time.Now())
+
+	select {
+	case req := <-tool.got:
+		return mockTransport.responseAt(0), req.Arguments
+	default:
+		return mockTransport.responseAt(0), nil
+	}
+}
+
+func TestResourceArgumentsNormalized(t *testing.T) {
+	docs := []handler.StaticResource{{
+		Resource: protocol.Resource{URI: "file:///docs/a.txt", Name: "a"},
+		Contents: []protocol.ResourceContent{{Text: "alpha"}},
+	}}
+
+	resp, args := callWithResourceArgument(t, docs, "file:///docs/./b/../a.txt", WithResourceURINormalization())
+	if resp.Error != nil || args["document"] != "alpha" {
+		t.Errorf("normalized reference: error %v, document %v; want alpha", resp.Error, args["document"])
+	}
+
+	resp, args = callWithResourceArgument(t, docs, "file:///docs/../../etc/passwd", WithResourceURINormalization())
+	if resp.Error == nil || resp.Error.Code != protocol.InvalidParams || args != nil {
+		t.Errorf("traversing reference: error %v, args %v; want InvalidParams before the tool runs", resp.Error, args)
+	}
+}
+
+func TestResourceArgumentsBinary(t *testing.T) {
+	resources := []handler.StaticResource{
+		{
+			Resource: protocol.Resource{URI: "upload://image", Name: "image"},
+			Contents: []protocol.ResourceContent{{Blob: "aGVsbG8="}},
+		},
+		{
+			Resource: protocol.Resource{URI: "upload://images", Name: "images"},
+			Contents: []protocol.ResourceContent{{Blob: "aGVsbG8="}, {Blob: "d29ybGQ="}},
+		},
+	}
+
+	resp, args := callWithResourceArgument(t, resources, "upload://image")
+	if resp.Error != nil || args["document"] != "aGVsbG8=" {
+		t.Errorf("single blob: error %v, document %v; want the blob", resp.Error, args["document"])
+	}
+
+	resp, args = callWithResourceArgument(t, resources, "upload://images")
+	if resp.Error == nil || resp.Error.Code != protocol.InvalidParams || args != nil {
+		t.Errorf("several blobs: error %v, args %v; want InvalidParams before the tool runs", resp.Error, args)
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// previewResourceHandler serves a resource as either a raw PNG blob or a
+// rendered text preview, depending on what the client accepts.
+type previewResourceHandler struct {
+	mockResourceHandler
+}
+
+func (h *previewResourceHandler) ReadResource(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResponse, error) {
+	switch req.PreferredMimeType("image/png", "text/plain") {
+	case "image/png":
+		return &protocol.ReadResourceResponse{Contents: []protocol.ResourceContent{
+			{URI: req.URI, MimeType: "image/png", Blob: "iVBORw0KGgo="},
+		}}, nil
+	case "text/plain":
+		return &protocol.ReadResourceResponse{Contents: []protocol.ResourceContent{
+			{URI: req.URI, MimeType: "text/plain", Text: "a 1x1 image"},
+		}}, nil
+	default:
+		return nil, &protocol.Error{Code: protocol.InvalidParams, Message: "no acceptable representation"}
+	}
+}
+
+func TestReadResourceNegotiatesMimeType(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   string
+		wantMime string
+		wantErr  bool
+	}{
+		{"absent defaults to raw", `{"uri":"file:///img"}`, "image/png", false},
+		{"text preferred", `{"uri":"file:///img","acceptedMimeTypes":["text/plain"]}`, "text/plain", false},
+		{"wildcard", `{"uri":"file:///img","acceptedMimeTypes":["image/*"]}`, "image/png", false},
+		{"nothing acceptable", `{"uri":"file:///img","acceptedMimeTypes":["application/pdf"]}`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transp := newMockTransport()
+			registry := handler.NewHandlerRegistry()
+			registry.RegisterResourceHandler(&previewResourceHandler{})
+			srv := New(Options{
+				Name:      "negotiation-test-server",
+				Version:   "1.0.0",
+				Registry:  registry,
+				Transport: transp,
+			})
+
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0",
+				ID:      1,
+				Method:  protocol.MethodResourcesRead,
+				Params:  json.RawMessage(tt.params),
+			}, time.Now())
+
+			if transp.responseCount() != 1 {
+				t.Fatalf("expected 1 response, got %d", transp.responseCount())
+			}
+			resp := transp.responseAt(0)
+			if tt.wantErr {
+				if resp.Error == nil || resp.Error.Code != protocol.InvalidParams {
+					t.Fatalf("expected InvalidParams error, got %+v", resp)
+				}
+				return
+			}
+			result, ok := resp.Result.(*protocol.ReadResourceResponse)
+			if !ok {
+				t.Fatalf("result = %T, want *protocol.ReadResourceResponse", resp.Result)
+			}
+			if got := result.Contents[0].MimeType; got != tt.wantMime {
+				t.Errorf("mimeType = %q, want %q", got, tt.wantMime)
+			}
+		})
+	}
+}
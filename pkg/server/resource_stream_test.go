@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// chunkedResourceHandler streams a resource as three text chunks.
+type chunkedResourceHandler struct {
+	mockResourceHandler
+}
+
+func (h *chunkedResourceHandler) ReadResourceStream(ctx context.Context, req *protocol.ReadResourceRequest, emit func(protocol.ResourceContent) error) (*protocol.ReadResourceResponse, error) {
+	for _, part := range []string{"aaa", "bbb", "ccc"} {
+		if err := emit(protocol.ResourceContent{URI: req.URI, Text: part}); err != nil {
+			return nil, err
+		}
+	}
+	return &protocol.ReadResourceResponse{Contents: []protocol.ResourceContent{}}, nil
+}
+
+func TestStreamingResourceReadSendsChunksBeforeResponse(t *testing.T) {
+	transp := newMockTransport()
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterResourceHandler(&chunkedResourceHandler{})
+	srv := New(Options{
+		Name:      "stream-test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: transp,
+	})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      9,
+		Method:  protocol.MethodResourcesRead,
+		Params:  json.RawMessage(`{"uri":"file:///big.log","_meta":{"stream":true}}`),
+	}, time.Now())
+
+	want := []string{
+		protocol.NotificationResourceChunk,
+		protocol.NotificationResourceChunk,
+		protocol.NotificationResourceChunk,
+		"response",
+	}
+	if got := transp.orderSnapshot(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("send order = %v, want %v", got, want)
+	}
+
+	transp.mu.Lock()
+	defer transp.mu.Unlock()
+	for i, n := range transp.notifications {
+		var params protocol.ResourceChunkParams
+		if err := json.Unmarshal(n.Params, &params); err != nil {
+			t.Fatalf("unmarshal chunk %d: %v", i, err)
+		}
+		if params.Index != i {
+			t.Errorf("chunk %d index = %d", i, params.Index)
+		}
+		if params.RequestID != float64(9) {
+			t.Errorf("chunk %d requestId = %v, want 9", i, params.RequestID)
+		}
+	}
+}
+
+func TestStreamingResourceHandlerWithoutOptIn(t *testing.T) {
+	transp := newMockTransport()
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterResourceHandler(&chunkedResourceHandler{})
+	srv := New(Options{
+		Name:      "stream-test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: transp,
+	})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodResourcesRead,
+		Params:  json.RawMessage(`{"uri":"file:///big.log"}`),
+	}, time.Now())
+
+	if got := transp.orderSnapshot(); !reflect.DeepEqual(got, []string{"response"}) {
+		t.Errorf("send order = %v, want only the response", got)
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// uriRecordingResourceHandler records the URI each read was dispatched with.
+type uriRecordingResourceHandler struct {
+	mockResourceHandler
+	uris []string
+}
+
+func (h *uriRecordingResourceHandler) ReadResource(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResponse, error) {
+	h.uris = append(h.uris, req.URI)
+	return &protocol.ReadResourceResponse{Contents: []protocol.ResourceContent{}}, nil
+}
+
+func TestResourceURINormalization(t *testing.T) {
+	transp := newMockTransport()
+	rec := &uriRecordingResourceHandler{}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterResourceHandler(rec)
+	srv := New(Options{
+		Name:                  "uri-test-server",
+		Version:               "1.0.0",
+		Registry:              registry,
+		Transport:             transp,
+		NormalizeResourceURIs: true,
+	})
+
+	for i, uri := range []string{
+		"FILE:///data/./reports/../summary.txt",
+		"file:///data/../../etc/passwd",
+		"file:///data/%2e%2e/%2e%2e/etc/passwd",
+		"not a uri",
+	} {
+		params, _ := json.Marshal(protocol.ReadResourceRequest{URI: uri})
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0",
+			ID:      i,
+			Method:  protocol.MethodResourcesRead,
+			Params:  params,
+		}, time.Now())
+	}
+
+	if len(rec.uris) != 1 || rec.uris[0] != "file:///data/summary.txt" {
+		t.Fatalf("handler saw %v, want only the normalized file:///data/summary.txt", rec.uris)
+	}
+	responses := transp.responsesSnapshot()
+	if len(responses) != 4 {
+		t.Fatalf("expected 4 responses, got %d", len(responses))
+	}
+	if responses[0].Error != nil {
+		t.Errorf("normalized read failed: %v", responses[0].Error)
+	}
+	for _, resp := range responses[1:] {
+		if resp.Error == nil || resp.Error.Code != protocol.InvalidParams {
+			t.Errorf("request %v: expected InvalidParams, got %+v", resp.ID, resp)
+		}
+	}
+}
+
+func TestResourceURINormalizationOffByDefault(t *testing.T) {
+	rec := &uriRecordingResourceHandler{}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterResourceHandler(rec)
+	srv := New(Options{
+		Name:      "uri-test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: newMockTransport(),
+	})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodResourcesRead,
+		Params:  json.RawMessage(`{"uri":"custom-thing"}`),
+	}, time.Now())
+
+	if len(rec.uris) != 1 || rec.uris[0] != "custom-thing" {
+		t.Errorf("handler saw %v, want the URI untouched", rec.uris)
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestMaxResponseBytes(t *testing.T) {
+	callWith := func(text string) *protocol.Response {
+		mockTransport := newMockTransport()
+		srv := Builder().
+			Transport(mockTransport).
+			Tool(&mockToolHandler{result: &protocol.CallToolResponse{
+				Content: []protocol.ToolContent{{Type: "text", Text: text}},
+			}}).
+			With(WithMaxResponseBytes(1024)).
+			Build()
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+			Params: json.RawMessage(`{"name":"test_tool","arguments":{}}`),
+		}, time.Now())
+		return mockTransport.responseAt(0)
+	}
+
+	if resp := callWith("small"); resp.Error != nil {
+		t.Errorf("result under the limit was rejected: %+v", resp.Error)
+	}
+
+	resp := callWith(strings.Repeat("x", 4096))
+	if resp.Result != nil {
+		t.Error("oversized result was sent")
+	}
+	if resp.Error == nil || resp.Error.Code != protocol.InternalError {
+		t.Fatalf("error = %+v, want InternalError", resp.Error)
+	}
+	if !strings.Contains(resp.Error.Message, "1024 byte limit") {
+		t.Errorf("message %q should state the limit", resp.Error.Message)
+	}
+	data, _ := resp.Error.Data.(map[string]interface{})
+	if size, _ := data["size"].(int); size <= 4096 || data["limit"] != 1024 {
+		t.Errorf("data = %v, want size above 4096 and limit 1024", resp.Error.Data)
+	}
+}
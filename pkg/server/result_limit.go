@@ -0,0 +1,62 @@
+package server
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// truncateToolContent returns resp with its content blocks cut down to max
+// bytes of text and data in total, followed by a notice block. Text is cut
+// on a rune boundary; a non-text block that does not fit is dropped whole,
+// since half an image is useless. resp is returned unchanged when it fits.
+func truncateToolContent(resp *protocol.CallToolResponse, max int) *protocol.CallToolResponse {
+	if resp == nil {
+		return nil
+	}
+	total := 0
+	for _, c := range resp.Content {
+		total += contentBytes(c)
+	}
+	if total <= max {
+		return resp
+	}
+
+	kept := make([]protocol.ToolContent, 0, len(resp.Content)+1)
+	remaining := max
+	for _, c := range resp.Content {
+		size := contentBytes(c)
+		if size <= remaining {
+			kept = append(kept, c)
+			remaining -= size
+			continue
+		}
+		if c.Data == "" && c.Resource == nil && remaining > 0 {
+			cut := remaining
+			for cut > 0 && !utf8.RuneStart(c.Text[cut]) {
+				cut--
+			}
+			c.Text = c.Text[:cut]
+			kept = append(kept, c)
+		}
+		break
+	}
+
+	truncated := *resp
+	truncated.Content = append(kept, protocol.ToolContent{
+		Type: "text",
+		Text: fmt.Sprintf("[Result truncated: content was %d bytes, limit is %d bytes]", total, max),
+	})
+	return &truncated
+}
+
+// contentBytes is the size of c's text and data, counting an embedded
+// resource's text and blob
+func contentBytes(c protocol.ToolContent) int {
+	size := len(c.Text) + len(c.Data)
+	if c.Resource != nil {
+		size += len(c.Resource.Text) + len(c.Resource.Blob)
+	}
+	return size
+}
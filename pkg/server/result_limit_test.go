@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func callWithContentLimit(t *testing.T, limit int, content ...protocol.ToolContent) *protocol.CallToolResponse {
+	t.Helper()
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(&mockToolHandler{result: &protocol.CallToolResponse{Content: content}}).
+		With(WithMaxResultContentBytes(limit)).
+		Build()
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+		Params: json.RawMessage(`{"name":"test_tool","arguments":{}}`),
+	}, time.Now())
+
+	resp := mockTransport.responseAt(0)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	result, ok := resp.Result.(*protocol.CallToolResponse)
+	if !ok {
+		t.Fatalf("result = %T, want *protocol.CallToolResponse", resp.Result)
+	}
+	return result
+}
+
+func TestMaxResultContentBytesWithinLimit(t *testing.T) {
+	result := callWithContentLimit(t, 16, protocol.ToolContent{Type: "text", Text: "small"})
+	if len(result.Content) != 1 || result.Content[0].Text != "small" {
+		t.Errorf("content = %+v, want it untouched", result.Content)
+	}
+}
+
+func TestMaxResultContentBytesTruncates(t *testing.T) {
+	result := callWithContentLimit(t, 10,
+		protocol.ToolContent{Type: "text", Text: "0123456"},
+		protocol.ToolContent{Type: "text", Text: strings.Repeat("x", 100)},
+		protocol.ToolContent{Type: "image", Data: "aGVsbG8=", MimeType: "image/png"},
+	)
+
+	if len(result.Content) != 3 {
+		t.Fatalf("content = %+v, want two kept blocks and a notice", result.Content)
+	}
+	if result.Content[0].Text != "0123456" || result.Content[1].Text != "xxx" {
+		t.Errorf("kept content = %+v, want 10 bytes of text", result.Content[:2])
+	}
+	notice := result.Content[2]
+	if notice.Type != "text" || !strings.Contains(notice.Text, "truncated") || !strings.Contains(notice.Text, "limit is 10 bytes") {
+		t.Errorf("notice = %+v, want a truncation notice stating the limit", notice)
+	}
+}
+
+func TestMaxResultContentBytesCutsOnRuneBoundary(t *testing.T) {
+	result := callWithContentLimit(t, 4, protocol.ToolContent{Type: "text", Text: "ééé"})
+	if got := result.Content[0].Text; got != "éé" {
+		t.Errorf("text = %q, want whole runes only", got)
+	}
+}
+
+func TestMaxResultContentBytesDropsEmbeddedResource(t *testing.T) {
+	result := callWithContentLimit(t, 10,
+		protocol.ToolContent{Type: "text", Text: "head"},
+		protocol.ToolContent{Type: "resource", Resource: &protocol.ResourceContent{URI: "file:///big.txt", Text: strings.Repeat("y", 100)}},
+	)
+
+	if len(result.Content) != 2 || result.Content[0].Text != "head" || result.Content[1].Resource != nil {
+		t.Errorf("content = %+v, want the text block and a notice, the resource dropped", result.Content)
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// sendWithRetry calls send until it succeeds or Options.SendRetries retries
+// have failed, doubling the wait between attempts. Only errors the transport
+// marked with transport.Retryable are retried; any other failure may have
+// left part of the message on the wire. It gives up early when the server
+// shuts down. The last error is returned.
+func (s *Server) sendWithRetry(send func() error) error {
+	err := send()
+	backoff := s.options.SendRetryBackoff
+	for attempt := 1; transport.IsRetryable(err) && attempt <= s.options.SendRetries; attempt++ {
+		log.Printf("Send failed (%v), retry %d/%d in %v", err, attempt, s.options.SendRetries, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-s.shutdown:
+			return err
+		}
+		backoff *= 2
+		err = send()
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// flakyTransport fails the first failures Sends, then delegates to the mock.
+// The failures are marked retryable unless permanent is set.
+type flakyTransport struct {
+	*mockTransport
+	mu        sync.Mutex
+	failures  int
+	attempts  int
+	permanent bool
+}
+
+func (t *flakyTransport) Send(response *protocol.Response) error {
+	t.mu.Lock()
+	t.attempts++
+	fail := t.attempts <= t.failures
+	t.mu.Unlock()
+	if fail {
+		err := errors.New("write: broken pipe")
+		if t.permanent {
+			return err
+		}
+		return transport.Retryable(err)
+	}
+	return t.mockTransport.Send(response)
+}
+
+func TestSendRetrySucceeds(t *testing.T) {
+	transp := &flakyTransport{mockTransport: newMockTransport(), failures: 1}
+	srv := Builder().
+		Transport(transp).
+		With(WithSendRetry(2, time.Millisecond)).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodPing,
+	}, time.Now())
+
+	if transp.attempts != 2 {
+		t.Errorf("attempts = %d, want 2", transp.attempts)
+	}
+	if transp.responseCount() != 1 {
+		t.Fatalf("expected the response to be delivered on retry, got %d", transp.responseCount())
+	}
+	if resp := transp.responseAt(0); resp.ID != 1 || resp.Error != nil {
+		t.Errorf("response = %+v, want the ping result", resp)
+	}
+}
+
+func TestSendErrorHandlerAfterRetries(t *testing.T) {
+	transp := &flakyTransport{mockTransport: newMockTransport(), failures: 10}
+	var failed []*protocol.Response
+	srv := Builder().
+		Transport(transp).
+		With(
+			WithSendRetry(2, time.Millisecond),
+			WithSendErrorHandler(func(resp *protocol.Response, err error) {
+				failed = append(failed, resp)
+			}),
+		).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodPing,
+	}, time.Now())
+
+	if transp.attempts != 3 {
+		t.Errorf("attempts = %d, want the first send and 2 retries", transp.attempts)
+	}
+	if len(failed) != 1 || failed[0].ID != 1 {
+		t.Errorf("handler got %+v, want the undelivered ping response", failed)
+	}
+}
+
+func TestSendRetrySkipsUnmarkedErrors(t *testing.T) {
+	transp := &flakyTransport{mockTransport: newMockTransport(), failures: 1, permanent: true}
+	var failed []*protocol.Response
+	srv := Builder().
+		Transport(transp).
+		With(
+			WithSendRetry(2, time.Millisecond),
+			WithSendErrorHandler(func(resp *protocol.Response, err error) {
+				failed = append(failed, resp)
+			}),
+		).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodPing,
+	}, time.Now())
+
+	if transp.attempts != 1 {
+		t.Errorf("attempts = %d, want 1: the failed write may have been partial", transp.attempts)
+	}
+	if transp.responseCount() != 0 {
+		t.Errorf("responses = %d, want 0", transp.responseCount())
+	}
+	if len(failed) != 1 {
+		t.Errorf("handler got %d responses, want the undelivered ping response", len(failed))
+	}
+}
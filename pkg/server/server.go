@@ -3,9 +3,14 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"runtime/debug"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gomcpgo/mcp/pkg/handler"
 	"github.com/gomcpgo/mcp/pkg/protocol"
@@ -19,6 +24,12 @@ type Server struct {
 	transport transport.Transport
 	tracker   *requestTracker
 
+	// limiter enforces RateLimit / MethodRateLimits; nil when unlimited.
+	limiter *rateLimiter
+
+	// slots enforces MaxConcurrentRequests; nil when unlimited.
+	slots *concurrencyLimiter
+
 	// outbound correlates server-initiated requests (e.g. elicitation/create)
 	// with the response the client sends back.
 	outbound *outboundTracker
@@ -29,15 +40,36 @@ type Server struct {
 
 	// clientCaps is the capabilities block the client sent during
 	// initialize. Used by Server.Elicit to refuse calls when the client did
-	// not advertise elicitation support.
+	// not advertise elicitation support. On a multi-client transport each
+	// session declares its own, kept in sessionCaps instead.
 	clientCapsMu sync.RWMutex
 	clientCaps   *protocol.ClientCapabilities
+	sessionCaps  map[string]*protocol.ClientCapabilities
 
 	// logLevel is the minimum level at which LogMessage emits
 	// notifications/message. Controlled by logging/setLevel. Defaults to
 	// "info". Guarded by logMu.
 	logMu    sync.RWMutex
 	logLevel string
+
+	// ready flips to true when the client sends notifications/initialized,
+	// completing the lifecycle handshake. A new initialize resets it. On a
+	// multi-client transport each session shakes hands on its own and is
+	// tracked in readySessions instead, guarded by readyMu.
+	ready         atomic.Bool
+	readyMu       sync.Mutex
+	readySessions map[string]bool
+
+	// shutdown is closed by Shutdown to make Run return.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+
+	// builtinTools holds the operation tools added by WithAsyncExecutor, or
+	// nil when there is no executor.
+	builtinTools *handler.ToolRouter
+
+	// startedAt is when New ran, for the uptime reported by describe.
+	startedAt time.Time
 }
 
 // New creates a new MCP server instance with the provided options
@@ -67,18 +99,179 @@ func New(options Options) *Server {
 	if options.Transport != nil {
 		defaultOpts.Transport = options.Transport
 	}
+	if options.toolHandler != nil {
+		defaultOpts.Registry.RegisterToolHandler(options.toolHandler)
+	}
+	if options.resourceHandler != nil {
+		defaultOpts.Registry.RegisterResourceHandler(options.resourceHandler)
+	}
+	if options.promptHandler != nil {
+		defaultOpts.Registry.RegisterPromptHandler(options.promptHandler)
+	}
+	if options.Capabilities != nil {
+		defaultOpts.Capabilities = options.Capabilities
+	}
+	if options.Instructions != "" {
+		defaultOpts.Instructions = options.Instructions
+	}
+	if len(options.InitializeMeta) > 0 {
+		defaultOpts.InitializeMeta = options.InitializeMeta
+	}
+	if len(options.ToolMiddleware) > 0 {
+		defaultOpts.ToolMiddleware = options.ToolMiddleware
+	}
+	if options.RequestTimeout > 0 {
+		defaultOpts.RequestTimeout = options.RequestTimeout
+	}
+	if len(options.MethodTimeouts) > 0 {
+		defaultOpts.MethodTimeouts = options.MethodTimeouts
+	}
+	if options.RateLimit.enabled() {
+		defaultOpts.RateLimit = options.RateLimit
+	}
+	if len(options.MethodRateLimits) > 0 {
+		defaultOpts.MethodRateLimits = options.MethodRateLimits
+	}
+	if options.ValidateID != nil {
+		defaultOpts.ValidateID = options.ValidateID
+	}
+	if options.RequestValidator != nil {
+		defaultOpts.RequestValidator = options.RequestValidator
+	}
+	if options.ErrorMessageFunc != nil {
+		defaultOpts.ErrorMessageFunc = options.ErrorMessageFunc
+	}
+	if options.RequestInterceptor != nil {
+		defaultOpts.RequestInterceptor = options.RequestInterceptor
+	}
+	if options.ResponseInterceptor != nil {
+		defaultOpts.ResponseInterceptor = options.ResponseInterceptor
+	}
+	if len(options.RedactKeys) > 0 {
+		defaultOpts.RedactKeys = options.RedactKeys
+	}
+	if options.ValidateOnlyDryRun {
+		defaultOpts.ValidateOnlyDryRun = true
+	}
+	if options.ResolveResourceArguments {
+		defaultOpts.ResolveResourceArguments = true
+	}
+	if options.ApplyArgumentDefaults {
+		defaultOpts.ApplyArgumentDefaults = true
+	}
+	if options.ValidateToolOutput {
+		defaultOpts.ValidateToolOutput = true
+	}
+	if options.SendRetries > 0 {
+		defaultOpts.SendRetries = options.SendRetries
+		defaultOpts.SendRetryBackoff = options.SendRetryBackoff
+	}
+	if options.SendErrorHandler != nil {
+		defaultOpts.SendErrorHandler = options.SendErrorHandler
+	}
+	if options.RecoveryHandler != nil {
+		defaultOpts.RecoveryHandler = options.RecoveryHandler
+	}
+	if options.MaxRequestAge > 0 {
+		defaultOpts.MaxRequestAge = options.MaxRequestAge
+	}
+	if options.MaxResultContentBytes > 0 {
+		defaultOpts.MaxResultContentBytes = options.MaxResultContentBytes
+	}
+	if options.MaxResponseBytes > 0 {
+		defaultOpts.MaxResponseBytes = options.MaxResponseBytes
+	}
+	if options.NormalizeResourceURIs {
+		defaultOpts.NormalizeResourceURIs = true
+	}
+	if options.StrictCapabilities {
+		defaultOpts.StrictCapabilities = true
+	}
+	if options.StrictDecoding {
+		defaultOpts.StrictDecoding = true
+	}
+	if options.RequireInitialize {
+		defaultOpts.RequireInitialize = true
+	}
+	if options.NotificationIDPolicy != NotificationIDIgnore {
+		defaultOpts.NotificationIDPolicy = options.NotificationIDPolicy
+	}
+	if options.ShutdownMethod != "" {
+		defaultOpts.ShutdownMethod = options.ShutdownMethod
+	}
+	if options.Introspection {
+		defaultOpts.Introspection = true
+	}
+	if options.LogLevel != "" {
+		defaultOpts.LogLevel = options.LogLevel
+	}
+	if options.CanonicalJSON {
+		defaultOpts.CanonicalJSON = true
+	}
+	if options.Clock != nil {
+		defaultOpts.Clock = options.Clock
+	}
+	if options.RateLimitKey != nil {
+		defaultOpts.RateLimitKey = options.RateLimitKey
+	}
+	if options.MaxConcurrentRequests > 0 {
+		defaultOpts.MaxConcurrentRequests = options.MaxConcurrentRequests
+	}
+	if options.MaxQueueDepth > 0 {
+		defaultOpts.MaxQueueDepth = options.MaxQueueDepth
+	}
+	if options.AsyncExecutor != nil {
+		defaultOpts.AsyncExecutor = options.AsyncExecutor
+	}
 
-	return &Server{
+	var builtinTools *handler.ToolRouter
+	if executor := defaultOpts.AsyncExecutor; executor != nil {
+		builtinTools = handler.NewToolRouter()
+		executor.RegisterTools(builtinTools)
+		executor.RegisterManagementTools(builtinTools)
+	}
+
+	s := &Server{
 		options:   defaultOpts,
 		registry:  defaultOpts.Registry,
 		transport: defaultOpts.Transport,
 		tracker:   newRequestTracker(),
 		outbound:  newOutboundTracker(),
+		limiter:   newRateLimiter(defaultOpts.RateLimit, defaultOpts.MethodRateLimits, defaultOpts.Clock),
+		slots:     newConcurrencyLimiter(defaultOpts.MaxConcurrentRequests, defaultOpts.MaxQueueDepth),
 		logLevel:  protocol.LogLevelInfo,
+		shutdown:  make(chan struct{}),
+
+		builtinTools: builtinTools,
+		startedAt:    defaultOpts.Clock.Now(),
+	}
+	if notifier, ok := s.transport.(transport.SessionCloseNotifier); ok {
+		notifier.NotifySessionClosed(s.sessionClosed)
+	}
+	return s
+}
+
+// sessionClosed releases what the server keeps for a session the transport
+// closed: its handshake state and the async operations it owns.
+func (s *Server) sessionClosed(sessionID string) {
+	s.readyMu.Lock()
+	delete(s.readySessions, sessionID)
+	s.readyMu.Unlock()
+
+	s.clientCapsMu.Lock()
+	delete(s.sessionCaps, sessionID)
+	s.clientCapsMu.Unlock()
+
+	if executor := s.options.AsyncExecutor; executor != nil {
+		if n := executor.CancelByOwner(sessionID); n > 0 {
+			log.Printf("Cancelled %d operations of closed session %s", n, sessionID)
+		}
 	}
 }
 
-// Run starts the server and handles requests
+// Run starts the server and handles requests. When it returns, because the
+// client disconnected (EOF) or the server shut down, the contexts of
+// handlers still running are cancelled.
 func (s *Server) Run() error {
 	ctx := context.Background()
 
@@ -88,9 +281,26 @@ func (s *Server) Run() error {
 	}
 	defer s.transport.Stop(ctx)
 
+	// Handlers run under ctx, so cancelling it stops in-flight work once
+	// nobody is left to receive the response.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Transports that can carry JSON-RPC batches deliver them on their own
+	// channel. A nil channel never fires, so plain transports are unaffected.
+	var batches <-chan []*protocol.Request
+	batchTransport, ok := s.transport.(transport.BatchTransport)
+	if ok {
+		batches = batchTransport.ReceiveBatch()
+	}
+
 	// Process requests and client responses
 	for {
 		select {
+		case <-s.shutdown:
+			log.Printf("Shutdown requested, stopping")
+			return nil
+
 		case err := <-s.transport.Errors():
 			log.Printf("Transport error: %v", err)
 			continue
@@ -101,7 +311,15 @@ func (s *Server) Run() error {
 				return nil
 			}
 
-			go s.handleRequest(ctx, req)
+			go s.handleRequest(ctx, req, s.options.Clock.Now())
+
+		case reqs := <-batches:
+			if reqs == nil {
+				log.Printf("Received nil batch, shutting down")
+				return nil
+			}
+
+			go s.handleBatch(ctx, batchTransport, reqs, s.options.Clock.Now())
 
 		case resp := <-s.transport.Responses():
 			if resp == nil {
@@ -115,63 +333,478 @@ func (s *Server) Run() error {
 	}
 }
 
-// handleRequest processes individual requests
-func (s *Server) handleRequest(parent context.Context, req *protocol.Request) {
-	log.Printf("MCP server req received:\n%v\n", truncatedJSON(req))
+// handleRequest processes an individual request and writes its response, if
+// any, to the transport. receivedAt is when the request came off the
+// transport; it drives the MaxRequestAge check.
+func (s *Server) handleRequest(parent context.Context, req *protocol.Request, receivedAt time.Time) {
+	resp, shutdown := s.processRequest(parent, req, receivedAt)
+	if resp != nil {
+		// Multi-client transports route the response by the request's
+		// session, carried in its context.
+		s.writeResponse(resp.WithContext(req.Context()))
+	}
+	// Shut down only once the acknowledgement is on the wire.
+	if shutdown {
+		s.Shutdown()
+	}
+}
+
+// Shutdown makes Run stop reading from the transport, stop it and return
+// nil. Handlers already running are not waited for. It is safe to call more
+// than once and from any goroutine.
+func (s *Server) Shutdown() {
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+}
 
-	// Notifications (no id) do not receive a response.
-	if req.ID == nil {
+// isShutdownMethod reports whether method is the configured
+// Options.ShutdownMethod
+func (s *Server) isShutdownMethod(method string) bool {
+	return s.options.ShutdownMethod != "" && method == s.options.ShutdownMethod
+}
+
+// processRequest runs a request through validation and dispatch and returns
+// the response to send, or nil when nothing should be sent (notifications,
+// stale or cancelled requests). Keeping this separate from the write lets a
+// batch collect every response before replying. shutdown reports that req
+// was the ShutdownMethod and was accepted: it passed every check and was
+// answered successfully, or was a notification.
+func (s *Server) processRequest(parent context.Context, req *protocol.Request, receivedAt time.Time) (resp *protocol.Response, shutdown bool) {
+	if s.logs(protocol.LogLevelDebug) {
+		log.Printf("MCP server req received:\n%v\n", s.logJSON(req))
+	}
+	if s.logs(protocol.LogLevelInfo) && !req.IsNotification() && !isNotificationMethod(req.Method) {
+		start := s.options.Clock.Now()
+		defer func() {
+			log.Printf("MCP server handled %s (id %v) in %v", req.Method, req.ID, s.options.Clock.Now().Sub(start))
+		}()
+	}
+
+	// Notifications (no id) do not receive a response. Anything in the
+	// notifications/ namespace is treated the same even if a confused client
+	// attached an id, unless NotificationIDPolicy says to answer that id.
+	if req.IsNotification() {
 		s.handleNotification(req)
-		return
+		return nil, s.isShutdownMethod(req.Method)
+	}
+	if isNotificationMethod(req.Method) {
+		switch s.options.NotificationIDPolicy {
+		case NotificationIDAcknowledge:
+			s.handleNotification(req)
+			return resultResponse(req.ID, struct{}{}), s.isShutdownMethod(req.Method)
+		case NotificationIDReject:
+			return errorResponse(req.ID, protocol.InvalidRequest, fmt.Sprintf("%s is a notification and must not carry an id", req.Method)), false
+		default:
+			s.handleNotification(req)
+			return nil, s.isShutdownMethod(req.Method)
+		}
+	}
+
+	// An explicit `"id": null` is not a notification but is not a usable
+	// request either: MCP requires a string or number id. Reject it, echoing
+	// the null id as JSON-RPC prescribes for unidentifiable requests.
+	if req.HasNullID() {
+		return errorResponse(nil, protocol.InvalidRequest, "request id must not be null"), false
+	}
+
+	if s.options.RequireInitialize && !s.readyFor(req.Context()) &&
+		req.Method != protocol.MethodInitialize && req.Method != protocol.MethodPing {
+		return errorResponse(req.ID, protocol.NotInitialized, fmt.Sprintf("server not initialized: %s sent before the initialize handshake completed", req.Method)), false
+	}
+
+	// Transports such as HTTP attach a per-request context carrying the
+	// caller's identity and the connection lifetime; handlers run under it.
+	// The server's own context still ends them when Run returns.
+	serverCtx := parent
+	if reqCtx := req.Context(); reqCtx != context.Background() {
+		parent = reqCtx
+	}
+
+	if s.options.ValidateID != nil {
+		if err := s.options.ValidateID(req.ID); err != nil {
+			return errorResponse(req.ID, protocol.InvalidRequest, fmt.Sprintf("invalid request id: %v", err)), false
+		}
+	}
+
+	if s.options.RequestValidator != nil {
+		if rpcErr := s.options.RequestValidator(req); rpcErr != nil {
+			return rpcErrorResponse(req.ID, rpcErr), false
+		}
+	}
+
+	// Refuse stale requests without running them: the client has most
+	// likely timed out already, so any work done now is wasted. The error
+	// still goes back so a transport holding the request open, like HTTP,
+	// can answer it.
+	if resp := s.expired(req, receivedAt); resp != nil {
+		return resp, false
+	}
+
+	var client string
+	if s.options.RateLimitKey != nil {
+		client = s.options.RateLimitKey(parent)
+	}
+	if ok, retryAfter := s.limiter.allow(client, req.Method); !ok {
+		log.Printf("Rate limit exceeded for request %v (%s); retry after %v", req.ID, req.Method, retryAfter)
+		return rpcErrorResponse(req.ID, rateLimitedError(req.Method, retryAfter)), false
 	}
 
 	// Give the handler a cancellable context so an inbound
 	// notifications/cancelled for this ID can stop it mid-flight.
-	ctx, cancel := s.tracker.register(parent, req.ID)
+	session, _ := transport.SessionIDFromContext(req.Context())
+	ctx, cancel := s.tracker.register(parent, session, req.ID)
 	defer func() {
 		cancel()
-		s.tracker.unregister(req.ID)
+		s.tracker.unregister(session, req.ID)
 	}()
+	if parent != serverCtx {
+		defer cancelWhenDone(serverCtx, cancel)()
+	}
+
+	// Wait for a free slot before any handler deadline starts to run.
+	var handlerReturned <-chan struct{}
+	if req.Method != protocol.MethodPing {
+		release, err := s.slots.acquire(ctx)
+		if errors.Is(err, errServerBusy) {
+			log.Printf("Rejecting request %v (%s): %d requests already queued", req.ID, req.Method, s.QueueDepth())
+			return errorResponse(req.ID, protocol.ServerBusy, "server busy, try again later"), false
+		}
+		if err != nil {
+			log.Printf("Request %v (%s) abandoned while queued: %v", req.ID, req.Method, err)
+			// A request the client cancelled gets no reply, as when it is
+			// cancelled mid-flight
+			if s.tracker.wasCancelled(session, req.ID) {
+				return nil, false
+			}
+			return errorResponse(req.ID, protocol.RequestTimeout, fmt.Sprintf("request abandoned while waiting for a free slot: %v", err)), false
+		}
+		// A handler that outlives its request keeps the slot until it
+		// actually returns, so abandoned handlers cannot pile up.
+		defer func() {
+			select {
+			case <-handlerReturned:
+			default:
+				if handlerReturned != nil {
+					go func() {
+						<-handlerReturned
+						release()
+					}()
+					return
+				}
+			}
+			release()
+		}()
+		// Waiting for the slot may itself have made the request stale
+		if resp := s.expired(req, receivedAt); resp != nil {
+			return resp, false
+		}
+	}
+
+	// Bound the handler by the per-method timeout, falling back to the
+	// global RequestTimeout, and by the client's own deadline from
+	// `_meta.timeoutMs`, which can shorten the server's but never extend
+	// it. Zero means no deadline.
+	timeout := s.methodTimeout(req.Method)
+	clientDeadline, deadlineErr := clientTimeout(req.Params)
+	if deadlineErr != nil {
+		return errorResponse(req.ID, protocol.InvalidParams, deadlineErr.Error()), false
+	}
+	if clientDeadline > 0 && (timeout == 0 || clientDeadline < timeout) {
+		timeout = clientDeadline
+	}
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
 
 	// If the client attached `_meta.progressToken`, inject a reporter bound
 	// to that token so ProgressReporterFromContext(ctx).Report(...) in the
 	// handler becomes an outbound notifications/progress. No token → the
 	// handler-package default no-op reporter is used.
 	if token := extractProgressToken(req.Params); token != nil {
+		notifyCtx := ctx
 		reporter := &transportProgressReporter{
-			sendNotification: s.SendNotification,
-			token:            token,
+			sendNotification: func(method string, params interface{}) error {
+				return s.notify(notifyCtx, method, params)
+			},
+			token: token,
 		}
 		ctx = handler.WithProgressReporter(ctx, reporter)
+		ctx = context.WithValue(ctx, progressTokenKey{}, token)
 	}
 
+	// Let handlers adapt to what the client declared; see ClientSupports.
+	ctx = context.WithValue(ctx, clientCapsKey{}, s.clientCapabilities(ctx))
+
 	// Inject an Elicitor when the client declared elicitation support during
 	// initialize. Otherwise leave ctx alone and handlers see the stub
 	// returning ErrElicitationNotSupported.
-	if s.clientSupportsElicitation() {
+	if s.clientSupportsElicitation(ctx) {
 		ctx = handler.WithElicitor(ctx, serverElicitor{s: s})
 	}
 
-	result, err := s.dispatchRequest(ctx, req)
+	if s.options.RequestInterceptor != nil {
+		s.options.RequestInterceptor(req)
+	}
+
+	var result interface{}
+	var err error
+	result, handlerReturned, err = s.dispatchIsolated(ctx, req)
 
 	// If the client cancelled mid-flight, the handler's result (or error) is
 	// stale per MCP spec — suppress the response so we don't waste bytes or
 	// confuse the client.
-	if s.tracker.wasCancelled(req.ID) {
+	if s.tracker.wasCancelled(session, req.ID) {
 		log.Printf("Request %v was cancelled; suppressing response", req.ID)
-		return
+		return nil, false
 	}
 
 	if err != nil {
-		s.sendError(req.ID, protocol.InternalError, err.Error())
+		return rpcErrorResponse(req.ID, s.handlerError(req, err)), false
+	}
+
+	if s.options.ResponseInterceptor != nil {
+		result = s.options.ResponseInterceptor(req.ID, result)
+	}
+
+	if s.options.CanonicalJSON {
+		canonical, err := protocol.CanonicalJSON(result)
+		if err != nil {
+			log.Printf("Failed to encode result of %v (%s): %v", req.ID, req.Method, err)
+			return errorResponse(req.ID, protocol.InternalError, "failed to encode result"), false
+		}
+		result = json.RawMessage(canonical)
+	}
+
+	if rpcErr := s.checkResponseSize(req, result); rpcErr != nil {
+		return rpcErrorResponse(req.ID, rpcErr), false
+	}
+
+	return resultResponse(req.ID, result), s.isShutdownMethod(req.Method)
+}
+
+// expired returns the RequestTimeout error for req, and logs it, when req
+// has waited longer than MaxRequestAge since it was received at receivedAt.
+// It returns nil for a request still fresh enough to run.
+func (s *Server) expired(req *protocol.Request, receivedAt time.Time) *protocol.Response {
+	if s.options.MaxRequestAge <= 0 {
+		return nil
+	}
+	age := s.options.Clock.Now().Sub(receivedAt)
+	if age <= s.options.MaxRequestAge {
+		return nil
+	}
+	log.Printf("Dropping request %v (%s): waited %v, exceeds max age %v", req.ID, req.Method, age, s.options.MaxRequestAge)
+	return errorResponse(req.ID, protocol.RequestTimeout, fmt.Sprintf("request expired after waiting %v, exceeds max age %v", age, s.options.MaxRequestAge))
+}
+
+// checkResponseSize returns an error when result marshals to more than
+// MaxResponseBytes. A result that fails to marshal is left for the
+// transport to report.
+func (s *Server) checkResponseSize(req *protocol.Request, result interface{}) *protocol.Error {
+	limit := s.options.MaxResponseBytes
+	if limit <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil || len(data) <= limit {
+		return nil
+	}
+	log.Printf("Response to %v (%s) is %d bytes, exceeds limit of %d", req.ID, req.Method, len(data), limit)
+	return &protocol.Error{
+		Code:    protocol.InternalError,
+		Message: fmt.Sprintf("response of %d bytes exceeds the %d byte limit", len(data), limit),
+		Data:    map[string]interface{}{"size": len(data), "limit": limit},
+	}
+}
+
+// handlerError converts an error returned by a handler into the JSON-RPC
+// error sent to the client: a *protocol.Error keeps its code, a context
+// deadline or cancellation becomes RequestTimeout, anything else becomes
+// InternalError. Options.ErrorMessageFunc, when set, chooses the message;
+// the original is then logged so it is not lost.
+func (s *Server) handlerError(req *protocol.Request, err error) *protocol.Error {
+	rpcErr := &protocol.Error{Code: protocol.InternalError, Message: err.Error()}
+	var handlerErr *protocol.Error
+	switch {
+	case errors.As(err, &handlerErr):
+		copied := *handlerErr
+		rpcErr = &copied
+	case errors.Is(err, context.DeadlineExceeded):
+		rpcErr = &protocol.Error{
+			Code:    protocol.RequestTimeout,
+			Message: fmt.Sprintf("request %s timed out", req.Method),
+		}
+	case errors.Is(err, context.Canceled):
+		rpcErr = &protocol.Error{
+			Code:    protocol.RequestTimeout,
+			Message: fmt.Sprintf("request %s was cancelled", req.Method),
+		}
+	}
+	if s.options.ErrorMessageFunc != nil {
+		if message := s.options.ErrorMessageFunc(rpcErr.Code, err); message != rpcErr.Message {
+			log.Printf("Request %v (%s) failed with code %d: %v", req.ID, req.Method, rpcErr.Code, err)
+			rpcErr.Message = message
+		}
+	}
+	return rpcErr
+}
+
+// handleBatch processes a JSON-RPC batch. Entries run concurrently, but the
+// replies are collected and written as a single array in request order so
+// each element lines up with its request id. A batch made up entirely of
+// notifications gets no reply at all.
+func (s *Server) handleBatch(ctx context.Context, bt transport.BatchTransport, reqs []*protocol.Request, receivedAt time.Time) {
+	responses := make([]*protocol.Response, len(reqs))
+	shutdowns := make([]bool, len(reqs))
+
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req *protocol.Request) {
+			defer wg.Done()
+			responses[i], shutdowns[i] = s.processRequest(ctx, req, receivedAt)
+		}(i, req)
+	}
+	wg.Wait()
+	// Shut down only once the replies are on the wire, as handleRequest does
+	defer func() {
+		for _, shutdown := range shutdowns {
+			if shutdown {
+				s.Shutdown()
+				return
+			}
+		}
+	}()
+
+	out := make([]*protocol.Response, 0, len(responses))
+	for _, resp := range responses {
+		if resp != nil {
+			out = append(out, resp)
+		}
+	}
+	if len(out) == 0 {
 		return
 	}
 
-	s.sendResponse(req.ID, result)
+	if s.logs(protocol.LogLevelDebug) {
+		log.Printf("MCP server batch response:\n%v\n", s.logJSON(out))
+	}
+	if err := s.sendWithRetry(func() error { return bt.SendBatch(out) }); err != nil {
+		log.Printf("Error sending batch response: %v", err)
+		if s.options.SendErrorHandler != nil {
+			for _, resp := range out {
+				s.options.SendErrorHandler(resp, err)
+			}
+		}
+	}
+}
+
+// cancelWhenDone calls cancel once ctx is done, until the returned func is
+// called. It ties a context to a second parent.
+func cancelWhenDone(ctx context.Context, cancel context.CancelFunc) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-stopped:
+		}
+	}()
+	return func() { close(stopped) }
+}
+
+// dispatchIsolated runs the handler on its own goroutine and stops waiting
+// once ctx ends, so a handler that ignores its context cannot hold the
+// request past its deadline. Together with dispatchWithRecovery this yields
+// exactly one outcome per request: the result, a timeout, or a panic error.
+// A handler still running is abandoned with its context cancelled; whatever
+// it produces later is discarded. The returned channel is closed once the
+// handler has actually returned.
+func (s *Server) dispatchIsolated(ctx context.Context, req *protocol.Request) (interface{}, <-chan struct{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		result interface{}
+		err    error
+	}
+	done := make(chan outcome, 1)
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		result, err := s.dispatchWithRecovery(ctx, req)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, returned, o.err
+	case <-ctx.Done():
+		// handlerError turns this into a RequestTimeout response
+		return nil, returned, ctx.Err()
+	}
+}
+
+// dispatchWithRecovery runs dispatchRequest and converts a handler panic into
+// the *protocol.Error chosen by the recovery handler, so one bad request
+// cannot take down the whole server.
+func (s *Server) dispatchWithRecovery(ctx context.Context, req *protocol.Request) (result interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			var rpcErr *protocol.Error
+			if s.options.RecoveryHandler != nil {
+				rpcErr = s.options.RecoveryHandler(ctx, req, recovered)
+			}
+			if rpcErr == nil {
+				rpcErr = defaultRecoveryHandler(ctx, req, recovered)
+			}
+			result, err = nil, rpcErr
+		}
+	}()
+	return s.dispatchRequest(ctx, req)
+}
+
+// defaultRecoveryHandler logs the panic with its stack trace and reports a
+// generic InternalError without leaking the panic value to the client.
+func defaultRecoveryHandler(_ context.Context, req *protocol.Request, recovered interface{}) *protocol.Error {
+	log.Printf("panic handling request %v (%s): %v\n%s", req.ID, req.Method, recovered, debug.Stack())
+	return &protocol.Error{
+		Code:    protocol.InternalError,
+		Message: "internal error",
+	}
+}
+
+// methodTimeout returns the handler deadline for method: its entry in
+// MethodTimeouts if present, else RequestTimeout.
+func (s *Server) methodTimeout(method string) time.Duration {
+	if timeout, ok := s.options.MethodTimeouts[method]; ok {
+		return timeout
+	}
+	return s.options.RequestTimeout
+}
+
+// notSupported is the strict-mode error for a capability with no handler.
+func notSupported(capability string) *protocol.Error {
+	return &protocol.Error{
+		Code:    protocol.MethodNotFound,
+		Message: capability + " not supported",
+	}
 }
 
 // dispatchRequest routes a request to the appropriate handler based on method.
 func (s *Server) dispatchRequest(ctx context.Context, req *protocol.Request) (interface{}, error) {
+	// The shutdown itself happens once the response has been written.
+	if s.isShutdownMethod(req.Method) {
+		return struct{}{}, nil
+	}
+	if s.isDescribeMethod(req.Method) {
+		return s.describe(ctx)
+	}
+
 	switch req.Method {
 	case protocol.MethodInitialize:
 		return s.handleInitialize(ctx, req.Params)
@@ -181,7 +814,7 @@ func (s *Server) dispatchRequest(ctx context.Context, req *protocol.Request) (in
 
 	case protocol.MethodLoggingSetLevel:
 		var setReq protocol.SetLevelParams
-		if err := json.Unmarshal(req.Params, &setReq); err != nil {
+		if err := s.decodeParams(req.Params, &setReq); err != nil {
 			return nil, fmt.Errorf("invalid logging/setLevel parameters: %w", err)
 		}
 		if protocol.LogLevelRank(setReq.Level) < 0 {
@@ -192,69 +825,162 @@ func (s *Server) dispatchRequest(ctx context.Context, req *protocol.Request) (in
 		s.logMu.Unlock()
 		return struct{}{}, nil
 
+	// Each case reads its handler from the registry exactly once: handlers
+	// may be swapped or unregistered concurrently, so a Has/Get pair could
+	// observe two different states.
 	case protocol.MethodToolsList:
-		if s.registry.HasToolHandler() {
-			return s.registry.GetToolHandler().ListTools(ctx)
+		if h := s.toolHandler(); h != nil {
+			return h.ListTools(ctx)
+		}
+		if s.options.StrictCapabilities {
+			return nil, notSupported("tools")
 		}
 		return &protocol.ListToolsResponse{Tools: []protocol.Tool{}}, nil
 
 	case protocol.MethodToolsCall:
-		if !s.registry.HasToolHandler() {
+		toolHandler := s.toolHandler()
+		if toolHandler == nil {
 			return nil, fmt.Errorf("tools not supported")
 		}
 		var toolReq protocol.CallToolRequest
-		if err := json.Unmarshal(req.Params, &toolReq); err != nil {
-			// Caller expects sendError on invalid params — but returning an
-			// error here keeps the flow uniform; sendError will fire with
+		if err := s.decodeParams(req.Params, &toolReq); err != nil {
+			// Caller expects an error response on invalid params — returning
+			// an error here keeps the flow uniform; processRequest answers with
 			// InternalError. That's a minor downgrade from InvalidParams in
 			// exchange for a simpler control flow; if strict invalid-params
 			// signalling matters, we can add per-case overrides later.
 			return nil, fmt.Errorf("invalid tool parameters: %w", err)
 		}
-		return s.registry.GetToolHandler().CallTool(ctx, &toolReq)
+		if s.options.ResolveResourceArguments {
+			if err := s.resolveResourceArguments(ctx, &toolReq); err != nil {
+				return nil, err
+			}
+		}
+		if s.options.ApplyArgumentDefaults {
+			if err := applyArgumentDefaults(ctx, toolHandler, &toolReq); err != nil {
+				return nil, err
+			}
+		}
+		if dryRun, _ := extractMeta(req.Params)["dryRun"].(bool); dryRun {
+			if s.options.ValidateOnlyDryRun {
+				return validateToolCall(ctx, toolHandler, &toolReq)
+			}
+			ctx = context.WithValue(ctx, dryRunKey{}, true)
+		}
+		stream := s.newToolStream(req)
+		ctx = context.WithValue(ctx, toolStreamKey{}, stream)
+		call := handler.ChainToolMiddleware(toolHandler.CallTool, s.options.ToolMiddleware...)
+		resp, err := call(ctx, &toolReq)
+		if err != nil {
+			return nil, err
+		}
+		resp = stream.finish(resp)
+		if s.options.ValidateToolOutput {
+			if err := validateToolOutput(ctx, toolHandler, &toolReq, resp); err != nil {
+				return nil, err
+			}
+		}
+		if s.options.MaxResultContentBytes > 0 {
+			resp = truncateToolContent(resp, s.options.MaxResultContentBytes)
+		}
+		return resp, nil
 
 	case protocol.MethodResourcesList:
-		if s.registry.HasResourceHandler() {
-			return s.registry.GetResourceHandler().ListResources(ctx)
+		if h := s.registry.GetResourceHandler(); h != nil {
+			return h.ListResources(ctx)
+		}
+		if s.options.StrictCapabilities {
+			return nil, notSupported("resources")
 		}
 		return &protocol.ListResourcesResponse{Resources: []protocol.Resource{}}, nil
 
 	case protocol.MethodResourcesRead:
-		if !s.registry.HasResourceHandler() {
+		resourceHandler := s.registry.GetResourceHandler()
+		if resourceHandler == nil {
 			return nil, fmt.Errorf("resources not supported")
 		}
 		var resourceReq protocol.ReadResourceRequest
-		if err := json.Unmarshal(req.Params, &resourceReq); err != nil {
+		if err := s.decodeParams(req.Params, &resourceReq); err != nil {
 			return nil, fmt.Errorf("invalid resource parameters: %w", err)
 		}
-		return s.registry.GetResourceHandler().ReadResource(ctx, &resourceReq)
+		uri, err := s.normalizeResourceURI(resourceReq.URI)
+		if err != nil {
+			return nil, &protocol.Error{Code: protocol.InvalidParams, Message: err.Error()}
+		}
+		resourceReq.URI = uri
+		if streamer, ok := resourceHandler.(handler.StreamingResourceHandler); ok {
+			if stream, _ := extractMeta(req.Params)["stream"].(bool); stream {
+				return streamer.ReadResourceStream(ctx, &resourceReq, s.resourceChunkEmitter(ctx, req.ID))
+			}
+		}
+		return resourceHandler.ReadResource(ctx, &resourceReq)
 
 	case protocol.MethodPromptsList:
-		if s.registry.HasPromptHandler() {
-			return s.registry.GetPromptHandler().ListPrompts(ctx)
+		if h := s.registry.GetPromptHandler(); h != nil {
+			return h.ListPrompts(ctx)
+		}
+		if s.options.StrictCapabilities {
+			return nil, notSupported("prompts")
 		}
 		return &protocol.ListPromptsResponse{Prompts: []protocol.Prompt{}}, nil
 
 	case protocol.MethodPromptsGet:
-		if !s.registry.HasPromptHandler() {
+		promptHandler := s.registry.GetPromptHandler()
+		if promptHandler == nil {
 			return nil, fmt.Errorf("prompts not supported")
 		}
 		var promptReq protocol.GetPromptRequest
-		if err := json.Unmarshal(req.Params, &promptReq); err != nil {
+		if err := s.decodeParams(req.Params, &promptReq); err != nil {
 			return nil, fmt.Errorf("invalid prompt parameters: %w", err)
 		}
-		return s.registry.GetPromptHandler().GetPrompt(ctx, &promptReq)
+		return promptHandler.GetPrompt(ctx, &promptReq)
 
 	default:
+		if h := s.registry.GetHandler(); h != nil {
+			return h.HandleRequest(ctx, req.Method, req.Params)
+		}
 		return nil, fmt.Errorf("unknown method: %s", req.Method)
 	}
 }
 
+// resourceChunkEmitter returns the emit func handed to a
+// StreamingResourceHandler: each call sends one
+// notifications/resources/chunk tagged with the read request's id.
+func (s *Server) resourceChunkEmitter(ctx context.Context, id interface{}) func(protocol.ResourceContent) error {
+	var mu sync.Mutex
+	index := 0
+	return func(content protocol.ResourceContent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := s.notify(ctx, protocol.NotificationResourceChunk, protocol.ResourceChunkParams{
+			RequestID: id,
+			Index:     index,
+			Content:   content,
+		}); err != nil {
+			return err
+		}
+		index++
+		return nil
+	}
+}
+
+// isNotificationMethod reports whether method lives in the notifications/
+// namespace, which never receives a response.
+func isNotificationMethod(method string) bool {
+	return strings.HasPrefix(method, "notifications/")
+}
+
 // handleNotification dispatches server-directed notifications. Notifications
 // never receive a response per JSON-RPC semantics.
 func (s *Server) handleNotification(req *protocol.Request) {
+	if s.isShutdownMethod(req.Method) {
+		log.Printf("Shutdown notification %s received", req.Method)
+		return
+	}
+
 	switch req.Method {
 	case protocol.MethodInitialized, protocol.NotificationInitialized:
+		s.setReady(req.Context(), true)
 		log.Printf("Server initialized successfully")
 
 	case protocol.NotificationCancelled:
@@ -263,7 +989,9 @@ func (s *Server) handleNotification(req *protocol.Request) {
 			log.Printf("Ignoring malformed notifications/cancelled: %v", err)
 			return
 		}
-		if s.tracker.cancel(params.RequestID) {
+		// Only the session that sent the request may cancel it
+		session, _ := transport.SessionIDFromContext(req.Context())
+		if s.tracker.cancel(session, params.RequestID) {
 			log.Printf("Request %v cancelled by client (reason: %q)", params.RequestID, params.Reason)
 		} else {
 			// No matching in-flight request; either it already completed or
@@ -277,47 +1005,149 @@ func (s *Server) handleNotification(req *protocol.Request) {
 }
 
 // handleInitialize processes initialization requests
-func (s *Server) handleInitialize(_ context.Context, params json.RawMessage) (*protocol.InitializeResponse, error) {
+func (s *Server) handleInitialize(ctx context.Context, params json.RawMessage) (*protocol.InitializeResponse, error) {
 	var initReq protocol.InitializeRequest
-	if err := json.Unmarshal(params, &initReq); err != nil {
+	if err := s.decodeParams(params, &initReq); err != nil {
 		return nil, fmt.Errorf("invalid initialization parameters: %w", err)
 	}
 
 	// Remember the client's capabilities so Server.Elicit (and any future
 	// server→client calls) can refuse politely when the client didn't
 	// advertise the matching capability.
-	s.clientCapsMu.Lock()
 	caps := initReq.Capabilities
-	s.clientCaps = &caps
-	s.clientCapsMu.Unlock()
+	s.setClientCapabilities(ctx, &caps)
+
+	// The session is not ready until the client confirms with
+	// notifications/initialized.
+	s.setReady(ctx, false)
+
+	capabilities := s.serverCapabilities(initReq.Capabilities)
+
+	return &protocol.InitializeResponse{
+		ProtocolVersion: protocol.NegotiateVersion(initReq.ProtocolVersion),
+		ServerInfo: protocol.ServerInfo{
+			Name:       s.options.Name,
+			Title:      s.options.Title,
+			Version:    s.options.Version,
+			Icons:      s.options.Icons,
+			WebsiteURL: s.options.WebsiteURL,
+		},
+		Capabilities: capabilities,
+		Instructions: s.options.Instructions,
+		Meta:         s.options.InitializeMeta,
+	}, nil
+}
+
+// IsReady reports whether the lifecycle handshake has completed: the client
+// sent initialize and then notifications/initialized. On a multi-client
+// transport use IsSessionReady, as each session has its own handshake.
+func (s *Server) IsReady() bool {
+	return s.ready.Load()
+}
+
+// IsSessionReady reports whether session sessionID of a multi-client
+// transport has completed the lifecycle handshake
+func (s *Server) IsSessionReady(sessionID string) bool {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+	return s.readySessions[sessionID]
+}
+
+// readyFor reports whether the client that sent the request handled in ctx
+// has completed the lifecycle handshake
+func (s *Server) readyFor(ctx context.Context) bool {
+	if sessionID, ok := s.handshakeSession(ctx); ok {
+		return s.IsSessionReady(sessionID)
+	}
+	return s.ready.Load()
+}
+
+// setReady records whether the client that sent the request handled in ctx
+// has completed the lifecycle handshake. Sessions the transport no longer
+// has are forgotten along the way.
+func (s *Server) setReady(ctx context.Context, ready bool) {
+	sessionID, ok := s.handshakeSession(ctx)
+	if !ok {
+		s.ready.Store(ready)
+		return
+	}
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+	if !ready {
+		delete(s.readySessions, sessionID)
+		return
+	}
+	if s.readySessions == nil {
+		s.readySessions = make(map[string]bool)
+	}
+	open := s.openSessions()
+	for id := range s.readySessions {
+		if !open[id] {
+			delete(s.readySessions, id)
+		}
+	}
+	s.readySessions[sessionID] = true
+}
 
+// openSessions returns the set of sessions the transport has open
+func (s *Server) openSessions() map[string]bool {
+	open := make(map[string]bool)
+	for _, id := range s.Sessions() {
+		open[id] = true
+	}
+	return open
+}
+
+// handshakeSession returns the session the request handled in ctx belongs
+// to when the transport runs one handshake per session
+func (s *Server) handshakeSession(ctx context.Context) (string, bool) {
+	sessionID, ok := transport.SessionIDFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	_, isManager := s.transport.(transport.SessionManager)
+	return sessionID, isManager
+}
+
+// serverCapabilities builds the capabilities advertised to a client with the
+// given declared capabilities. Each handler type present contributes its
+// capability; the optional flags from Options.Capabilities are layered on top
+// and dropped again if the client cannot receive notifications, since every
+// such flag promises a notification.
+func (s *Server) serverCapabilities(client protocol.ClientCapabilities) protocol.Capabilities {
 	capabilities := protocol.Capabilities{
 		// Logging is always advertised: the server may or may not emit
 		// notifications/message, but supporting logging/setLevel costs
 		// nothing, so every server exposes the capability.
 		Logging: &protocol.LoggingInfo{},
 	}
-	if s.registry.HasToolHandler() {
+	flags := s.options.Capabilities
+	if flags == nil || !client.AcceptsNotifications() {
+		flags = &protocol.Capabilities{}
+	}
+	if s.registry.HasToolHandler() || s.builtinTools != nil {
 		capabilities.Tools = &protocol.ToolsInfo{}
+		if flags.Tools != nil {
+			*capabilities.Tools = *flags.Tools
+		}
 	}
 	if s.registry.HasResourceHandler() {
 		capabilities.Resources = &protocol.ResourcesInfo{}
+		if flags.Resources != nil {
+			*capabilities.Resources = *flags.Resources
+		}
 	}
 	if s.registry.HasPromptHandler() {
 		capabilities.Prompts = &protocol.PromptsInfo{}
+		if flags.Prompts != nil {
+			*capabilities.Prompts = *flags.Prompts
+		}
+		// Prompts added via AddPrompt announce their changes themselves
+		if _, dynamic := s.registry.GetPromptHandler().(*handler.PromptRouter); dynamic && client.AcceptsNotifications() {
+			capabilities.Prompts.ListChanged = true
+		}
 	}
-
-	return &protocol.InitializeResponse{
-		ProtocolVersion: protocol.NegotiateVersion(initReq.ProtocolVersion),
-		ServerInfo: protocol.ServerInfo{
-			Name:       s.options.Name,
-			Title:      s.options.Title,
-			Version:    s.options.Version,
-			Icons:      s.options.Icons,
-			WebsiteURL: s.options.WebsiteURL,
-		},
-		Capabilities: capabilities,
-	}, nil
+	return capabilities
 }
 
 // SendNotification sends a server-initiated notification to the client.
@@ -352,33 +1182,51 @@ func (s *Server) LogMessage(level, loggerName string, data interface{}) error {
 	})
 }
 
-// sendResponse sends a successful response
-func (s *Server) sendResponse(id interface{}, result interface{}) {
-	response := &protocol.Response{
+// resultResponse builds a successful response
+func resultResponse(id interface{}, result interface{}) *protocol.Response {
+	return &protocol.Response{
 		JSONRPC: "2.0",
 		ID:      id,
 		Result:  result,
 	}
+}
 
-	log.Printf("MCP server response:\n%v\n", truncatedJSON(response))
-	if err := s.transport.Send(response); err != nil {
-		log.Printf("Error sending response: %v", err)
-	}
+// errorResponse builds an error response
+func errorResponse(id interface{}, code int, message string) *protocol.Response {
+	return rpcErrorResponse(id, &protocol.Error{
+		Code:    code,
+		Message: message,
+	})
 }
 
-// sendError sends an error response
-func (s *Server) sendError(id interface{}, code int, message string) {
-	response := &protocol.Response{
+// rpcErrorResponse builds a response carrying a prepared JSON-RPC error object
+func rpcErrorResponse(id interface{}, rpcErr *protocol.Error) *protocol.Response {
+	return &protocol.Response{
 		JSONRPC: "2.0",
 		ID:      id,
-		Error: &protocol.Error{
-			Code:    code,
-			Message: message,
-		},
+		Error:   rpcErr,
 	}
+}
+
+// logs reports whether the request log records messages at level, per
+// Options.LogLevel
+func (s *Server) logs(level string) bool {
+	return protocol.LogLevelRank(level) >= protocol.LogLevelRank(s.options.LogLevel)
+}
 
-	log.Printf("MCP server error response:\n%v\n", truncatedJSON(response))
-	if err := s.transport.Send(response); err != nil {
-		log.Printf("Error sending error response: %v", err)
+// writeResponse sends a single response to the client
+func (s *Server) writeResponse(response *protocol.Response) {
+	if s.logs(protocol.LogLevelDebug) {
+		if response.Error != nil {
+			log.Printf("MCP server error response:\n%v\n", s.logJSON(response))
+		} else {
+			log.Printf("MCP server response:\n%v\n", s.logJSON(response))
+		}
+	}
+	if err := s.sendWithRetry(func() error { return s.transport.Send(response) }); err != nil {
+		log.Printf("Error sending response: %v", err)
+		if s.options.SendErrorHandler != nil {
+			s.options.SendErrorHandler(response, err)
+		}
 	}
 }
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -22,6 +24,10 @@ type mockTransport struct {
 	responses       []*protocol.Response
 	notifications   []*protocol.Notification
 	outboundRequest []*protocol.Request
+
+	// order records every outbound message in send order as "response" or
+	// the notification method, for tests that assert sequencing.
+	order []string
 }
 
 func newMockTransport() *mockTransport {
@@ -50,6 +56,7 @@ func (t *mockTransport) Send(response *protocol.Response) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	t.responses = append(t.responses, response)
+	t.order = append(t.order, "response")
 	return nil
 }
 
@@ -57,6 +64,7 @@ func (t *mockTransport) SendNotification(notification *protocol.Notification) er
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	t.notifications = append(t.notifications, notification)
+	t.order = append(t.order, notification.Method)
 	return nil
 }
 
@@ -103,6 +111,15 @@ func (t *mockTransport) responsesSnapshot() []*protocol.Response {
 	return out
 }
 
+// orderSnapshot returns a copy of the outbound message order.
+func (t *mockTransport) orderSnapshot() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]string, len(t.order))
+	copy(out, t.order)
+	return out
+}
+
 // responseCount returns the current number of responses captured.
 func (t *mockTransport) responseCount() int {
 	t.mu.Lock()
@@ -139,6 +156,30 @@ func (h *mockToolHandler) CallTool(ctx context.Context, req *protocol.CallToolRe
 	return h.result, nil
 }
 
+type mockResourceHandler struct {
+	resources []protocol.Resource
+}
+
+func (h *mockResourceHandler) ListResources(ctx context.Context) (*protocol.ListResourcesResponse, error) {
+	return &protocol.ListResourcesResponse{Resources: h.resources}, nil
+}
+
+func (h *mockResourceHandler) ReadResource(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResponse, error) {
+	return &protocol.ReadResourceResponse{}, nil
+}
+
+type mockPromptHandler struct {
+	prompts []protocol.Prompt
+}
+
+func (h *mockPromptHandler) ListPrompts(ctx context.Context) (*protocol.ListPromptsResponse, error) {
+	return &protocol.ListPromptsResponse{Prompts: h.prompts}, nil
+}
+
+func (h *mockPromptHandler) GetPrompt(ctx context.Context, req *protocol.GetPromptRequest) (*protocol.GetPromptResponse, error) {
+	return &protocol.GetPromptResponse{}, nil
+}
+
 func TestServerNoResponseForNotification(t *testing.T) {
 	mockTransport := newMockTransport()
 	registry := handler.NewHandlerRegistry()
@@ -498,3 +539,361 @@ func TestPingReturnsEmptyResult(t *testing.T) {
 		t.Errorf("ping result = %s, want {}", string(raw))
 	}
 }
+
+func TestStaleRequestsAreRefused(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := New(Options{
+		Name:          "test-server",
+		Version:       "1.0.0",
+		Registry:      handler.NewHandlerRegistry(),
+		Transport:     mockTransport,
+		MaxRequestAge: 50 * time.Millisecond,
+	})
+
+	ping := func(id int) *protocol.Request {
+		return &protocol.Request{JSONRPC: "2.0", ID: id, Method: protocol.MethodPing}
+	}
+
+	// Simulate a request that sat in a queue well past the max age.
+	srv.handleRequest(context.Background(), ping(1), time.Now().Add(-time.Second))
+	if mockTransport.responseCount() != 1 {
+		t.Fatalf("stale request got %d responses; want 1", mockTransport.responseCount())
+	}
+	if resp := mockTransport.responseAt(0); resp.ID != 1 || resp.Error == nil || resp.Error.Code != protocol.RequestTimeout {
+		t.Errorf("stale response = %+v, want RequestTimeout for id 1", resp)
+	}
+
+	// A fresh request is still served.
+	srv.handleRequest(context.Background(), ping(2), time.Now())
+	if mockTransport.responseCount() != 2 {
+		t.Fatalf("got %d responses; want 2", mockTransport.responseCount())
+	}
+	if resp := mockTransport.responseAt(1); resp.ID != 2 || resp.Error != nil {
+		t.Errorf("fresh response = %+v, want the ping result", resp)
+	}
+}
+
+func TestInitializeInstructions(t *testing.T) {
+	tests := []struct {
+		name         string
+		instructions string
+	}{
+		{name: "set", instructions: "Call search before fetch."},
+		{name: "empty", instructions: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTransport := newMockTransport()
+			srv := New(Options{
+				Name:         "test-server",
+				Version:      "1.0.0",
+				Registry:     handler.NewHandlerRegistry(),
+				Transport:    mockTransport,
+				Instructions: tt.instructions,
+			})
+			go srv.Run()
+
+			mockTransport.requests <- &protocol.Request{
+				JSONRPC: "2.0",
+				ID:      1,
+				Method:  protocol.MethodInitialize,
+				Params:  json.RawMessage(`{"protocolVersion":"2025-11-25","capabilities":{}}`),
+			}
+
+			time.Sleep(100 * time.Millisecond)
+
+			if mockTransport.responseCount() == 0 {
+				t.Fatal("no response received")
+			}
+			raw, err := json.Marshal(mockTransport.responseAt(0).Result)
+			if err != nil {
+				t.Fatalf("marshal result: %v", err)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(raw, &got); err != nil {
+				t.Fatalf("unmarshal result: %v", err)
+			}
+
+			value, present := got["instructions"]
+			if tt.instructions == "" {
+				if present {
+					t.Errorf("instructions = %v, want field omitted", value)
+				}
+				return
+			}
+			if value != tt.instructions {
+				t.Errorf("instructions = %v, want %q", value, tt.instructions)
+			}
+		})
+	}
+}
+
+// TestDynamicHandlerRegistration swaps the tool handler while tools/list and
+// tools/call requests are being dispatched. Run with -race to catch
+// unsynchronized registry access.
+func TestDynamicHandlerRegistration(t *testing.T) {
+	mockTransport := newMockTransport()
+	registry := handler.NewHandlerRegistry()
+	srv := New(Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Registry:  registry,
+		Transport: mockTransport,
+	})
+
+	tool := &mockToolHandler{result: &protocol.CallToolResponse{}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			registry.RegisterToolHandler(tool)
+			registry.UnregisterToolHandler()
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		method := protocol.MethodToolsList
+		if i%2 == 1 {
+			method = protocol.MethodToolsCall
+		}
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0",
+			ID:      i,
+			Method:  method,
+			Params:  json.RawMessage(`{"name":"x"}`),
+		}, time.Now())
+	}
+	<-done
+
+	if got := mockTransport.responseCount(); got != 200 {
+		t.Errorf("responses = %d, want 200", got)
+	}
+}
+
+func TestValidateIDRejectsRequests(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := New(Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Registry:  handler.NewHandlerRegistry(),
+		Transport: mockTransport,
+		ValidateID: func(id interface{}) error {
+			switch v := id.(type) {
+			case string:
+				return errors.New("string ids are not allowed")
+			case json.Number:
+				if n, err := v.Int64(); err != nil || n < 0 {
+					return errors.New("numeric ids must be non-negative integers")
+				}
+				return nil
+			}
+			return errors.New("unexpected id type")
+		},
+	})
+
+	// Decode real requests so the validator sees the id types a transport
+	// actually produces
+	for _, raw := range []string{
+		`{"jsonrpc":"2.0","id":"abc","method":"ping"}`,
+		`{"jsonrpc":"2.0","id":7,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":-1,"method":"ping"}`,
+	} {
+		var req protocol.Request
+		if err := json.Unmarshal([]byte(raw), &req); err != nil {
+			t.Fatalf("decode %s: %v", raw, err)
+		}
+		srv.handleRequest(context.Background(), &req, time.Now())
+	}
+
+	if mockTransport.responseCount() != 3 {
+		t.Fatalf("responses = %d, want 3", mockTransport.responseCount())
+	}
+	rejected := mockTransport.responseAt(0)
+	if rejected.Error == nil || rejected.Error.Code != protocol.InvalidRequest {
+		t.Errorf("string id response = %+v, want InvalidRequest error", rejected.Error)
+	}
+	if rejected.ID != "abc" {
+		t.Errorf("rejected response ID = %v, want abc", rejected.ID)
+	}
+	if accepted := mockTransport.responseAt(1); accepted.Error != nil {
+		t.Errorf("numeric id rejected: %v", accepted.Error)
+	}
+	if negative := mockTransport.responseAt(2); negative.Error == nil || negative.Error.Code != protocol.InvalidRequest {
+		t.Errorf("negative id response = %+v, want InvalidRequest error", negative.Error)
+	}
+}
+
+func TestReadinessTransitions(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := New(Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Registry:  handler.NewHandlerRegistry(),
+		Transport: mockTransport,
+	})
+
+	if srv.IsReady() {
+		t.Fatal("server should not be ready before initialize")
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodInitialize, Params: json.RawMessage(`{}`),
+	}, time.Now())
+	if srv.IsReady() {
+		t.Fatal("server should not be ready until notifications/initialized")
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", Method: protocol.NotificationInitialized,
+	}, time.Now())
+	if !srv.IsReady() {
+		t.Fatal("server should be ready after notifications/initialized")
+	}
+
+	// A fresh initialize restarts the handshake.
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 2, Method: protocol.MethodInitialize, Params: json.RawMessage(`{}`),
+	}, time.Now())
+	if srv.IsReady() {
+		t.Error("re-initialize should reset readiness")
+	}
+}
+
+func TestNoResponseForNotificationsNamespace(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := New(Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Registry:  handler.NewHandlerRegistry(),
+		Transport: mockTransport,
+	})
+
+	for i, method := range []string{
+		protocol.NotificationCancelled,
+		"notifications/something_unknown",
+	} {
+		// Both with and without an id: neither shape may be answered.
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0", Method: method, Params: json.RawMessage(`{"requestId":99}`),
+		}, time.Now())
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0", ID: i, Method: method, Params: json.RawMessage(`{"requestId":99}`),
+		}, time.Now())
+	}
+
+	if got := mockTransport.responseCount(); got != 0 {
+		t.Errorf("server sent %d responses to notifications; want none", got)
+	}
+}
+
+func TestNullAndAbsentRequestID(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := New(Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Registry:  handler.NewHandlerRegistry(),
+		Transport: mockTransport,
+	})
+
+	decode := func(raw string) *protocol.Request {
+		var req protocol.Request
+		if err := json.Unmarshal([]byte(raw), &req); err != nil {
+			t.Fatalf("unmarshal %s: %v", raw, err)
+		}
+		return &req
+	}
+
+	// No id member: a notification, never answered.
+	srv.handleRequest(context.Background(), decode(`{"jsonrpc":"2.0","method":"ping"}`), time.Now())
+	if got := mockTransport.responseCount(); got != 0 {
+		t.Fatalf("absent id was answered; got %d responses", got)
+	}
+
+	// Explicit null id: an invalid request, answered with a null id.
+	srv.handleRequest(context.Background(), decode(`{"jsonrpc":"2.0","id":null,"method":"ping"}`), time.Now())
+	if got := mockTransport.responseCount(); got != 1 {
+		t.Fatalf("null id should get exactly one response, got %d", got)
+	}
+	resp := mockTransport.responseAt(0)
+	if resp.Error == nil || resp.Error.Code != protocol.InvalidRequest {
+		t.Fatalf("expected InvalidRequest, got %+v", resp)
+	}
+	out, _ := json.Marshal(resp)
+	var decoded map[string]interface{}
+	_ = json.Unmarshal(out, &decoded)
+	if id, ok := decoded["id"]; !ok || id != nil {
+		t.Errorf("error response must carry \"id\": null, got %s", out)
+	}
+}
+
+func TestStrictCapabilities(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		strict  bool
+		tools   bool
+		wantErr bool
+	}{
+		{"lenient without handler", false, false, false},
+		{"strict without handler", true, false, true},
+		{"strict with handler", true, true, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := handler.NewHandlerRegistry()
+			if tt.tools {
+				registry.RegisterToolHandler(&mockToolHandler{})
+			}
+			transp := newMockTransport()
+			srv := New(Options{
+				Registry:           registry,
+				Transport:          transp,
+				StrictCapabilities: tt.strict,
+			})
+
+			srv.handleRequest(context.Background(), &protocol.Request{
+				JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsList,
+			}, time.Now())
+
+			resp := transp.responseAt(0)
+			if tt.wantErr {
+				if resp.Error == nil || resp.Error.Code != protocol.MethodNotFound {
+					t.Fatalf("expected MethodNotFound, got %+v", resp)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %v", resp.Error)
+			}
+			if _, ok := resp.Result.(*protocol.ListToolsResponse); !ok {
+				t.Errorf("result = %T, want *protocol.ListToolsResponse", resp.Result)
+			}
+		})
+	}
+}
+
+func TestResponseEchoesIntegerID(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := New(Options{
+		Name:      "test-server",
+		Version:   "1.0.0",
+		Registry:  handler.NewHandlerRegistry(),
+		Transport: mockTransport,
+	})
+
+	var req protocol.Request
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":42,"method":"ping"}`), &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	srv.handleRequest(context.Background(), &req, time.Now())
+
+	if mockTransport.responseCount() != 1 {
+		t.Fatalf("responses = %d, want 1", mockTransport.responseCount())
+	}
+	out, err := json.Marshal(mockTransport.responseAt(0))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !bytes.Contains(out, []byte(`"id":42,`)) {
+		t.Errorf("response = %s, want id echoed as 42", out)
+	}
+}
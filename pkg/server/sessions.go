@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// Sessions returns the IDs of the clients connected through a multi-client
+// transport (see transport.SessionManager), or nil for a single-peer one
+func (s *Server) Sessions() []string {
+	if sm, ok := s.transport.(transport.SessionManager); ok {
+		return sm.Sessions()
+	}
+	return nil
+}
+
+// SendNotificationTo sends a notification to one session of a multi-client
+// transport. Use SendNotification to reach every client.
+func (s *Server) SendNotificationTo(sessionID, method string, params interface{}) error {
+	sm, ok := s.transport.(transport.SessionManager)
+	if !ok {
+		return fmt.Errorf("transport does not support sessions")
+	}
+	notification, err := protocol.NewNotification(method, params)
+	if err != nil {
+		return fmt.Errorf("failed to build notification: %w", err)
+	}
+	return sm.SendNotificationTo(sessionID, notification)
+}
+
+// notify sends a notification about the request handled in ctx: only to
+// its session when the transport has sessions, otherwise to the client
+func (s *Server) notify(ctx context.Context, method string, params interface{}) error {
+	if sessionID, ok := transport.SessionIDFromContext(ctx); ok {
+		if _, isManager := s.transport.(transport.SessionManager); isManager {
+			return s.SendNotificationTo(sessionID, method, params)
+		}
+	}
+	return s.SendNotification(method, params)
+}
+
+// sendRequest sends a server-initiated request on behalf of the request
+// handled in ctx, targeting its session as notify does
+func (s *Server) sendRequest(ctx context.Context, req *protocol.Request) error {
+	if sessionID, ok := transport.SessionIDFromContext(ctx); ok {
+		if sm, isManager := s.transport.(transport.SessionManager); isManager {
+			return sm.SendRequestTo(sessionID, req)
+		}
+	}
+	return s.transport.SendRequest(req)
+}
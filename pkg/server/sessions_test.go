@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/async"
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// sessionTransport is a mock multi-client transport recording what each
+// session was sent
+type sessionTransport struct {
+	*mockTransport
+	mu   sync.Mutex
+	sent map[string][]*protocol.Notification
+}
+
+func (t *sessionTransport) Sessions() []string {
+	return []string{"a", "b"}
+}
+
+func (t *sessionTransport) SendNotificationTo(sessionID string, n *protocol.Notification) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sent[sessionID] = append(t.sent[sessionID], n)
+	return nil
+}
+
+func (t *sessionTransport) SendRequestTo(sessionID string, r *protocol.Request) error {
+	return nil
+}
+
+func TestSessionsIsolateNotificationsAndResponses(t *testing.T) {
+	transp := &sessionTransport{mockTransport: newMockTransport(), sent: make(map[string][]*protocol.Notification)}
+	var srv *Server
+	tools := handler.NewToolRouter()
+	tools.Register(protocol.Tool{Name: "work"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		session, _ := transport.SessionIDFromContext(ctx)
+		if err := srv.Progress(ctx, 1, nil, "working for "+session); err != nil {
+			return nil, err
+		}
+		return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: session}}}, nil
+	})
+	srv = Builder().Transport(transp).Tool(tools).Build()
+
+	if got := srv.Sessions(); len(got) != 2 {
+		t.Errorf("Sessions() = %v, want the transport's sessions", got)
+	}
+
+	for _, session := range []string{"a", "b"} {
+		req := &protocol.Request{
+			JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+			Params: json.RawMessage(`{"name":"work","arguments":{},"_meta":{"progressToken":"p"}}`),
+		}
+		srv.handleRequest(context.Background(), req.WithContext(transport.WithSessionID(context.Background(), session)), time.Now())
+	}
+
+	for _, session := range []string{"a", "b"} {
+		sent := transp.sent[session]
+		if len(sent) != 1 || sent[0].Method != protocol.NotificationProgress {
+			t.Fatalf("session %s got %+v, want one progress notification", session, sent)
+		}
+		var params protocol.ProgressParams
+		json.Unmarshal(sent[0].Params, &params)
+		if params.Message != "working for "+session {
+			t.Errorf("session %s got progress %q", session, params.Message)
+		}
+	}
+	if n := transp.notificationCount(); n != 0 {
+		t.Errorf("%d notifications were broadcast, want none", n)
+	}
+
+	for i, want := range []string{"a", "b"} {
+		resp := transp.responseAt(i)
+		if got, _ := transport.SessionIDFromContext(resp.Context()); got != want {
+			t.Errorf("response %d routed to session %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestSendNotificationToWithoutSessions(t *testing.T) {
+	srv := Builder().Transport(newMockTransport()).Build()
+	if srv.Sessions() != nil {
+		t.Error("Sessions() should be nil for a single-peer transport")
+	}
+	if err := srv.SendNotificationTo("a", "notifications/message", nil); err == nil {
+		t.Error("expected an error from a transport without sessions")
+	}
+}
+
+func TestCancellationIsScopedToSession(t *testing.T) {
+	transp := &sessionTransport{mockTransport: newMockTransport(), sent: make(map[string][]*protocol.Notification)}
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	tools := handler.NewToolRouter()
+	tools.Register(protocol.Tool{Name: "work"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		session, _ := transport.SessionIDFromContext(ctx)
+		started <- session
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-release:
+		}
+		return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: session}}}, nil
+	})
+	srv := Builder().Transport(transp).Tool(tools).Build()
+
+	send := func(session string, req *protocol.Request) {
+		srv.handleRequest(context.Background(), req.WithContext(transport.WithSessionID(context.Background(), session)), time.Now())
+	}
+
+	// Both sessions have a request with id 1 in flight at once
+	var wg sync.WaitGroup
+	for _, session := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(session string) {
+			defer wg.Done()
+			send(session, &protocol.Request{
+				JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+				Params: json.RawMessage(`{"name":"work","arguments":{}}`),
+			})
+		}(session)
+	}
+	<-started
+	<-started
+
+	send("a", &protocol.Request{
+		JSONRPC: "2.0", Method: protocol.NotificationCancelled,
+		Params: json.RawMessage(`{"requestId":1}`),
+	})
+	close(release)
+	wg.Wait()
+
+	if n := transp.responseCount(); n != 1 {
+		t.Fatalf("got %d responses, want only session b's", n)
+	}
+	resp := transp.responseAt(0)
+	if got, _ := transport.SessionIDFromContext(resp.Context()); got != "b" || resp.Error != nil {
+		t.Errorf("response = %+v for session %q, want session b's result", resp, got)
+	}
+}
+
+func TestClosedSessionCancelsItsOperations(t *testing.T) {
+	executor := async.NewExecutor(async.ExecutorConfig{
+		DefaultTimeout:  10 * time.Millisecond,
+		MaxLifetime:     10 * time.Second,
+		RetentionPeriod: 10 * time.Second,
+		CleanupInterval: time.Second,
+	})
+	defer executor.Stop()
+
+	// render starts an operation that runs until cancelled, owned by the
+	// caller's session
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "render"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		result, err := executor.Execute(ctx, func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, async.ExecuteOptions{Type: "render"})
+		if err != nil {
+			return nil, err
+		}
+		return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: result.OperationID}}}, nil
+	})
+
+	httpTransport := transport.NewHTTPTransport(transport.HTTPOptions{SessionIdleTimeout: 100 * time.Millisecond})
+	ts := httptest.NewServer(httpTransport)
+	defer ts.Close()
+	srv := Builder().Transport(httpTransport).Tool(router).With(WithAsyncExecutor(executor)).Build()
+	go srv.Run()
+	defer srv.Shutdown()
+
+	post := func(sessionID, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			req.Header.Set(transport.SessionHeader, sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST: %v", err)
+		}
+		return resp
+	}
+	resp := post("", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","clientInfo":{"name":"c","version":"1"},"capabilities":{}}}`)
+	resp.Body.Close()
+	session := resp.Header.Get(transport.SessionHeader)
+	if session == "" {
+		t.Fatal("initialize response carries no session ID")
+	}
+	resp = post(session, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"render","arguments":{}}}`)
+	resp.Body.Close()
+
+	ops := executor.ListOperationDetails()
+	if len(ops) != 1 || ops[0].Owner != session || ops[0].Status != async.StatusRunning {
+		t.Fatalf("operations = %+v, want one running operation owned by %s", ops, session)
+	}
+
+	// Let the session go idle until the transport reaps it
+	deadline := time.Now().Add(2 * time.Second)
+	for executor.ListOperationDetails()[0].Status == async.StatusRunning {
+		if time.Now().After(deadline) {
+			t.Fatal("operation of the reaped session is still running")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := executor.ListOperationDetails()[0].Status; status != async.StatusCancelled {
+		t.Errorf("operation status = %s, want %s", status, async.StatusCancelled)
+	}
+	if caps := srv.clientCapabilities(transport.WithSessionID(context.Background(), session)); caps != nil {
+		t.Errorf("capabilities of closed session %s still kept: %+v", session, caps)
+	}
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// runServer starts srv.Run in the background and returns a channel that
+// receives its result.
+func runServer(srv *Server) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- srv.Run() }()
+	return done
+}
+
+func TestShutdownNotificationStopsRun(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).With(WithShutdownMethod("notifications/exit")).Build()
+	done := runServer(srv)
+
+	mockTransport.requests <- &protocol.Request{JSONRPC: "2.0", Method: "notifications/exit"}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run returned %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the shutdown notification")
+	}
+	if n := mockTransport.responseCount(); n != 0 {
+		t.Errorf("shutdown notification got %d responses, want none", n)
+	}
+}
+
+func TestShutdownRequestIsAcknowledged(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).With(WithShutdownMethod("shutdown")).Build()
+	done := runServer(srv)
+
+	mockTransport.requests <- &protocol.Request{JSONRPC: "2.0", ID: 1, Method: "shutdown"}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the shutdown request")
+	}
+	if mockTransport.responseCount() != 1 {
+		t.Fatalf("responses = %d, want 1", mockTransport.responseCount())
+	}
+	if resp := mockTransport.responseAt(0); resp.Error != nil || resp.ID != 1 {
+		t.Errorf("shutdown response = %+v, want an empty result for id 1", resp)
+	}
+}
+
+func TestShutdownMethodUnsetIsUnknown(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).Build()
+	done := runServer(srv)
+
+	mockTransport.requests <- &protocol.Request{JSONRPC: "2.0", Method: "notifications/exit"}
+	select {
+	case <-done:
+		t.Fatal("Run returned although no shutdown method is configured")
+	case <-time.After(50 * time.Millisecond):
+	}
+	srv.Shutdown()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after Shutdown")
+	}
+}
+
+// shutDown reports whether srv has been told to shut down
+func shutDown(srv *Server) bool {
+	select {
+	case <-srv.shutdown:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestShutdownRequestRejectedDoesNotShutDown(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		// before is sent first, e.g. to use up the rate limit
+		before *protocol.Request
+	}{
+		{name: "not initialized", opts: []Option{WithRequireInitialize()}},
+		{name: "invalid id", opts: []Option{WithIDValidator(func(interface{}) error { return errors.New("bad id") })}},
+		{
+			name:   "rate limited",
+			opts:   []Option{WithRateLimit(0.001, 1)},
+			before: &protocol.Request{JSONRPC: "2.0", ID: 0, Method: protocol.MethodPing},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTransport := newMockTransport()
+			srv := Builder().Transport(mockTransport).With(append(tt.opts, WithShutdownMethod("shutdown"))...).Build()
+
+			if tt.before != nil {
+				srv.handleRequest(context.Background(), tt.before, time.Now())
+			}
+			srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", ID: 1, Method: "shutdown"}, time.Now())
+
+			if resp := mockTransport.responseAt(mockTransport.responseCount() - 1); resp.Error == nil {
+				t.Fatalf("response = %+v, want an error", resp)
+			}
+			if shutDown(srv) {
+				t.Error("a rejected shutdown request shut the server down")
+			}
+		})
+	}
+}
+
+func TestShutdownInBatchOnlyWhenAccepted(t *testing.T) {
+	transp := newBatchMockTransport()
+	srv := Builder().Transport(transp).With(WithShutdownMethod("shutdown"), WithRequireInitialize()).Build()
+
+	shutdown := &protocol.Request{JSONRPC: "2.0", ID: 1, Method: "shutdown"}
+	srv.handleBatch(context.Background(), transp, []*protocol.Request{shutdown}, time.Now())
+	if shutDown(srv) {
+		t.Fatal("a shutdown rejected before initialize shut the server down")
+	}
+
+	srv.handleRequest(context.Background(), &protocol.Request{JSONRPC: "2.0", Method: protocol.NotificationInitialized}, time.Now())
+	srv.handleBatch(context.Background(), transp, []*protocol.Request{shutdown}, time.Now())
+	if !shutDown(srv) {
+		t.Error("an accepted shutdown in a batch should shut the server down")
+	}
+}
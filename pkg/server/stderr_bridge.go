@@ -0,0 +1,84 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// stderrLoggerName is the logger name attached to bridged log entries
+const stderrLoggerName = "stderr"
+
+// StderrBridge is an io.Writer that forwards each line written to it to the
+// client as a notifications/message log entry, so diagnostics that would
+// otherwise only reach the server's stderr become visible to the client.
+// Writes are also passed through to the underlying writer, if any. Entries
+// respect the client's logging/setLevel threshold like any LogMessage.
+//
+// A typical use is log.SetOutput(srv.NewStderrBridge(os.Stderr, "info")), or
+// assigning a bridge to exec.Cmd.Stderr for a wrapped tool. It is safe for
+// concurrent use.
+type StderrBridge struct {
+	server *Server
+	out    io.Writer
+	level  string
+
+	mu      sync.Mutex
+	partial []byte
+}
+
+// NewStderrBridge returns a bridge that emits log entries at level and
+// copies every write to out. out may be nil to forward to the client only.
+func (s *Server) NewStderrBridge(out io.Writer, level string) *StderrBridge {
+	return &StderrBridge{server: s, out: out, level: level}
+}
+
+// Write passes p through to the underlying writer and emits a log entry for
+// each complete line. A trailing partial line is held until its newline
+// arrives or Flush is called. Failing to notify the client never fails the
+// write.
+func (b *StderrBridge) Write(p []byte) (int, error) {
+	if b.out != nil {
+		if n, err := b.out.Write(p); err != nil {
+			return n, err
+		}
+	}
+
+	b.mu.Lock()
+	b.partial = append(b.partial, p...)
+	var lines []string
+	for {
+		i := bytes.IndexByte(b.partial, '\n')
+		if i < 0 {
+			break
+		}
+		lines = append(lines, string(b.partial[:i]))
+		b.partial = b.partial[i+1:]
+	}
+	b.mu.Unlock()
+
+	// Emit outside the lock so a transport that itself logs cannot deadlock
+	for _, line := range lines {
+		b.emit(line)
+	}
+	return len(p), nil
+}
+
+// Flush emits any buffered partial line
+func (b *StderrBridge) Flush() {
+	b.mu.Lock()
+	line := string(b.partial)
+	b.partial = nil
+	b.mu.Unlock()
+	b.emit(line)
+}
+
+// emit sends line as a log entry, skipping blank lines
+func (b *StderrBridge) emit(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	_ = b.server.LogMessage(b.level, stderrLoggerName, line)
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestStderrBridgeEmitsLogNotifications(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).Build()
+
+	var local bytes.Buffer
+	bridge := srv.NewStderrBridge(&local, protocol.LogLevelWarning)
+
+	fmt.Fprint(bridge, "disk nearly full\r\nretrying ")
+	if got := mockTransport.notificationCount(); got != 1 {
+		t.Fatalf("notifications after one complete line = %d, want 1", got)
+	}
+	fmt.Fprint(bridge, "upload\n\n")
+	bridge.Flush()
+
+	if local.String() != "disk nearly full\r\nretrying upload\n\n" {
+		t.Errorf("underlying writer got %q, want every byte passed through", local.String())
+	}
+
+	mockTransport.mu.Lock()
+	notifications := append([]*protocol.Notification(nil), mockTransport.notifications...)
+	mockTransport.mu.Unlock()
+
+	want := []string{"disk nearly full", "retrying upload"}
+	if len(notifications) != len(want) {
+		t.Fatalf("got %d notifications, want %d", len(notifications), len(want))
+	}
+	for i, n := range notifications {
+		if n.Method != protocol.NotificationMessage {
+			t.Errorf("method = %q, want %q", n.Method, protocol.NotificationMessage)
+		}
+		var params protocol.LogMessageParams
+		if err := json.Unmarshal(n.Params, &params); err != nil {
+			t.Fatalf("unmarshal params: %v", err)
+		}
+		if params.Level != protocol.LogLevelWarning || params.Logger != "stderr" || params.Data != want[i] {
+			t.Errorf("notification %d = %+v, want warning entry %q from stderr", i, params, want[i])
+		}
+	}
+}
+
+func TestStderrBridgeRespectsThreshold(t *testing.T) {
+	mockTransport := newMockTransport()
+	srv := Builder().Transport(mockTransport).Build()
+
+	// The default threshold is info, so debug lines stay local
+	fmt.Fprintln(srv.NewStderrBridge(nil, protocol.LogLevelDebug), "verbose detail")
+	if got := mockTransport.notificationCount(); got != 0 {
+		t.Errorf("debug line below threshold produced %d notifications", got)
+	}
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// deadlineRecorder captures the ctx deadline each handler method sees.
+type deadlineRecorder struct {
+	deadlines map[string]time.Duration
+}
+
+func (d *deadlineRecorder) record(ctx context.Context, method string) {
+	if deadline, ok := ctx.Deadline(); ok {
+		d.deadlines[method] = time.Until(deadline)
+	} else {
+		d.deadlines[method] = 0
+	}
+}
+
+func (d *deadlineRecorder) ListTools(ctx context.Context) (*protocol.ListToolsResponse, error) {
+	d.record(ctx, protocol.MethodToolsList)
+	return &protocol.ListToolsResponse{}, nil
+}
+
+func (d *deadlineRecorder) CallTool(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+	d.record(ctx, protocol.MethodToolsCall)
+	return &protocol.CallToolResponse{}, nil
+}
+
+func (d *deadlineRecorder) ListResources(ctx context.Context) (*protocol.ListResourcesResponse, error) {
+	d.record(ctx, protocol.MethodResourcesList)
+	return &protocol.ListResourcesResponse{}, nil
+}
+
+func (d *deadlineRecorder) ReadResource(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResponse, error) {
+	d.record(ctx, protocol.MethodResourcesRead)
+	return &protocol.ReadResourceResponse{}, nil
+}
+
+func TestMethodTimeouts(t *testing.T) {
+	rec := &deadlineRecorder{deadlines: map[string]time.Duration{}}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(rec)
+	registry.RegisterResourceHandler(rec)
+
+	srv := New(Options{
+		Name:           "timeout-test-server",
+		Version:        "1.0.0",
+		Registry:       registry,
+		Transport:      newMockTransport(),
+		RequestTimeout: 5 * time.Second,
+		MethodTimeouts: map[string]time.Duration{
+			protocol.MethodToolsCall:     time.Minute,
+			protocol.MethodResourcesRead: time.Second,
+			protocol.MethodResourcesList: 0, // explicit zero disables the default
+		},
+	})
+
+	for i, method := range []string{
+		protocol.MethodToolsCall,
+		protocol.MethodResourcesRead,
+		protocol.MethodToolsList,
+		protocol.MethodResourcesList,
+	} {
+		srv.handleRequest(context.Background(), &protocol.Request{
+			JSONRPC: "2.0",
+			ID:      i,
+			Method:  method,
+			Params:  json.RawMessage(`{}`),
+		}, time.Now())
+	}
+
+	within := func(method string, want time.Duration) {
+		t.Helper()
+		got, ok := rec.deadlines[method]
+		if !ok {
+			t.Fatalf("%s handler was not called", method)
+		}
+		if got > want || got < want-time.Second/2 {
+			t.Errorf("%s deadline = %v, want about %v", method, got, want)
+		}
+	}
+	within(protocol.MethodToolsCall, time.Minute)
+	within(protocol.MethodResourcesRead, time.Second)
+	within(protocol.MethodToolsList, 5*time.Second)
+	if got := rec.deadlines[protocol.MethodResourcesList]; got != 0 {
+		t.Errorf("%s deadline = %v, want none", protocol.MethodResourcesList, got)
+	}
+}
+
+func TestClientTimeoutMetaCancelsHandler(t *testing.T) {
+	tool := &cancellingToolHandler{
+		ctxSeen:     make(chan context.Context, 1),
+		returnDelay: 2 * time.Second,
+	}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(tool)
+	transp := newMockTransport()
+	srv := New(Options{Registry: registry, Transport: transp})
+
+	start := time.Now()
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"slow","_meta":{"timeoutMs":50}}`),
+	}, time.Now())
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("handler ran %v; client timeout should have cancelled it", elapsed)
+	}
+	if transp.responseCount() != 1 {
+		t.Fatalf("responses = %d, want 1", transp.responseCount())
+	}
+	if resp := transp.responseAt(0); resp.Error == nil {
+		t.Error("expected an error response after the client deadline passed")
+	}
+}
+
+func TestClientTimeoutMetaCappedByServerTimeout(t *testing.T) {
+	rec := &deadlineRecorder{deadlines: map[string]time.Duration{}}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(rec)
+	srv := New(Options{
+		Registry:       registry,
+		Transport:      newMockTransport(),
+		RequestTimeout: time.Second,
+	})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"x","_meta":{"timeoutMs":600000}}`),
+	}, time.Now())
+
+	if got := rec.deadlines[protocol.MethodToolsCall]; got > time.Second {
+		t.Errorf("deadline = %v, want capped at server timeout of 1s", got)
+	}
+}
+
+func TestClientTimeoutMetaHugeValueFallsBackToServerTimeout(t *testing.T) {
+	rec := &deadlineRecorder{deadlines: map[string]time.Duration{}}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(rec)
+	srv := New(Options{
+		Registry:       registry,
+		Transport:      newMockTransport(),
+		RequestTimeout: time.Second,
+	})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"x","_meta":{"timeoutMs":1e300}}`),
+	}, time.Now())
+
+	got, ok := rec.deadlines[protocol.MethodToolsCall]
+	if !ok || got <= 0 || got > time.Second {
+		t.Errorf("deadline = %v (recorded %v), want within the server timeout of 1s", got, ok)
+	}
+}
+
+func TestClientTimeoutMetaNegativeRejected(t *testing.T) {
+	rec := &deadlineRecorder{deadlines: map[string]time.Duration{}}
+	registry := handler.NewHandlerRegistry()
+	registry.RegisterToolHandler(rec)
+	transp := newMockTransport()
+	srv := New(Options{Registry: registry, Transport: transp})
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  protocol.MethodToolsCall,
+		Params:  json.RawMessage(`{"name":"x","_meta":{"timeoutMs":-5}}`),
+	}, time.Now())
+
+	if _, ran := rec.deadlines[protocol.MethodToolsCall]; ran {
+		t.Error("handler ran despite a negative timeoutMs")
+	}
+	if transp.responseCount() != 1 {
+		t.Fatalf("responses = %d, want 1", transp.responseCount())
+	}
+	if resp := transp.responseAt(0); resp.Error == nil || resp.Error.Code != protocol.InvalidParams {
+		t.Errorf("Error = %+v, want InvalidParams", resp.Error)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// errNoToolStream is returned when writing to the stream of a context that
+// is not handling a tools/call
+var errNoToolStream = errors.New("no tool stream: not handling a tools/call")
+
+// errNilToolChunk is returned when writing a nil chunk to a tool stream
+var errNilToolChunk = errors.New("tool stream: nil chunk")
+
+// toolStreamKey is the context key for the current call's ToolStreamWriter
+type toolStreamKey struct{}
+
+// ToolStreamWriter lets a tools/call handler deliver partial results before
+// its final one. When the client opted in with `_meta.stream: true` and the
+// transport can deliver notifications ahead of the response (see
+// transport.StreamingTransport), each Write is sent immediately as a
+// notifications/tools/chunk to the calling client only (its session, on a
+// multi-client transport) and the handler's returned result is the
+// terminal one. Otherwise chunks are buffered and their content is prepended
+// to the handler's result, so the client receives everything in one
+// response. It is safe for concurrent use.
+type ToolStreamWriter struct {
+	server *Server
+	ctx    context.Context
+	id     interface{}
+	stream bool
+
+	mu       sync.Mutex
+	index    int
+	buffered []protocol.ToolContent
+}
+
+// ToolStream returns the stream for the tools/call being handled in ctx, or
+// nil outside a tools/call. Writing to a nil stream returns an error.
+func ToolStream(ctx context.Context) *ToolStreamWriter {
+	w, _ := ctx.Value(toolStreamKey{}).(*ToolStreamWriter)
+	return w
+}
+
+// newToolStream creates the stream for a tools/call request, streaming only
+// if both the client and the transport support it. On a transport with
+// sessions it is the caller's session that must have a stream open.
+func (s *Server) newToolStream(req *protocol.Request) *ToolStreamWriter {
+	w := &ToolStreamWriter{server: s, ctx: req.Context(), id: req.ID}
+	if stream, _ := extractMeta(req.Params)["stream"].(bool); stream {
+		if sessionID, ok := transport.SessionIDFromContext(w.ctx); ok {
+			if st, ok := s.transport.(transport.SessionStreamingTransport); ok {
+				w.stream = st.SupportsStreamingTo(sessionID)
+				return w
+			}
+		}
+		if st, ok := s.transport.(transport.StreamingTransport); ok {
+			w.stream = st.SupportsStreaming()
+		}
+	}
+	return w
+}
+
+// Streaming reports whether chunks reach the client as they are written
+// rather than with the final result
+func (w *ToolStreamWriter) Streaming() bool {
+	return w != nil && w.stream
+}
+
+// Write delivers chunk to the client, or buffers its content when not
+// streaming. A nil chunk is an error.
+func (w *ToolStreamWriter) Write(chunk *protocol.CallToolResponse) error {
+	if w == nil {
+		return errNoToolStream
+	}
+	if chunk == nil {
+		return errNilToolChunk
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.stream {
+		w.buffered = append(w.buffered, chunk.Content...)
+		return nil
+	}
+	if err := w.server.notify(w.ctx, protocol.NotificationToolChunk, protocol.ToolChunkParams{
+		RequestID: w.id,
+		Index:     w.index,
+		Chunk:     chunk,
+	}); err != nil {
+		return err
+	}
+	w.index++
+	return nil
+}
+
+// finish returns the result to send for resp, prepending any buffered
+// content
+func (w *ToolStreamWriter) finish(resp *protocol.CallToolResponse) *protocol.CallToolResponse {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buffered) == 0 {
+		return resp
+	}
+	merged := &protocol.CallToolResponse{}
+	if resp != nil {
+		*merged = *resp
+	}
+	merged.Content = append(append([]protocol.ToolContent(nil), w.buffered...), merged.Content...)
+	return merged
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+	"github.com/gomcpgo/mcp/pkg/transport"
+)
+
+// streamingMockTransport is a mockTransport that can deliver notifications
+// ahead of responses
+type streamingMockTransport struct {
+	*mockTransport
+}
+
+func (t *streamingMockTransport) SupportsStreaming() bool { return true }
+
+// generateRouter returns a tool that writes each word as a chunk and
+// finishes with a summary
+func generateRouter() *handler.ToolRouter {
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "generate"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		stream := ToolStream(ctx)
+		for _, word := range []string{"one", "two", "three"} {
+			if err := stream.Write(&protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: word}}}); err != nil {
+				return nil, err
+			}
+		}
+		return &protocol.CallToolResponse{Content: []protocol.ToolContent{{Type: "text", Text: "done"}}}, nil
+	})
+	return router
+}
+
+func callGenerate(srv *Server) {
+	callGenerateIn(context.Background(), srv)
+}
+
+// callGenerateIn calls the generate tool with a request carrying ctx
+func callGenerateIn(ctx context.Context, srv *Server) {
+	req := &protocol.Request{
+		JSONRPC: "2.0", ID: 3, Method: protocol.MethodToolsCall,
+		Params: json.RawMessage(`{"name":"generate","arguments":{},"_meta":{"stream":true}}`),
+	}
+	srv.handleRequest(context.Background(), req.WithContext(ctx), time.Now())
+}
+
+func TestToolStreamSendsChunks(t *testing.T) {
+	mt := &streamingMockTransport{newMockTransport()}
+	srv := Builder().Transport(mt).Tool(generateRouter()).Build()
+
+	callGenerate(srv)
+
+	mt.mu.Lock()
+	notifications := append([]*protocol.Notification(nil), mt.notifications...)
+	mt.mu.Unlock()
+
+	want := []string{"one", "two", "three"}
+	if len(notifications) != len(want) {
+		t.Fatalf("got %d chunk notifications, want %d", len(notifications), len(want))
+	}
+	for i, n := range notifications {
+		if n.Method != protocol.NotificationToolChunk {
+			t.Errorf("method = %q, want %q", n.Method, protocol.NotificationToolChunk)
+		}
+		var params protocol.ToolChunkParams
+		if err := json.Unmarshal(n.Params, &params); err != nil {
+			t.Fatalf("unmarshal params: %v", err)
+		}
+		if params.Index != i || params.RequestID != float64(3) {
+			t.Errorf("chunk %d: index %d, requestId %v", i, params.Index, params.RequestID)
+		}
+		if params.Chunk == nil || params.Chunk.Content[0].Text != want[i] {
+			t.Errorf("chunk %d = %+v, want %q", i, params.Chunk, want[i])
+		}
+	}
+
+	resp := mt.responseAt(0)
+	final, ok := resp.Result.(*protocol.CallToolResponse)
+	if !ok || len(final.Content) != 1 || final.Content[0].Text != "done" {
+		t.Errorf("terminal result = %+v, want only the handler's result", resp.Result)
+	}
+}
+
+func TestToolStreamBuffersWithoutStreamingTransport(t *testing.T) {
+	mt := newMockTransport()
+	srv := Builder().Transport(mt).Tool(generateRouter()).Build()
+
+	callGenerate(srv)
+
+	if got := mt.notificationCount(); got != 0 {
+		t.Errorf("got %d notifications, want none when buffering", got)
+	}
+	if got := mt.responseCount(); got != 1 {
+		t.Fatalf("got %d responses, want 1", got)
+	}
+	final, ok := mt.responseAt(0).Result.(*protocol.CallToolResponse)
+	if !ok {
+		t.Fatalf("result = %T, want *protocol.CallToolResponse", mt.responseAt(0).Result)
+	}
+	var texts []string
+	for _, c := range final.Content {
+		texts = append(texts, c.Text)
+	}
+	if len(texts) != 4 || texts[0] != "one" || texts[3] != "done" {
+		t.Errorf("buffered content = %v, want chunks followed by the final result", texts)
+	}
+}
+
+func TestToolStreamOutsideToolCall(t *testing.T) {
+	stream := ToolStream(context.Background())
+	if stream.Streaming() {
+		t.Error("no stream should report not streaming")
+	}
+	if err := stream.Write(&protocol.CallToolResponse{}); err == nil {
+		t.Error("writing outside a tools/call should fail")
+	}
+}
+
+// sessionStreamingTransport is a sessionTransport where only session "a"
+// has an event stream open
+type sessionStreamingTransport struct {
+	*sessionTransport
+}
+
+func (t *sessionStreamingTransport) SupportsStreaming() bool { return true }
+
+func (t *sessionStreamingTransport) SupportsStreamingTo(sessionID string) bool {
+	return sessionID == "a"
+}
+
+func TestToolStreamTargetsCallerSession(t *testing.T) {
+	mt := &sessionStreamingTransport{&sessionTransport{mockTransport: newMockTransport(), sent: make(map[string][]*protocol.Notification)}}
+	srv := Builder().Transport(mt).Tool(generateRouter()).Build()
+
+	callGenerateIn(transport.WithSessionID(context.Background(), "a"), srv)
+	callGenerateIn(transport.WithSessionID(context.Background(), "b"), srv)
+
+	mt.mu.Lock()
+	toA, toB := len(mt.sent["a"]), len(mt.sent["b"])
+	mt.mu.Unlock()
+	if toA != 3 || toB != 0 {
+		t.Errorf("chunks sent to a = %d, b = %d; want 3 and 0", toA, toB)
+	}
+	if got := mt.notificationCount(); got != 0 {
+		t.Errorf("got %d broadcast notifications, want none", got)
+	}
+
+	// Session b has no stream open, so its chunks arrive with the result
+	final, ok := mt.responseAt(1).Result.(*protocol.CallToolResponse)
+	if !ok || len(final.Content) != 4 {
+		t.Errorf("session b result = %+v, want buffered chunks and the final result", mt.responseAt(1).Result)
+	}
+}
+
+func TestToolStreamRejectsNilChunk(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		var got error
+		router := handler.NewToolRouter()
+		router.Register(protocol.Tool{Name: "generate"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+			got = ToolStream(ctx).Write(nil)
+			return &protocol.CallToolResponse{}, nil
+		})
+		var mt transport.Transport = newMockTransport()
+		if streaming {
+			mt = &streamingMockTransport{newMockTransport()}
+		}
+		callGenerate(Builder().Transport(mt).Tool(router).Build())
+		if got == nil {
+			t.Errorf("streaming=%v: writing a nil chunk should fail", streaming)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// Unknown notifications must be dropped without a reply, even when a generic
+// handler that would answer any request is registered.
+func TestUnknownNotificationIsDroppedSilently(t *testing.T) {
+	mockTransport := newMockTransport()
+	registry := handler.NewHandlerRegistry()
+	generic := &echoGenericHandler{}
+	registry.RegisterHandler(generic)
+	srv := New(Options{Registry: registry, Transport: mockTransport})
+	done := runServer(srv)
+
+	mockTransport.requests <- &protocol.Request{JSONRPC: "2.0", Method: "notifications/vendor/unknown", Params: json.RawMessage(`{}`)}
+	mockTransport.requests <- &protocol.Request{JSONRPC: "2.0", Method: "custom/event"}
+	mockTransport.requests <- &protocol.Request{JSONRPC: "2.0", ID: 1, Method: protocol.MethodPing}
+
+	deadline := time.Now().Add(time.Second)
+	for mockTransport.responseCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	// Leave time for a stray error response to the notifications to arrive.
+	time.Sleep(50 * time.Millisecond)
+
+	mockTransport.requests <- nil
+	<-done
+
+	if n := mockTransport.responseCount(); n != 1 {
+		t.Fatalf("responses = %d, want only the ping's", n)
+	}
+	if resp := mockTransport.responseAt(0); resp.ID != 1 || resp.Error != nil {
+		t.Errorf("response = %+v, want the ping result", resp)
+	}
+	if len(generic.methods) != 0 {
+		t.Errorf("generic handler saw %v, want no notifications", generic.methods)
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/handler"
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func TestRequestValidator(t *testing.T) {
+	var calls int32
+	router := handler.NewToolRouter()
+	router.Register(protocol.Tool{Name: "upload"}, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		return &protocol.CallToolResponse{}, nil
+	})
+
+	const maxParams = 64
+	mockTransport := newMockTransport()
+	srv := Builder().
+		Transport(mockTransport).
+		Tool(router).
+		With(WithRequestValidator(func(req *protocol.Request) *protocol.Error {
+			if len(req.Params) > maxParams {
+				return &protocol.Error{Code: protocol.InvalidParams, Message: "params too large"}
+			}
+			return nil
+		})).
+		Build()
+
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 1, Method: protocol.MethodToolsCall,
+		Params: json.RawMessage(`{"name":"upload","arguments":{}}`),
+	}, time.Now())
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("accepted request: handler calls = %d, want 1", n)
+	}
+	if resp := mockTransport.responseAt(0); resp.Error != nil {
+		t.Errorf("accepted request got error %+v", resp.Error)
+	}
+
+	big, _ := json.Marshal(map[string]interface{}{
+		"name":      "upload",
+		"arguments": map[string]string{"blob": string(make([]byte, 2*maxParams))},
+	})
+	srv.handleRequest(context.Background(), &protocol.Request{
+		JSONRPC: "2.0", ID: 2, Method: protocol.MethodToolsCall, Params: big,
+	}, time.Now())
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("rejected request reached the handler")
+	}
+	resp := mockTransport.responseAt(1)
+	if resp.Error == nil || resp.Error.Code != protocol.InvalidParams || resp.Error.Message != "params too large" {
+		t.Errorf("rejected request response = %+v, want the validator's error", resp.Error)
+	}
+	if resp.ID != 2 {
+		t.Errorf("rejected response ID = %v, want 2", resp.ID)
+	}
+}
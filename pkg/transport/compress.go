@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// writeBody writes data as the HTTP response body, gzip-encoded when
+// HTTPOptions.CompressionThreshold is set, data reaches it and the client
+// accepts gzip. Headers other than the encoding must already be set.
+func (t *HTTPTransport) writeBody(w http.ResponseWriter, r *http.Request, data []byte) error {
+	threshold := t.options.CompressionThreshold
+	if threshold > 0 {
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+	if threshold <= 0 || len(data) < threshold || !acceptsGzip(r) {
+		_, err := w.Write(data)
+		return err
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(data); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// acceptsGzip reports whether r's Accept-Encoding allows a gzip response.
+// Every entry is considered: an explicit gzip entry decides on its own,
+// whatever its position, and "*" only applies when gzip is not named. A q
+// value of zero rules the encoding out.
+func acceptsGzip(r *http.Request) bool {
+	var gzipParams, wildcardParams string
+	var sawGzip, sawWildcard bool
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, entry := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(entry, ";")
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "gzip":
+				gzipParams, sawGzip = params, true
+			case "*":
+				wildcardParams, sawWildcard = params, true
+			}
+		}
+	}
+	switch {
+	case sawGzip:
+		return qualityAllowed(gzipParams)
+	case sawWildcard:
+		return qualityAllowed(wildcardParams)
+	}
+	return false
+}
+
+// qualityAllowed reports whether an Accept-Encoding entry's parameters leave
+// it enabled, i.e. carry no q value of zero
+func qualityAllowed(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return err == nil && q > 0
+	}
+	return true
+}
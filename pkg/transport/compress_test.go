@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// postWithEncoding POSTs a request with the given Accept-Encoding. Setting
+// the header stops net/http from decompressing the body on its own.
+func postWithEncoding(t *testing.T, url, acceptEncoding string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call"}`))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	return resp
+}
+
+func TestHTTPCompression(t *testing.T) {
+	large := strings.Repeat("x", 4096)
+	tests := []struct {
+		name           string
+		result         string
+		acceptEncoding string
+		wantGzip       bool
+	}{
+		{name: "large", result: large, acceptEncoding: "gzip", wantGzip: true},
+		{name: "small", result: "ok", acceptEncoding: "gzip", wantGzip: false},
+		{name: "large without gzip", result: large, acceptEncoding: "identity", wantGzip: false},
+		{name: "large with gzip refused", result: large, acceptEncoding: "br, gzip;q=0", wantGzip: false},
+		{name: "large with wildcard", result: large, acceptEncoding: "*", wantGzip: true},
+		{name: "large with gzip refused after wildcard", result: large, acceptEncoding: "*;q=1, gzip;q=0", wantGzip: false},
+		{name: "large with gzip allowed after wildcard refused", result: large, acceptEncoding: "*;q=0, gzip", wantGzip: true},
+		{name: "large with wildcard refused", result: large, acceptEncoding: "br, *;q=0", wantGzip: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewHTTPTransport(HTTPOptions{CompressionThreshold: 1024})
+			ts := httptest.NewServer(transport)
+			defer ts.Close()
+			defer transport.Stop(context.Background())
+
+			go func() {
+				req := <-transport.Receive()
+				transport.Send(&protocol.Response{JSONRPC: "2.0", ID: req.ID, Result: tt.result})
+			}()
+
+			resp := postWithEncoding(t, ts.URL, tt.acceptEncoding)
+			defer resp.Body.Close()
+
+			gzipped := resp.Header.Get("Content-Encoding") == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("Content-Encoding = %q, want gzip %v", resp.Header.Get("Content-Encoding"), tt.wantGzip)
+			}
+			var body io.Reader = resp.Body
+			if gzipped {
+				gz, err := gzip.NewReader(resp.Body)
+				if err != nil {
+					t.Fatalf("gzip reader: %v", err)
+				}
+				body = gz
+			}
+			var got protocol.Response
+			if err := json.NewDecoder(body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.Result != tt.result {
+				t.Errorf("result did not round-trip: got %d-byte %T", len(fmt.Sprint(got.Result)), got.Result)
+			}
+		})
+	}
+}
+
+func TestHTTPCompressionDisabledByDefault(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	go func() {
+		req := <-transport.Receive()
+		transport.Send(&protocol.Response{JSONRPC: "2.0", ID: req.ID, Result: strings.Repeat("x", 1<<16)})
+	}()
+
+	resp := postWithEncoding(t, ts.URL, "gzip")
+	defer resp.Body.Close()
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != "" {
+		t.Errorf("Content-Encoding = %q, want none without a threshold", encoding)
+	}
+}
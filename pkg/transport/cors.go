@@ -0,0 +1,81 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CORSOptions configures cross-origin access to an HTTPTransport for
+// browser-based clients.
+type CORSOptions struct {
+	// AllowedOrigins lists origins allowed to call the transport, e.g.
+	// "https://app.example.com". There is no wildcard by default; include
+	// "*" explicitly to allow any origin.
+	AllowedOrigins []string
+
+	// AllowedMethods defaults to GET, POST and OPTIONS.
+	AllowedMethods []string
+
+	// AllowedHeaders defaults to Content-Type and Authorization.
+	AllowedHeaders []string
+
+	// MaxAge is how long, in seconds, browsers may cache a preflight result.
+	// Zero leaves the header unset.
+	MaxAge int
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodOptions}
+	defaultCORSHeaders = []string{"Content-Type", "Authorization"}
+)
+
+// allowsOrigin reports whether origin may access the transport.
+func (c *CORSOptions) allowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// handle applies CORS to r. It returns false when the request has been fully
+// answered — a preflight, or a disallowed origin — and must not reach the
+// JSON-RPC layer.
+func (c *CORSOptions) handle(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		// Not a cross-origin browser request.
+		return true
+	}
+
+	if !c.allowsOrigin(origin) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return false
+	}
+
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	h.Set("Access-Control-Allow-Origin", origin)
+
+	if r.Method != http.MethodOptions {
+		return true
+	}
+
+	methods := c.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := c.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	if c.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(c.MaxAge))
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return false
+}
@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCORSTestServer(t *testing.T) (*HTTPTransport, *httptest.Server) {
+	t.Helper()
+	transport := NewHTTPTransport(HTTPOptions{
+		CORS: &CORSOptions{AllowedOrigins: []string{"https://app.example.com"}},
+	})
+	ts := httptest.NewServer(transport)
+	t.Cleanup(func() {
+		ts.Close()
+		transport.Stop(context.Background())
+	})
+	return transport, ts
+}
+
+func TestCORSPreflightAllowedOrigin(t *testing.T) {
+	transport, ts := newCORSTestServer(t)
+
+	req, _ := http.NewRequest(http.MethodOptions, ts.URL, nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("OPTIONS: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Methods"); !strings.Contains(got, "POST") {
+		t.Errorf("Allow-Methods = %q, want POST included", got)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Headers"); !strings.Contains(got, "Authorization") {
+		t.Errorf("Allow-Headers = %q, want Authorization included", got)
+	}
+
+	select {
+	case r := <-transport.Receive():
+		t.Fatalf("preflight reached the JSON-RPC layer: %+v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCORSAllowedOriginOnPost(t *testing.T) {
+	transport, ts := newCORSTestServer(t)
+	go func() { <-transport.Receive() }()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	req.Header.Set("Origin", "https://app.example.com")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("status = %d, want 202", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+}
+
+func TestCORSDisallowedOriginBlocked(t *testing.T) {
+	transport, ts := newCORSTestServer(t)
+
+	for _, method := range []string{http.MethodOptions, http.MethodPost} {
+		req, _ := http.NewRequest(method, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		req.Header.Set("Origin", "https://evil.example.com")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want 403", method, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("%s: Allow-Origin = %q, want none", method, got)
+		}
+	}
+
+	select {
+	case r := <-transport.Receive():
+		t.Fatalf("blocked request reached the JSON-RPC layer: %+v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,554 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// maxHTTPBodyBytes caps the size of a single POSTed JSON-RPC message.
+const maxHTTPBodyBytes = 4 << 20
+
+// DefaultKeepAlive is the heartbeat interval used on event streams when
+// HTTPOptions.KeepAlive is zero.
+const DefaultKeepAlive = 30 * time.Second
+
+// Authenticator inspects an incoming HTTP request before any JSON-RPC
+// message in it is surfaced on Receive(). Returning an error rejects the
+// request with 401 Unauthorized. The returned identity is attached to the
+// request context and can be read by handlers with IdentityFromContext.
+type Authenticator func(r *http.Request) (identity interface{}, err error)
+
+// BearerAuthenticator returns an Authenticator that accepts requests with an
+// `Authorization: Bearer <token>` header for which verify succeeds.
+func BearerAuthenticator(verify func(token string) (identity interface{}, err error)) Authenticator {
+	return func(r *http.Request) (interface{}, error) {
+		const prefix = "Bearer "
+		header := r.Header.Get("Authorization")
+		if len(header) <= len(prefix) || header[:len(prefix)] != prefix {
+			return nil, errors.New("missing bearer token")
+		}
+		return verify(header[len(prefix):])
+	}
+}
+
+type identityKey struct{}
+
+// WithIdentity returns ctx carrying the authenticated caller's identity.
+func WithIdentity(ctx context.Context, identity interface{}) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity an Authenticator attached to the
+// request, if any.
+func IdentityFromContext(ctx context.Context) (interface{}, bool) {
+	identity := ctx.Value(identityKey{})
+	return identity, identity != nil
+}
+
+// HTTPOptions configures an HTTPTransport.
+type HTTPOptions struct {
+	// Addr is the address Start listens on. Leave empty to mount the
+	// transport on an existing server; it implements http.Handler.
+	Addr string
+
+	// Authenticator, when set, must accept every HTTP request before its
+	// message reaches the server.
+	Authenticator Authenticator
+	// CORS, when set, enables cross-origin access for browser clients.
+	// Preflight requests are answered here and never reach the server.
+	CORS *CORSOptions
+
+	// KeepAlive is how often an idle event stream gets a `: ping` comment
+	// line so proxies do not drop it. Zero means DefaultKeepAlive; a
+	// negative value disables heartbeats.
+	KeepAlive time.Duration
+
+	// Logger receives the transport's diagnostics, such as rejected
+	// requests and failed writes. Nil logs nothing.
+	Logger *log.Logger
+
+	// SessionIdleTimeout, when non-zero, gives each client a session: the
+	// reply to initialize carries a SessionHeader that every later request
+	// must send back, and handlers can read it with SessionIDFromContext.
+	// A session with no open event stream and no request for this long is
+	// closed; its ID then gets 404 Not Found.
+	SessionIdleTimeout time.Duration
+
+	// OnSessionClosed, when set, is called with the ID of each session
+	// closed for being idle, so state tied to it can be released. A server
+	// using this transport already cancels the session's async operations
+	// (see NotifySessionClosed).
+	OnSessionClosed func(sessionID string)
+
+	// CompressionThreshold, when positive, gzip-encodes POST responses of at
+	// least this many bytes for clients whose Accept-Encoding allows gzip,
+	// marking them with Content-Encoding: gzip. Event streams are never
+	// compressed. Zero disables compression.
+	CompressionThreshold int
+}
+
+// HTTPTransport serves MCP over HTTP. Clients POST one JSON-RPC message per
+// request; a request with an id is answered in the HTTP response, while
+// notifications and client responses get 202 Accepted. Server-initiated
+// notifications and requests are pushed to clients holding a GET
+// text/event-stream connection open.
+type HTTPTransport struct {
+	options   HTTPOptions
+	server    *http.Server
+	requests  chan *protocol.Request
+	responses chan *protocol.Response
+	errors    chan error
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// pushers counts deliveries in progress on requests, responses and
+	// errors. They run without t.mu, so Stop waits for them before closing
+	// the channels.
+	pushers sync.WaitGroup
+
+	mu       sync.RWMutex
+	isClosed bool
+	pending  map[string]map[string]chan *protocol.Response // id -> session ID -> reply
+	streams  map[chan []byte]*httpSession
+	sessions map[string]*httpSession
+	onClosed []func(sessionID string) // added by NotifySessionClosed
+
+	logger *log.Logger
+}
+
+func NewHTTPTransport(options HTTPOptions) *HTTPTransport {
+	t := &HTTPTransport{
+		options:   options,
+		requests:  make(chan *protocol.Request),
+		responses: make(chan *protocol.Response),
+		errors:    make(chan error),
+		done:      make(chan struct{}),
+		pending:   make(map[string]map[string]chan *protocol.Response),
+		streams:   make(map[chan []byte]*httpSession),
+		sessions:  make(map[string]*httpSession),
+		logger:    loggerOrDiscard(options.Logger),
+	}
+	if t.sessionsEnabled() {
+		go t.reapSessions()
+	}
+	return t
+}
+
+func (t *HTTPTransport) Start(ctx context.Context) error {
+	if t.options.Addr == "" {
+		return nil
+	}
+	t.server = &http.Server{Addr: t.options.Addr, Handler: t}
+	go func() {
+		if err := t.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.sendError(ctx, fmt.Errorf("http server: %w", err))
+		}
+	}()
+	return nil
+}
+
+func (t *HTTPTransport) Stop(ctx context.Context) error {
+	// Close done first so deliveries blocked on a channel give up, then wait
+	// for them before closing the channels. Once isClosed is set no new
+	// delivery starts.
+	t.closeOnce.Do(func() { close(t.done) })
+
+	t.mu.Lock()
+	closing := !t.isClosed
+	t.isClosed = true
+	t.mu.Unlock()
+	if closing {
+		t.pushers.Wait()
+		close(t.requests)
+		close(t.responses)
+		close(t.errors)
+	}
+
+	if t.server != nil {
+		return t.server.Shutdown(ctx)
+	}
+	return nil
+}
+
+// Send delivers a response to the HTTP request still waiting for it. With
+// sessions, the session is taken from the response's context; a response
+// without one is delivered only if a single session awaits that id.
+func (t *HTTPTransport) Send(response *protocol.Response) error {
+	key := idKey(response.ID)
+	sessionID, hasSession := SessionIDFromContext(response.Context())
+
+	t.mu.Lock()
+	if t.isClosed {
+		t.mu.Unlock()
+		return fmt.Errorf("transport is closed")
+	}
+	waiting := t.pending[key]
+	if !hasSession && len(waiting) == 1 {
+		for only := range waiting {
+			sessionID = only
+		}
+	}
+	ch, ok := waiting[sessionID]
+	t.removePending(key, sessionID)
+	t.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no pending HTTP request for id %s", key)
+	}
+	ch <- response
+	return nil
+}
+
+// removePending forgets the request id of session sessionID. Caller must
+// hold t.mu.
+func (t *HTTPTransport) removePending(key, sessionID string) {
+	delete(t.pending[key], sessionID)
+	if len(t.pending[key]) == 0 {
+		delete(t.pending, key)
+	}
+}
+
+func (t *HTTPTransport) SendNotification(notification *protocol.Notification) error {
+	return t.broadcast(notification)
+}
+
+// SupportsStreaming reports whether any event stream is open to receive
+// notifications ahead of a response
+func (t *HTTPTransport) SupportsStreaming() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return !t.isClosed && len(t.streams) > 0
+}
+
+func (t *HTTPTransport) SendRequest(request *protocol.Request) error {
+	return t.broadcast(request)
+}
+
+func (t *HTTPTransport) Receive() <-chan *protocol.Request {
+	return t.requests
+}
+
+func (t *HTTPTransport) Responses() <-chan *protocol.Response {
+	return t.responses
+}
+
+func (t *HTTPTransport) Errors() <-chan error {
+	return t.errors
+}
+
+// ServeHTTP applies CORS, authenticates the request, then handles a POSTed JSON-RPC
+// message or opens an event stream for GET.
+func (t *HTTPTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if t.options.CORS != nil && !t.options.CORS.handle(w, r) {
+		return
+	}
+
+	ctx := r.Context()
+	if t.options.Authenticator != nil {
+		identity, err := t.options.Authenticator(r)
+		if err != nil {
+			t.logger.Printf("transport: rejected %s %s: %v", r.Method, r.URL.Path, err)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		ctx = WithIdentity(ctx, identity)
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		t.handlePost(ctx, w, r)
+	case http.MethodGet:
+		sess, ok := t.streamSession(w, r)
+		if !ok {
+			return
+		}
+		t.handleStream(ctx, w, sess)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (t *HTTPTransport) handlePost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxHTTPBodyBytes))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if isBatch(body) {
+		http.Error(w, "batch requests are not supported over HTTP", http.StatusBadRequest)
+		return
+	}
+
+	req, resp, err := decodeMessage(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sess, ok := t.postSession(w, r, req)
+	if !ok {
+		return
+	}
+	var sessionID string
+	if sess != nil {
+		sessionID = sess.id
+		ctx = WithSessionID(ctx, sessionID)
+	}
+
+	if resp != nil {
+		if !t.pushResponse(ctx, resp) {
+			http.Error(w, "transport is closed", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	req = req.WithContext(ctx)
+	if req.IsNotification() {
+		if !t.pushRequest(ctx, req) {
+			http.Error(w, "transport is closed", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	key := idKey(req.ID)
+	reply := make(chan *protocol.Response, 1)
+	t.mu.Lock()
+	if _, dup := t.pending[key][sessionID]; dup {
+		t.mu.Unlock()
+		http.Error(w, fmt.Sprintf("request id %s is already in flight", key), http.StatusConflict)
+		return
+	}
+	if t.pending[key] == nil {
+		t.pending[key] = make(map[string]chan *protocol.Response)
+	}
+	t.pending[key][sessionID] = reply
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		if t.pending[key][sessionID] == reply {
+			t.removePending(key, sessionID)
+		}
+		t.mu.Unlock()
+	}()
+
+	if !t.pushRequest(ctx, req) {
+		http.Error(w, "transport is closed", http.StatusServiceUnavailable)
+		return
+	}
+
+	select {
+	case response := <-reply:
+		data, err := json.Marshal(response)
+		if err != nil {
+			t.logger.Printf("transport: encode response: %v", err)
+			http.Error(w, "failed to encode response", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := t.writeBody(w, r, append(data, '\n')); err != nil {
+			t.logger.Printf("transport: write response: %v", err)
+		}
+	case <-ctx.Done():
+	case <-t.done:
+		http.Error(w, "transport is closed", http.StatusServiceUnavailable)
+	}
+}
+
+// handleStream holds a GET connection open and writes every server-initiated
+// message to it as a server-sent event. An open stream keeps sess alive.
+func (t *HTTPTransport) handleStream(ctx context.Context, w http.ResponseWriter, sess *httpSession) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events := make(chan []byte, 16)
+	t.mu.Lock()
+	if t.isClosed {
+		t.mu.Unlock()
+		http.Error(w, "transport is closed", http.StatusServiceUnavailable)
+		return
+	}
+	t.streams[events] = sess
+	if sess != nil {
+		sess.streams++
+	}
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.streams, events)
+		if sess != nil {
+			sess.streams--
+			sess.lastSeen = time.Now()
+		}
+		t.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Heartbeats are SSE comment lines, which clients discard rather than
+	// parse as messages. A nil channel disables them.
+	var heartbeat <-chan time.Time
+	if interval := t.keepAlive(); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case data := <-events:
+			if _, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ctx.Done():
+			return
+		case <-sess.done():
+			return
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// keepAlive returns the heartbeat interval, or 0 when heartbeats are off.
+func (t *HTTPTransport) keepAlive() time.Duration {
+	switch {
+	case t.options.KeepAlive < 0:
+		return 0
+	case t.options.KeepAlive == 0:
+		return DefaultKeepAlive
+	default:
+		return t.options.KeepAlive
+	}
+}
+
+// broadcast pushes a server-initiated message to every open event stream.
+// Streams that are not keeping up drop the message rather than stall the
+// server.
+func (t *HTTPTransport) broadcast(message interface{}) error {
+	return t.publish(message, func(*httpSession) bool { return true })
+}
+
+// publish pushes message to the open event streams of the sessions selected
+// by match; see broadcast
+func (t *HTTPTransport) publish(message interface{}, match func(*httpSession) bool) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.isClosed {
+		return fmt.Errorf("transport is closed")
+	}
+	for events, sess := range t.streams {
+		if !match(sess) {
+			continue
+		}
+		select {
+		case events <- data:
+		default:
+			t.logger.Printf("transport: event stream is full, dropping message")
+		}
+	}
+	return nil
+}
+
+// pushRequest hands req to the server. It returns false when the transport
+// is closed or ctx ends first.
+func (t *HTTPTransport) pushRequest(ctx context.Context, req *protocol.Request) bool {
+	if !t.startPush() {
+		return false
+	}
+	defer t.pushers.Done()
+	select {
+	case t.requests <- req:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-t.done:
+		return false
+	}
+}
+
+// pushResponse hands a client response to the server; see pushRequest.
+func (t *HTTPTransport) pushResponse(ctx context.Context, resp *protocol.Response) bool {
+	if !t.startPush() {
+		return false
+	}
+	defer t.pushers.Done()
+	select {
+	case t.responses <- resp:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-t.done:
+		return false
+	}
+}
+
+// sendError pushes err onto the errors channel if a receiver is ready,
+// otherwise logs it.
+func (t *HTTPTransport) sendError(ctx context.Context, err error) {
+	if !t.startPush() {
+		t.logger.Printf("transport: %v", err)
+		return
+	}
+	defer t.pushers.Done()
+	select {
+	case t.errors <- err:
+	case <-ctx.Done():
+	case <-t.done:
+	default:
+		t.logger.Printf("transport: %v", err)
+	}
+}
+
+// startPush registers a delivery on one of the server-facing channels, or
+// returns false once the transport is closed. The lock is held only to
+// register, never while blocking on the channel: a consumer that replies
+// inline needs t.mu for Send. The caller must call t.pushers.Done.
+func (t *HTTPTransport) startPush() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.isClosed {
+		return false
+	}
+	t.pushers.Add(1)
+	return true
+}
+
+// idKey turns a JSON-RPC id into a map key that keeps 1 and "1" distinct.
+func idKey(id interface{}) string {
+	data, err := json.Marshal(id)
+	if err != nil {
+		return fmt.Sprintf("%v", id)
+	}
+	return string(data)
+}
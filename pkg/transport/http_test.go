@@ -0,0 +1,242 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+func tokenAuthenticator() Authenticator {
+	return BearerAuthenticator(func(token string) (interface{}, error) {
+		if token != "good-token" {
+			return nil, errors.New("invalid token")
+		}
+		return "alice", nil
+	})
+}
+
+func postJSON(t *testing.T, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	return resp
+}
+
+func TestHTTPAuthenticatorRejectsBadToken(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{Authenticator: tokenAuthenticator()})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	for _, token := range []string{"", "bad-token"} {
+		resp := postJSON(t, ts.URL, token, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("token %q: status = %d, want 401", token, resp.StatusCode)
+		}
+	}
+
+	select {
+	case req := <-transport.Receive():
+		t.Fatalf("rejected request reached Receive(): %+v", req)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHTTPAuthenticatorAttachesIdentity(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{Authenticator: tokenAuthenticator()})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	// Play the server: answer the request once it arrives.
+	go func() {
+		req := <-transport.Receive()
+		identity, _ := IdentityFromContext(req.Context())
+		transport.Send(&protocol.Response{JSONRPC: "2.0", ID: req.ID, Result: identity})
+	}()
+
+	resp := postJSON(t, ts.URL, "good-token", `{"jsonrpc":"2.0","id":1,"method":"whoami"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var got protocol.Response
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Result != "alice" {
+		t.Errorf("result = %v, want alice", got.Result)
+	}
+}
+
+func TestHTTPNotificationIsAccepted(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	received := make(chan *protocol.Request, 1)
+	go func() { received <- <-transport.Receive() }()
+
+	resp := postJSON(t, ts.URL, "", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("status = %d, want 202", resp.StatusCode)
+	}
+
+	select {
+	case req := <-received:
+		if !req.IsNotification() {
+			t.Errorf("expected a notification, got %+v", req)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notification did not reach Receive()")
+	}
+}
+
+func TestHTTPEventStreamKeepAlive(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{KeepAlive: 20 * time.Millisecond})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	lines := make(chan string, 64)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	// Wait for two heartbeats, then interleave a real message.
+	start := time.Now()
+	pings := 0
+	for pings < 2 {
+		line, ok := <-lines
+		if !ok {
+			t.Fatal("stream closed before heartbeats")
+		}
+		if line == ": ping" {
+			pings++
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond || elapsed > time.Second {
+		t.Errorf("two heartbeats took %v, want about 40ms", elapsed)
+	}
+
+	notif, _ := protocol.NewNotification("notifications/tools/list_changed", nil)
+	if err := transport.SendNotification(notif); err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+
+	for line := range lines {
+		if line == ": ping" || line == "" || line == "event: message" {
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			t.Fatalf("unexpected stream line %q", line)
+		}
+		var got protocol.Notification
+		if err := json.Unmarshal([]byte(data), &got); err != nil {
+			t.Fatalf("data line is not a JSON-RPC message: %v", err)
+		}
+		if got.Method != "notifications/tools/list_changed" {
+			t.Errorf("method = %q", got.Method)
+		}
+		return
+	}
+	t.Fatal("stream closed before the notification arrived")
+}
+
+func TestHTTPSupportsStreamingOnlyWithOpenStream(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	if transport.SupportsStreaming() {
+		t.Error("streaming reported with no event stream open")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if !transport.SupportsStreaming() {
+		t.Error("streaming not reported with an event stream open")
+	}
+}
+
+// A consumer that answers each request inline must not deadlock against
+// requests still waiting to be delivered.
+func TestHTTPInlineRepliesWithConcurrentRequests(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	go func() {
+		for req := range transport.Receive() {
+			transport.Send(&protocol.Response{JSONRPC: "2.0", ID: req.ID, Result: "ok"})
+		}
+	}()
+
+	const clients = 8
+	statuses := make(chan int, clients)
+	for i := 0; i < clients; i++ {
+		go func(id int) {
+			resp := postJSON(t, ts.URL, "", fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"ping"}`, id))
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}(i)
+	}
+	for i := 0; i < clients; i++ {
+		select {
+		case status := <-statuses:
+			if status != http.StatusOK {
+				t.Errorf("status = %d, want 200", status)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("requests deadlocked waiting for inline replies")
+		}
+	}
+}
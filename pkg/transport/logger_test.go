@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// feedInvalidMessage starts a stdio transport built with opts, writes a
+// message it cannot route and waits for the read loop to get past it.
+func feedInvalidMessage(t *testing.T, opts ...StdioOption) {
+	t.Helper()
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	defer pw.Close()
+	oldStdin := os.Stdin
+	os.Stdin = pr
+	defer func() { os.Stdin = oldStdin }()
+
+	transport := NewStdioTransport(opts...)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport.Start(ctx)
+
+	// Nobody reads Errors(), so the decode error falls through to the log.
+	io.WriteString(pw, `{"jsonrpc":"1.0","method":"ping"}`+"\n")
+	io.WriteString(pw, `{"jsonrpc":"2.0","id":1,"method":"ping"}`+"\n")
+	select {
+	case <-transport.Receive():
+	case <-time.After(time.Second):
+		t.Fatal("read loop did not get past the invalid message")
+	}
+}
+
+func TestStdioTransportLogger(t *testing.T) {
+	var global bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&global)
+	defer log.SetOutput(prev)
+
+	feedInvalidMessage(t)
+	if global.Len() != 0 {
+		t.Errorf("default transport wrote to the global logger:\n%s", global.String())
+	}
+
+	var custom bytes.Buffer
+	feedInvalidMessage(t, WithTransportLogger(log.New(&custom, "", 0)))
+	if !strings.Contains(custom.String(), "invalid JSON-RPC version") {
+		t.Errorf("provided logger output = %q, want the decode error", custom.String())
+	}
+	if global.Len() != 0 {
+		t.Errorf("transport with its own logger wrote to the global logger:\n%s", global.String())
+	}
+}
+
+func TestHTTPTransportLogger(t *testing.T) {
+	var global bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&global)
+	defer log.SetOutput(prev)
+
+	var custom bytes.Buffer
+	for _, logger := range []*log.Logger{nil, log.New(&custom, "", 0)} {
+		transport := NewHTTPTransport(HTTPOptions{Authenticator: tokenAuthenticator(), Logger: logger})
+		ts := httptest.NewServer(transport)
+		resp := postJSON(t, ts.URL, "bad-token", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+		resp.Body.Close()
+		ts.Close()
+		transport.Stop(context.Background())
+	}
+
+	if global.Len() != 0 {
+		t.Errorf("HTTP transport wrote to the global logger:\n%s", global.String())
+	}
+	if !strings.Contains(custom.String(), "rejected") {
+		t.Errorf("provided logger output = %q, want the rejected request", custom.String())
+	}
+}
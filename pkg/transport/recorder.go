@@ -0,0 +1,168 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// Recording directions
+const (
+	DirectionIn  = "in"  // a request or notification from the client
+	DirectionOut = "out" // a response or notification to the client
+)
+
+// RecordedMessage is one line of a recording made by RecordingTransport
+type RecordedMessage struct {
+	Direction string          `json:"direction"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// RecordingTransport wraps a transport and writes every request it receives
+// and every response and notification it sends to w, one RecordedMessage
+// per line (JSONL), for reproducing a client's exact sequence with Replay.
+// Batches pass through and are recorded entry by entry. Write errors are
+// ignored so a full disk never breaks the session being recorded.
+type RecordingTransport struct {
+	inner Transport
+
+	mu sync.Mutex
+	w  io.Writer
+
+	requests chan *protocol.Request
+	batches  chan []*protocol.Request
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRecordingTransport returns inner with its traffic recorded to w
+func NewRecordingTransport(inner Transport, w io.Writer) *RecordingTransport {
+	t := &RecordingTransport{
+		inner:    inner,
+		w:        w,
+		requests: make(chan *protocol.Request),
+		done:     make(chan struct{}),
+	}
+	if _, ok := inner.(BatchTransport); ok {
+		t.batches = make(chan []*protocol.Request)
+	}
+	return t
+}
+
+func (t *RecordingTransport) Start(ctx context.Context) error {
+	if err := t.inner.Start(ctx); err != nil {
+		return err
+	}
+	go t.forwardRequests()
+	if bt, ok := t.inner.(BatchTransport); ok {
+		go t.forwardBatches(bt)
+	}
+	return nil
+}
+
+func (t *RecordingTransport) Stop(ctx context.Context) error {
+	t.stopOnce.Do(func() { close(t.done) })
+	return t.inner.Stop(ctx)
+}
+
+func (t *RecordingTransport) Send(response *protocol.Response) error {
+	t.record(DirectionOut, response)
+	return t.inner.Send(response)
+}
+
+// SendBatch records and sends the responses to a batch. It fails if the
+// wrapped transport cannot carry batches, which the server never attempts.
+func (t *RecordingTransport) SendBatch(responses []*protocol.Response) error {
+	bt, ok := t.inner.(BatchTransport)
+	if !ok {
+		return fmt.Errorf("wrapped transport does not support batches")
+	}
+	for _, resp := range responses {
+		t.record(DirectionOut, resp)
+	}
+	return bt.SendBatch(responses)
+}
+
+func (t *RecordingTransport) SendNotification(notification *protocol.Notification) error {
+	t.record(DirectionOut, notification)
+	return t.inner.SendNotification(notification)
+}
+
+func (t *RecordingTransport) SendRequest(request *protocol.Request) error {
+	t.record(DirectionOut, request)
+	return t.inner.SendRequest(request)
+}
+
+func (t *RecordingTransport) Receive() <-chan *protocol.Request {
+	return t.requests
+}
+
+// ReceiveBatch returns the wrapped transport's batches, or nil, which never
+// delivers, if it has none
+func (t *RecordingTransport) ReceiveBatch() <-chan []*protocol.Request {
+	return t.batches
+}
+
+func (t *RecordingTransport) Responses() <-chan *protocol.Response {
+	return t.inner.Responses()
+}
+
+func (t *RecordingTransport) Errors() <-chan error {
+	return t.inner.Errors()
+}
+
+// forwardRequests records each request from the wrapped transport and
+// passes it on, including the nil that signals the end of input
+func (t *RecordingTransport) forwardRequests() {
+	for {
+		req, ok := <-t.inner.Receive()
+		if req != nil {
+			t.record(DirectionIn, req)
+		}
+		select {
+		case t.requests <- req:
+		case <-t.done:
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// forwardBatches records and passes on each batch; see forwardRequests
+func (t *RecordingTransport) forwardBatches(bt BatchTransport) {
+	for {
+		batch, ok := <-bt.ReceiveBatch()
+		for _, req := range batch {
+			t.record(DirectionIn, req)
+		}
+		select {
+		case t.batches <- batch:
+		case <-t.done:
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// record writes one line for message
+func (t *RecordingTransport) record(direction string, message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	line, err := json.Marshal(RecordedMessage{Direction: direction, Message: data})
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(append(line, '\n'))
+}
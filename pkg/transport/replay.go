@@ -0,0 +1,187 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// DefaultReplayResponseTimeout is how long a ReplayTransport waits for the
+// answer to one replayed request before moving on
+const DefaultReplayResponseTimeout = 5 * time.Second
+
+// ReplayTransport plays the client side of a recording made by
+// RecordingTransport against a server. Recorded requests are delivered in
+// order, each only once the previous one has been answered (or
+// ResponseTimeout has passed), so the server sees the client's exact
+// sequence; notifications are delivered without waiting. After the last
+// message the transport reports end of input, making Server.Run return.
+// What the server sends is collected for comparison with the recording.
+type ReplayTransport struct {
+	// ResponseTimeout bounds the wait for each answer; set it before Start.
+	// Zero means DefaultReplayResponseTimeout.
+	ResponseTimeout time.Duration
+
+	messages  []*protocol.Request
+	requests  chan *protocol.Request
+	responses chan *protocol.Response
+	errors    chan error
+	finished  chan struct{}
+	done      chan struct{}
+	stopOnce  sync.Once
+
+	mu            sync.Mutex
+	waiting       map[string]chan struct{}
+	sent          []*protocol.Response
+	notifications []*protocol.Notification
+}
+
+// Replay reads a recording and returns a transport that replays the
+// client's messages from it
+func Replay(r io.Reader) (*ReplayTransport, error) {
+	t := &ReplayTransport{
+		requests:  make(chan *protocol.Request),
+		responses: make(chan *protocol.Response),
+		errors:    make(chan error),
+		finished:  make(chan struct{}),
+		done:      make(chan struct{}),
+		waiting:   make(map[string]chan struct{}),
+	}
+	dec := json.NewDecoder(r)
+	for {
+		var entry RecordedMessage
+		if err := dec.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("read recording: %w", err)
+		}
+		if entry.Direction != DirectionIn {
+			continue
+		}
+		var req protocol.Request
+		if err := json.Unmarshal(entry.Message, &req); err != nil {
+			return nil, fmt.Errorf("read recorded request: %w", err)
+		}
+		t.messages = append(t.messages, &req)
+	}
+	return t, nil
+}
+
+func (t *ReplayTransport) Start(ctx context.Context) error {
+	go t.play(ctx)
+	return nil
+}
+
+func (t *ReplayTransport) Stop(ctx context.Context) error {
+	t.stopOnce.Do(func() { close(t.done) })
+	return nil
+}
+
+// Send collects a response and releases the replay waiting for it
+func (t *ReplayTransport) Send(response *protocol.Response) error {
+	key := idKey(response.ID)
+	t.mu.Lock()
+	t.sent = append(t.sent, response)
+	answered, ok := t.waiting[key]
+	delete(t.waiting, key)
+	t.mu.Unlock()
+	if ok {
+		close(answered)
+	}
+	return nil
+}
+
+func (t *ReplayTransport) SendNotification(notification *protocol.Notification) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notifications = append(t.notifications, notification)
+	return nil
+}
+
+// SendRequest accepts a server-initiated request; a replay has no client to
+// answer it
+func (t *ReplayTransport) SendRequest(request *protocol.Request) error {
+	return nil
+}
+
+func (t *ReplayTransport) Receive() <-chan *protocol.Request {
+	return t.requests
+}
+
+func (t *ReplayTransport) Responses() <-chan *protocol.Response {
+	return t.responses
+}
+
+func (t *ReplayTransport) Errors() <-chan error {
+	return t.errors
+}
+
+// Finished returns a channel closed once every recorded message has been
+// replayed and answered
+func (t *ReplayTransport) Finished() <-chan struct{} {
+	return t.finished
+}
+
+// Sent returns the responses the server sent, in order
+func (t *ReplayTransport) Sent() []*protocol.Response {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*protocol.Response(nil), t.sent...)
+}
+
+// Notifications returns the notifications the server sent, in order
+func (t *ReplayTransport) Notifications() []*protocol.Notification {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*protocol.Notification(nil), t.notifications...)
+}
+
+// play delivers the recorded messages, then end of input
+func (t *ReplayTransport) play(ctx context.Context) {
+	timeout := t.ResponseTimeout
+	if timeout == 0 {
+		timeout = DefaultReplayResponseTimeout
+	}
+	for _, req := range t.messages {
+		var answered chan struct{}
+		if !req.IsNotification() {
+			answered = make(chan struct{})
+			t.mu.Lock()
+			t.waiting[idKey(req.ID)] = answered
+			t.mu.Unlock()
+		}
+		if !t.deliver(ctx, req) {
+			return
+		}
+		if answered == nil {
+			continue
+		}
+		select {
+		case <-answered:
+		case <-time.After(timeout):
+		case <-ctx.Done():
+			return
+		case <-t.done:
+			return
+		}
+	}
+	close(t.finished)
+	t.deliver(ctx, nil)
+}
+
+// deliver hands req to the server, reporting false once the replay is over
+func (t *ReplayTransport) deliver(ctx context.Context, req *protocol.Request) bool {
+	select {
+	case t.requests <- req:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-t.done:
+		return false
+	}
+}
@@ -0,0 +1,222 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// SessionHeader carries the session ID the HTTP transport issues in its
+// reply to initialize. Clients send it back on every later request.
+const SessionHeader = "Mcp-Session-Id"
+
+type sessionIDKey struct{}
+
+// WithSessionID returns ctx carrying the ID of the session a request
+// arrived on.
+func WithSessionID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, id)
+}
+
+// SessionIDFromContext returns the session ID the transport attached to the
+// request, if any. Handlers can use it to tie work such as async operations
+// to the session, e.g. to cancel it from HTTPOptions.OnSessionClosed.
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(sessionIDKey{}).(string)
+	return id, ok
+}
+
+// httpSession tracks one client of an HTTPTransport. Its fields are guarded
+// by the transport's mu.
+type httpSession struct {
+	id       string
+	lastSeen time.Time
+	streams  int
+	closed   chan struct{}
+}
+
+// done returns a channel closed when the session is reaped; nil, which
+// never fires, for a transport without sessions
+func (s *httpSession) done() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.closed
+}
+
+func (t *HTTPTransport) sessionsEnabled() bool {
+	return t.options.SessionIdleTimeout > 0
+}
+
+// postSession resolves the session of a POSTed message and records the
+// activity. initialize without a session header starts a new session,
+// announced in the response header. It writes the HTTP error and returns
+// false when the message cannot be accepted. The session is nil when
+// sessions are disabled.
+func (t *HTTPTransport) postSession(w http.ResponseWriter, r *http.Request, req *protocol.Request) (*httpSession, bool) {
+	if !t.sessionsEnabled() {
+		return nil, true
+	}
+	id := r.Header.Get(SessionHeader)
+	if id == "" {
+		if req == nil || req.Method != protocol.MethodInitialize {
+			http.Error(w, "missing "+SessionHeader+" header", http.StatusBadRequest)
+			return nil, false
+		}
+		sess := t.newSession()
+		w.Header().Set(SessionHeader, sess.id)
+		return sess, true
+	}
+	return t.touchSession(w, id)
+}
+
+// streamSession resolves the session of a GET event stream request
+func (t *HTTPTransport) streamSession(w http.ResponseWriter, r *http.Request) (*httpSession, bool) {
+	if !t.sessionsEnabled() {
+		return nil, true
+	}
+	id := r.Header.Get(SessionHeader)
+	if id == "" {
+		http.Error(w, "missing "+SessionHeader+" header", http.StatusBadRequest)
+		return nil, false
+	}
+	return t.touchSession(w, id)
+}
+
+// newSession registers a session with a random ID
+func (t *HTTPTransport) newSession() *httpSession {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	sess := &httpSession{
+		id:       hex.EncodeToString(b[:]),
+		lastSeen: time.Now(),
+		closed:   make(chan struct{}),
+	}
+	t.mu.Lock()
+	t.sessions[sess.id] = sess
+	t.mu.Unlock()
+	return sess
+}
+
+// touchSession marks session id as active. Unknown or reaped sessions get
+// 404 Not Found, telling the client to initialize again.
+func (t *HTTPTransport) touchSession(w http.ResponseWriter, id string) (*httpSession, bool) {
+	t.mu.Lock()
+	sess, ok := t.sessions[id]
+	if ok {
+		sess.lastSeen = time.Now()
+	}
+	t.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown or expired session", http.StatusNotFound)
+		return nil, false
+	}
+	return sess, true
+}
+
+// reapSessions periodically closes sessions idle beyond SessionIdleTimeout
+// until the transport stops
+func (t *HTTPTransport) reapSessions() {
+	interval := t.options.SessionIdleTimeout / 2
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			t.reapIdle(now)
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// reapIdle closes every session with no open event stream whose last
+// request is older than SessionIdleTimeout, ending any state tied to it
+func (t *HTTPTransport) reapIdle(now time.Time) {
+	var reaped []string
+	t.mu.Lock()
+	for id, sess := range t.sessions {
+		if sess.streams == 0 && now.Sub(sess.lastSeen) > t.options.SessionIdleTimeout {
+			delete(t.sessions, id)
+			close(sess.closed)
+			reaped = append(reaped, id)
+		}
+	}
+	onClosed := t.onClosed
+	t.mu.Unlock()
+
+	for _, id := range reaped {
+		t.logger.Printf("transport: closed idle session %s", id)
+		for _, fn := range onClosed {
+			fn(id)
+		}
+		if t.options.OnSessionClosed != nil {
+			t.options.OnSessionClosed(id)
+		}
+	}
+}
+
+// NotifySessionClosed registers fn to be called, like
+// HTTPOptions.OnSessionClosed, with the ID of each session closed for being
+// idle. The server uses it to cancel the session's async operations.
+func (t *HTTPTransport) NotifySessionClosed(fn func(sessionID string)) {
+	t.mu.Lock()
+	t.onClosed = append(t.onClosed, fn)
+	t.mu.Unlock()
+}
+
+// Sessions returns the IDs of the open sessions, sorted. It is empty when
+// sessions are disabled.
+func (t *HTTPTransport) Sessions() []string {
+	t.mu.RLock()
+	ids := make([]string, 0, len(t.sessions))
+	for id := range t.sessions {
+		ids = append(ids, id)
+	}
+	t.mu.RUnlock()
+	sort.Strings(ids)
+	return ids
+}
+
+// SupportsStreamingTo reports whether session sessionID has an event stream
+// open to receive notifications ahead of a response
+func (t *HTTPTransport) SupportsStreamingTo(sessionID string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	sess, ok := t.sessions[sessionID]
+	return ok && !t.isClosed && sess.streams > 0
+}
+
+// SendNotificationTo pushes a notification to the event streams of session
+// sessionID only
+func (t *HTTPTransport) SendNotificationTo(sessionID string, notification *protocol.Notification) error {
+	return t.publishTo(sessionID, notification)
+}
+
+// SendRequestTo pushes a server-initiated request to the event streams of
+// session sessionID only
+func (t *HTTPTransport) SendRequestTo(sessionID string, request *protocol.Request) error {
+	return t.publishTo(sessionID, request)
+}
+
+// publishTo publishes message to sessionID, failing for an unknown session
+func (t *HTTPTransport) publishTo(sessionID string, message interface{}) error {
+	t.mu.RLock()
+	_, ok := t.sessions[sessionID]
+	t.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown session %q", sessionID)
+	}
+	return t.publish(message, func(sess *httpSession) bool {
+		return sess != nil && sess.id == sessionID
+	})
+}
@@ -0,0 +1,301 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomcpgo/mcp/pkg/protocol"
+)
+
+// postSession POSTs body with the given session header and returns the
+// response status and session header
+func postSession(t *testing.T, url, session, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if session != "" {
+		req.Header.Set(SessionHeader, session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get(SessionHeader)
+}
+
+func TestHTTPIdleSessionIsClosed(t *testing.T) {
+	closed := make(chan string, 4)
+	transport := NewHTTPTransport(HTTPOptions{
+		SessionIdleTimeout: 100 * time.Millisecond,
+		OnSessionClosed:    func(id string) { closed <- id },
+	})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	// Play the server: answer every request, recording its session
+	sessions := make(chan string, 64)
+	go func() {
+		for req := range transport.Receive() {
+			id, _ := SessionIDFromContext(req.Context())
+			sessions <- id
+			transport.Send(&protocol.Response{JSONRPC: "2.0", ID: req.ID, Result: struct{}{}})
+		}
+	}()
+
+	const initialize = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+	_, idle := postSession(t, ts.URL, "", initialize)
+	_, active := postSession(t, ts.URL, "", initialize)
+	if idle == "" || active == "" || idle == active {
+		t.Fatalf("session IDs %q and %q, want two distinct IDs", idle, active)
+	}
+	if got := <-sessions; got != idle {
+		t.Errorf("handler saw session %q, want %q", got, idle)
+	}
+
+	if status, _ := postSession(t, ts.URL, "", `{"jsonrpc":"2.0","id":2,"method":"ping"}`); status != http.StatusBadRequest {
+		t.Errorf("request without a session: status %d, want 400", status)
+	}
+
+	// Keep one session busy well past the idle timeout
+	deadline := time.Now().Add(400 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if status, _ := postSession(t, ts.URL, active, `{"jsonrpc":"2.0","id":3,"method":"ping"}`); status != http.StatusOK {
+			t.Fatalf("active session ping: status %d, want 200", status)
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	select {
+	case id := <-closed:
+		if id != idle {
+			t.Errorf("closed session %q, want the idle %q", id, idle)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("idle session was never closed")
+	}
+	select {
+	case id := <-closed:
+		t.Errorf("active session %q was closed too", id)
+	default:
+	}
+
+	if status, _ := postSession(t, ts.URL, idle, `{"jsonrpc":"2.0","id":4,"method":"ping"}`); status != http.StatusNotFound {
+		t.Errorf("closed session: status %d, want 404", status)
+	}
+}
+
+// openSessionStream opens the event stream of session and returns its data
+// lines
+func openSessionStream(t *testing.T, ctx context.Context, url, session string) <-chan string {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req.Header.Set(SessionHeader, session)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET: status %d", resp.StatusCode)
+	}
+	data := make(chan string, 16)
+	go func() {
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				data <- line
+			}
+		}
+	}()
+	return data
+}
+
+func TestHTTPSessionsAreIsolated(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{SessionIdleTimeout: time.Minute})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	// Play the server: answer every request with the session it came from
+	go func() {
+		for req := range transport.Receive() {
+			id, _ := SessionIDFromContext(req.Context())
+			resp := &protocol.Response{JSONRPC: "2.0", ID: req.ID, Result: id}
+			transport.Send(resp.WithContext(req.Context()))
+		}
+	}()
+
+	const initialize = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+	_, a := postSession(t, ts.URL, "", initialize)
+	_, b := postSession(t, ts.URL, "", initialize)
+	if got := transport.Sessions(); len(got) != 2 {
+		t.Fatalf("Sessions() = %v, want 2 sessions", got)
+	}
+
+	// Both sessions use the same request id at once; each gets its own reply
+	var wg sync.WaitGroup
+	results := make(map[string]string)
+	var mu sync.Mutex
+	for _, session := range []string{a, b} {
+		wg.Add(1)
+		go func(session string) {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":7,"method":"ping"}`))
+			req.Header.Set(SessionHeader, session)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Errorf("POST: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+			var got protocol.Response
+			json.NewDecoder(resp.Body).Decode(&got)
+			mu.Lock()
+			results[session], _ = got.Result.(string)
+			mu.Unlock()
+		}(session)
+	}
+	wg.Wait()
+	for _, session := range []string{a, b} {
+		if results[session] != session {
+			t.Errorf("session %s got the reply for %q", session, results[session])
+		}
+	}
+
+	// A notification for one session reaches only its stream
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	streamA := openSessionStream(t, ctx, ts.URL, a)
+	streamB := openSessionStream(t, ctx, ts.URL, b)
+
+	notif, _ := protocol.NewNotification("notifications/message", map[string]string{"to": "a"})
+	if err := transport.SendNotificationTo(a, notif); err != nil {
+		t.Fatalf("SendNotificationTo: %v", err)
+	}
+	select {
+	case line := <-streamA:
+		if !strings.Contains(line, `"to":"a"`) {
+			t.Errorf("session a got %s", line)
+		}
+	case <-ctx.Done():
+		t.Fatal("session a never got its notification")
+	}
+	select {
+	case line := <-streamB:
+		t.Errorf("session b got another session's notification: %s", line)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := transport.SendNotificationTo("no-such-session", notif); err == nil {
+		t.Error("expected an error for an unknown session")
+	}
+}
+
+// Sessions answering the same ids concurrently, with an inline-replying
+// consumer and idle sessions being reaped meanwhile, each get their own
+// replies and never stall.
+func TestHTTPSessionsConcurrentInlineReplies(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{SessionIdleTimeout: 200 * time.Millisecond})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	go func() {
+		for req := range transport.Receive() {
+			id, _ := SessionIDFromContext(req.Context())
+			resp := &protocol.Response{JSONRPC: "2.0", ID: req.ID, Result: id}
+			transport.Send(resp.WithContext(req.Context()))
+		}
+	}()
+
+	const initialize = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+	var sessions []string
+	for i := 0; i < 6; i++ {
+		_, id := postSession(t, ts.URL, "", initialize)
+		sessions = append(sessions, id)
+	}
+
+	// Half the sessions go idle and are reaped while the others keep working
+	active := sessions[:3]
+	errs := make(chan error, len(active))
+	for _, session := range active {
+		go func(session string) {
+			for i := 0; i < 30; i++ {
+				req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"ping"}`, i)))
+				req.Header.Set(SessionHeader, session)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					errs <- err
+					return
+				}
+				var got protocol.Response
+				json.NewDecoder(resp.Body).Decode(&got)
+				resp.Body.Close()
+				if got.Result != session {
+					errs <- fmt.Errorf("session %s got the reply for %v", session, got.Result)
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			errs <- nil
+		}(session)
+	}
+	for range active {
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Error(err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("sessions stalled")
+		}
+	}
+	if got := transport.Sessions(); len(got) != len(active) {
+		t.Errorf("Sessions() = %v, want only the %d active sessions", got, len(active))
+	}
+}
+
+func TestHTTPSupportsStreamingToOnlySessionWithStream(t *testing.T) {
+	transport := NewHTTPTransport(HTTPOptions{SessionIdleTimeout: time.Minute})
+	ts := httptest.NewServer(transport)
+	defer ts.Close()
+	defer transport.Stop(context.Background())
+
+	go func() {
+		for req := range transport.Receive() {
+			resp := &protocol.Response{JSONRPC: "2.0", ID: req.ID, Result: struct{}{}}
+			transport.Send(resp.WithContext(req.Context()))
+		}
+	}()
+
+	const initialize = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+	_, listening := postSession(t, ts.URL, "", initialize)
+	_, silent := postSession(t, ts.URL, "", initialize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	openSessionStream(t, ctx, ts.URL, listening)
+
+	if !transport.SupportsStreamingTo(listening) {
+		t.Error("session with an open stream should support streaming")
+	}
+	if transport.SupportsStreamingTo(silent) {
+		t.Error("session without a stream should not support streaming")
+	}
+	if transport.SupportsStreamingTo("unknown") {
+		t.Error("unknown session should not support streaming")
+	}
+}
@@ -2,6 +2,7 @@ package transport
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -17,21 +18,63 @@ type StdioTransport struct {
 	encoder   *json.Encoder
 	reader    *bufio.Reader
 	requests  chan *protocol.Request
+	batches   chan []*protocol.Request
 	responses chan *protocol.Response
 	errors    chan error
 	done      chan struct{}
 	mu        sync.RWMutex
 	isClosed  bool
+	logger    *log.Logger
+
+	// pushers counts deliveries in flight to the channels above; Stop
+	// waits for them before closing the channels
+	pushers sync.WaitGroup
+}
+
+// StdioOption configures a StdioTransport
+type StdioOption func(*stdioConfig)
+
+type stdioConfig struct {
+	requestBuffer int
+	logger        *log.Logger
+}
+
+// WithRequestChannelBuffer lets the read loop decode up to n requests ahead
+// of the server instead of waiting for each one to be picked up, which helps
+// throughput on bursty input. Requests still queued when the transport stops
+// remain readable from Receive until the channel is drained.
+func WithRequestChannelBuffer(n int) StdioOption {
+	return func(c *stdioConfig) {
+		c.requestBuffer = n
+	}
+}
+
+// WithTransportLogger sends the transport's diagnostics, such as messages
+// it could not decode, to logger. By default the transport logs nothing.
+func WithTransportLogger(logger *log.Logger) StdioOption {
+	return func(c *stdioConfig) {
+		c.logger = logger
+	}
 }
 
-func NewStdioTransport() *StdioTransport {
+func NewStdioTransport(opts ...StdioOption) *StdioTransport {
+	var config stdioConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.requestBuffer < 0 {
+		config.requestBuffer = 0
+	}
+
 	return &StdioTransport{
 		encoder:   json.NewEncoder(os.Stdout),
 		reader:    bufio.NewReader(os.Stdin),
-		requests:  make(chan *protocol.Request),
+		requests:  make(chan *protocol.Request, config.requestBuffer),
+		batches:   make(chan []*protocol.Request),
 		responses: make(chan *protocol.Response),
 		errors:    make(chan error),
 		done:      make(chan struct{}),
+		logger:    loggerOrDiscard(config.logger),
 	}
 }
 
@@ -42,15 +85,21 @@ func (t *StdioTransport) Start(ctx context.Context) error {
 
 func (t *StdioTransport) Stop(_ context.Context) error {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-
-	if !t.isClosed {
-		t.isClosed = true
-		close(t.done)
-		close(t.requests)
-		close(t.responses)
-		close(t.errors)
+	if t.isClosed {
+		t.mu.Unlock()
+		return nil
 	}
+	t.isClosed = true
+	close(t.done)
+	t.mu.Unlock()
+
+	// Deliveries in flight give up on done; only then is it safe to close
+	// the channels they send on.
+	t.pushers.Wait()
+	close(t.requests)
+	close(t.batches)
+	close(t.responses)
+	close(t.errors)
 	return nil
 }
 
@@ -65,6 +114,18 @@ func (t *StdioTransport) Send(response *protocol.Response) error {
 	return t.encoder.Encode(response)
 }
 
+// SendBatch writes the responses to a batch as a single JSON array.
+func (t *StdioTransport) SendBatch(responses []*protocol.Response) error {
+	t.mu.RLock()
+	if t.isClosed {
+		t.mu.RUnlock()
+		return fmt.Errorf("transport is closed")
+	}
+	t.mu.RUnlock()
+
+	return t.encoder.Encode(responses)
+}
+
 func (t *StdioTransport) SendNotification(notification *protocol.Notification) error {
 	t.mu.RLock()
 	if t.isClosed {
@@ -91,6 +152,10 @@ func (t *StdioTransport) Receive() <-chan *protocol.Request {
 	return t.requests
 }
 
+func (t *StdioTransport) ReceiveBatch() <-chan []*protocol.Request {
+	return t.batches
+}
+
 func (t *StdioTransport) Responses() <-chan *protocol.Response {
 	return t.responses
 }
@@ -105,7 +170,8 @@ func (t *StdioTransport) Errors() <-chan error {
 // marks it as a response bound for the responses channel. Routing by shape
 // rather than structural heuristics keeps us spec-faithful: a well-formed
 // response never carries a method, and a well-formed request/notification
-// always does.
+// always does. A top-level JSON array is a batch: its requests are
+// delivered together on the batches channel.
 func (t *StdioTransport) readLoop(ctx context.Context) {
 	defer t.Stop(ctx)
 
@@ -139,69 +205,199 @@ func (t *StdioTransport) readLoop(ctx context.Context) {
 			continue
 		}
 
-		// Peek at the keys to decide routing. Using a small envelope
-		// avoids a full decode-and-reflect.
-		var peek struct {
-			JSONRPC string          `json:"jsonrpc"`
-			Method  *string         `json:"method,omitempty"`
-			Result  json.RawMessage `json:"result,omitempty"`
-			Error   json.RawMessage `json:"error,omitempty"`
-		}
-		if err := json.Unmarshal(raw, &peek); err != nil {
-			t.sendError(ctx, fmt.Errorf("decode envelope: %w", err))
-			continue
-		}
-
-		if peek.JSONRPC != "2.0" {
-			t.sendError(ctx, fmt.Errorf("invalid JSON-RPC version: %s", peek.JSONRPC))
-			continue
-		}
-
-		if peek.Method != nil {
-			var request protocol.Request
-			if err := json.Unmarshal(raw, &request); err != nil {
-				t.sendError(ctx, fmt.Errorf("decode request: %w", err))
+		if isBatch(raw) {
+			var entries []json.RawMessage
+			if err := json.Unmarshal(raw, &entries); err != nil {
+				t.sendError(ctx, fmt.Errorf("decode batch: %w", err))
 				continue
 			}
-			select {
-			case t.requests <- &request:
-			case <-ctx.Done():
-				return
-			case <-t.done:
+			var batch []*protocol.Request
+			responses := 0
+			for _, entry := range entries {
+				req, resp, err := decodeMessage(entry)
+				if err != nil {
+					t.sendError(ctx, err)
+					continue
+				}
+				if req != nil {
+					batch = append(batch, req)
+					continue
+				}
+				if !t.deliverResponse(ctx, resp) {
+					return
+				}
+				responses++
+			}
+			if len(batch) == 0 {
+				// An empty batch, or one with nothing usable in it, is
+				// itself an invalid request and gets a single error back.
+				if responses == 0 {
+					t.sendInvalidBatch()
+				}
+				continue
+			}
+			if !t.deliverBatch(ctx, batch) {
 				return
 			}
 			continue
 		}
 
-		// No method → response (must have result or error).
-		if len(peek.Result) == 0 && len(peek.Error) == 0 {
-			t.sendError(ctx, fmt.Errorf("message has no method, result, or error"))
+		req, resp, err := decodeMessage(raw)
+		if err != nil {
+			t.sendError(ctx, err)
 			continue
 		}
 
-		var response protocol.Response
-		if err := json.Unmarshal(raw, &response); err != nil {
-			t.sendError(ctx, fmt.Errorf("decode response: %w", err))
+		if req != nil {
+			if !t.deliverRequest(ctx, req) {
+				return
+			}
 			continue
 		}
-		select {
-		case t.responses <- &response:
-		case <-ctx.Done():
-			return
-		case <-t.done:
+
+		if !t.deliverResponse(ctx, resp) {
 			return
 		}
 	}
 }
 
+// startPush registers a delivery on one of the transport's channels, or
+// returns false once the transport is closed. The caller must call
+// t.pushers.Done.
+func (t *StdioTransport) startPush() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.isClosed {
+		return false
+	}
+	t.pushers.Add(1)
+	return true
+}
+
+// deliverRequest pushes req onto the requests channel. It returns false
+// when the transport is shutting down.
+func (t *StdioTransport) deliverRequest(ctx context.Context, req *protocol.Request) bool {
+	if !t.startPush() {
+		return false
+	}
+	defer t.pushers.Done()
+	select {
+	case t.requests <- req:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-t.done:
+		return false
+	}
+}
+
+// deliverBatch pushes batch onto the batches channel. It returns false when
+// the transport is shutting down.
+func (t *StdioTransport) deliverBatch(ctx context.Context, batch []*protocol.Request) bool {
+	if !t.startPush() {
+		return false
+	}
+	defer t.pushers.Done()
+	select {
+	case t.batches <- batch:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-t.done:
+		return false
+	}
+}
+
+// deliverResponse pushes resp onto the responses channel. It returns false
+// when the transport is shutting down.
+func (t *StdioTransport) deliverResponse(ctx context.Context, resp *protocol.Response) bool {
+	if !t.startPush() {
+		return false
+	}
+	defer t.pushers.Done()
+	select {
+	case t.responses <- resp:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-t.done:
+		return false
+	}
+}
+
+// isBatch reports whether raw is a JSON array, i.e. a JSON-RPC batch.
+func isBatch(raw json.RawMessage) bool {
+	trimmed := bytes.TrimLeft(raw, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// decodeMessage decodes a single JSON-RPC message, returning either a request
+// (or notification) or a response depending on its shape.
+func decodeMessage(raw json.RawMessage) (*protocol.Request, *protocol.Response, error) {
+	// Peek at the keys to decide routing. Using a small envelope
+	// avoids a full decode-and-reflect.
+	var peek struct {
+		JSONRPC string          `json:"jsonrpc"`
+		Method  *string         `json:"method,omitempty"`
+		Result  json.RawMessage `json:"result,omitempty"`
+		Error   json.RawMessage `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &peek); err != nil {
+		return nil, nil, fmt.Errorf("decode envelope: %w", err)
+	}
+
+	if peek.JSONRPC != "2.0" {
+		return nil, nil, fmt.Errorf("invalid JSON-RPC version: %s", peek.JSONRPC)
+	}
+
+	if peek.Method != nil {
+		var request protocol.Request
+		if err := json.Unmarshal(raw, &request); err != nil {
+			return nil, nil, fmt.Errorf("decode request: %w", err)
+		}
+		return &request, nil, nil
+	}
+
+	// No method → response (must have result or error).
+	if len(peek.Result) == 0 && len(peek.Error) == 0 {
+		return nil, nil, fmt.Errorf("message has no method, result, or error")
+	}
+
+	var response protocol.Response
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, nil, fmt.Errorf("decode response: %w", err)
+	}
+	return nil, &response, nil
+}
+
 // sendError pushes err onto the errors channel if a receiver is ready,
 // otherwise logs it. Mirrors the prior readLoop's behaviour.
 func (t *StdioTransport) sendError(ctx context.Context, err error) {
+	if !t.startPush() {
+		return
+	}
+	defer t.pushers.Done()
 	select {
 	case t.errors <- err:
 	case <-ctx.Done():
 	case <-t.done:
 	default:
-		log.Printf("transport: %v", err)
+		t.logger.Printf("transport: %v", err)
+	}
+}
+
+// sendInvalidBatch answers a batch that contained no valid message with the
+// single Invalid Request error JSON-RPC prescribes for it.
+func (t *StdioTransport) sendInvalidBatch() {
+	resp := &protocol.Response{
+		JSONRPC: "2.0",
+		ID:      nil,
+		Error: &protocol.Error{
+			Code:    protocol.InvalidRequest,
+			Message: "invalid request: batch contains no valid messages",
+		},
+	}
+	if err := t.Send(resp); err != nil {
+		t.logger.Printf("transport: reply to invalid batch: %v", err)
 	}
 }
@@ -345,3 +345,170 @@ func TestContextCancellation(t *testing.T) {
 		t.Error("Send() should return error after context cancellation")
 	}
 }
+
+func TestStdioRoutesBatchToBatchesChannel(t *testing.T) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	oldStdin := os.Stdin
+	os.Stdin = pr
+	defer func() { os.Stdin = oldStdin }()
+
+	transport := NewStdioTransport()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := transport.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	batch := `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]` + "\n"
+	if _, err := pw.WriteString(batch); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	select {
+	case got := <-transport.ReceiveBatch():
+		if len(got) != 3 {
+			t.Fatalf("batch len = %d, want 3", len(got))
+		}
+		for i, want := range []string{"ping", "notifications/initialized", "tools/list"} {
+			if got[i].Method != want {
+				t.Errorf("batch[%d].Method = %q, want %q", i, got[i].Method, want)
+			}
+		}
+	case req := <-transport.Receive():
+		t.Fatalf("batch entry was routed to requests channel: %+v", req)
+	case err := <-transport.Errors():
+		t.Fatalf("got error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for batch")
+	}
+}
+
+func TestStdioInvalidBatchGetsSingleError(t *testing.T) {
+	tests := []struct {
+		name  string
+		batch string
+	}{
+		{"empty", `[]`},
+		{"no valid members", `[1,{"foo":"bar"},"x"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inR, inW, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("pipe: %v", err)
+			}
+			defer inW.Close()
+			outR, outW, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("pipe: %v", err)
+			}
+			defer outR.Close()
+			oldStdin, oldStdout := os.Stdin, os.Stdout
+			os.Stdin, os.Stdout = inR, outW
+			transport := NewStdioTransport()
+			os.Stdin, os.Stdout = oldStdin, oldStdout
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if err := transport.Start(ctx); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			defer transport.Stop(ctx)
+
+			// A valid request after the batch shows the loop carried on
+			// and bounds how far the output needs to be read.
+			input := tt.batch + "\n" + `{"jsonrpc":"2.0","id":7,"method":"ping"}` + "\n"
+			if _, err := inW.WriteString(input); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			select {
+			case req := <-transport.Receive():
+				if req.Method != "ping" {
+					t.Fatalf("received %q, want ping", req.Method)
+				}
+			case <-transport.ReceiveBatch():
+				t.Fatal("invalid batch was delivered")
+			case <-time.After(time.Second):
+				t.Fatal("timeout waiting for the request after the batch")
+			}
+			outW.Close()
+
+			var got []json.RawMessage
+			dec := json.NewDecoder(outR)
+			for {
+				var msg json.RawMessage
+				if err := dec.Decode(&msg); err != nil {
+					break
+				}
+				got = append(got, msg)
+			}
+			if len(got) != 1 {
+				t.Fatalf("wrote %d messages, want 1: %s", len(got), got)
+			}
+			var resp protocol.Response
+			if err := json.Unmarshal(got[0], &resp); err != nil {
+				t.Fatalf("reply is not a single response: %s", got[0])
+			}
+			if resp.ID != nil {
+				t.Errorf("ID = %v, want null", resp.ID)
+			}
+			if resp.Error == nil || resp.Error.Code != protocol.InvalidRequest {
+				t.Errorf("Error = %+v, want code %d", resp.Error, protocol.InvalidRequest)
+			}
+		})
+	}
+}
+
+func TestStdioRequestChannelBuffer(t *testing.T) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	defer pw.Close()
+	oldStdin := os.Stdin
+	os.Stdin = pr
+	defer func() { os.Stdin = oldStdin }()
+
+	transport := NewStdioTransport(WithRequestChannelBuffer(4))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := transport.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	enc := json.NewEncoder(pw)
+	for id := 1; id <= 3; id++ {
+		if err := enc.Encode(protocol.Request{JSONRPC: "2.0", ID: id, Method: "ping"}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}
+
+	// Nothing has been consumed yet, but the read loop should have decoded
+	// all three requests into the buffer.
+	deadline := time.Now().Add(time.Second)
+	for len(transport.requests) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := len(transport.requests); n != 3 {
+		t.Fatalf("buffered requests = %d, want 3", n)
+	}
+
+	// Stopping keeps the queued requests readable, then reports closure.
+	transport.Stop(ctx)
+	for want := 1; want <= 3; want++ {
+		req, ok := <-transport.Receive()
+		if !ok {
+			t.Fatalf("channel closed before request %d was drained", want)
+		}
+		if fmtID(req.ID) != fmtID(want) {
+			t.Errorf("request id = %v, want %d", req.ID, want)
+		}
+	}
+	if _, ok := <-transport.Receive(); ok {
+		t.Error("channel should be closed once drained")
+	}
+}
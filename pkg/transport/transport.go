@@ -2,6 +2,9 @@ package transport
 
 import (
 	"context"
+	"errors"
+	"io"
+	"log"
 
 	"github.com/gomcpgo/mcp/pkg/protocol"
 )
@@ -38,6 +41,93 @@ type Transport interface {
 	Errors() <-chan error
 }
 
+// BatchTransport is implemented by transports that can carry JSON-RPC
+// batches. A batch arrives as a single slice and its replies must go back
+// as a single array.
+type BatchTransport interface {
+	Transport
+
+	// ReceiveBatch returns a channel that provides incoming batches. Each
+	// slice holds the requests and notifications of one batch, in the order
+	// the client sent them.
+	ReceiveBatch() <-chan []*protocol.Request
+
+	// SendBatch sends the responses to a batch as one JSON array.
+	SendBatch(responses []*protocol.Response) error
+}
+
+// SessionManager is implemented by transports serving several clients at
+// once, each in its own session. Requests carry their session ID in their
+// context (see SessionIDFromContext), responses are routed back by the
+// context the server attaches to them, and server-initiated messages can be
+// aimed at one session instead of every client.
+type SessionManager interface {
+	// Sessions returns the IDs of the open sessions
+	Sessions() []string
+
+	// SendNotificationTo sends a notification to one session only
+	SendNotificationTo(sessionID string, notification *protocol.Notification) error
+
+	// SendRequestTo sends a server-initiated request to one session only
+	SendRequestTo(sessionID string, request *protocol.Request) error
+}
+
+// SessionCloseNotifier is implemented by session transports that can tell
+// the server when they close a session, so it can release state tied to it
+// such as the session's async operations.
+type SessionCloseNotifier interface {
+	// NotifySessionClosed registers fn to be called with the ID of each
+	// session the transport closes
+	NotifySessionClosed(fn func(sessionID string))
+}
+
+// StreamingTransport is implemented by transports that can deliver
+// server-initiated messages to the client while a request is still being
+// handled, such as over an HTTP event stream.
+type StreamingTransport interface {
+	Transport
+
+	// SupportsStreaming reports whether a notification sent now reaches the
+	// client before the response to the request in flight
+	SupportsStreaming() bool
+}
+
+// SessionStreamingTransport is implemented by multi-client streaming
+// transports that can tell whether one session in particular would receive
+// a notification sent now.
+type SessionStreamingTransport interface {
+	StreamingTransport
+
+	// SupportsStreamingTo is SupportsStreaming for session sessionID only
+	SupportsStreamingTo(sessionID string) bool
+}
+
+// Retryable marks err, returned from a send, as a transient failure that
+// wrote nothing to the client, so sending the same message again can
+// neither duplicate nor corrupt it. The server only retries sends that fail
+// this way. Writes to a byte stream such as stdout must never be marked: a
+// partial line cannot be taken back.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return retryableError{err}
+}
+
+// IsRetryable reports whether err, or an error it wraps, was marked with
+// Retryable
+func IsRetryable(err error) bool {
+	var r retryableError
+	return errors.As(err, &r)
+}
+
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
 // Options holds configuration for transports
 type Options struct {
 	// Add common transport options here
@@ -49,4 +139,14 @@ type TransportType string
 const (
 	TypeStdio TransportType = "stdio"
 	TypeSSE   TransportType = "sse"
+	TypeHTTP  TransportType = "http"
 )
+
+// loggerOrDiscard returns logger, or a logger that discards everything when
+// it is nil, so transports stay silent unless given one.
+func loggerOrDiscard(logger *log.Logger) *log.Logger {
+	if logger == nil {
+		return log.New(io.Discard, "", 0)
+	}
+	return logger
+}